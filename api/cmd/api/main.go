@@ -38,7 +38,10 @@ func main() {
 	logger.Info("Database connection established")
 
 	// Initialize router
-	r := router.New(cfg, db, logger)
+	r, err := router.New(cfg, db, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize router", "error", err)
+	}
 
 	// If -routes flag is set, show routes and exit
 	if *showRoutes {
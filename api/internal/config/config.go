@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -15,6 +16,11 @@ type Config struct {
 	AppEnv  string
 	AppPort string
 	AppURL  string
+	AppKey  string
+
+	// Frontends (used to build links in emails)
+	AdminAppURL string
+	StoreAppURL string
 
 	// Database
 	DBHost            string
@@ -28,10 +34,84 @@ type Config struct {
 	DBConnMaxLifetime time.Duration
 
 	// Session
-	SessionLifetime time.Duration
+	SessionLifetime time.Duration // Default absolute lifetime
 	SessionSecure   bool
 	SessionDomain   string
 
+	// Keys used to hash session tokens at rest; the first one signs new sessions,
+	// the rest are only accepted so keys can be rotated without logging everyone out
+	SessionTokenKeys []string
+
+	// Session policies per audience: idle timeout since last activity and absolute lifetime since login
+	AdminSessionIdleTimeout    time.Duration
+	AdminSessionLifetime       time.Duration
+	CashierSessionIdleTimeout  time.Duration
+	CashierSessionLifetime     time.Duration
+	CustomerSessionIdleTimeout time.Duration
+	CustomerSessionLifetime    time.Duration
+
+	// Lifetime of a customer session started by an admin impersonating the customer
+	ImpersonationLifetime time.Duration
+
+	// Accept "Authorization: Bearer <token>" next to the session cookie
+	AdminBearerTokens    bool
+	CustomerBearerTokens bool
+
+	// Two-Factor Authentication
+	TwoFactorIssuer            string
+	TwoFactorChallengeLifetime time.Duration
+
+	// Passkeys (WebAuthn) for admins
+	WebAuthnRPID              string // Domain the passkeys are bound to, e.g. admin.susano.id
+	WebAuthnRPName            string
+	WebAuthnOrigins           []string // Origins allowed to use the passkeys, defaults to the admin app URL
+	WebAuthnChallengeLifetime time.Duration
+
+	// Social login (OpenID Connect) for customers
+	OIDCProviders     []OIDCProvider
+	OIDCStateLifetime time.Duration // Time a customer has to complete the login at the provider
+
+	// API keys for admin integrations
+	APIKeyRateLimit   int           // Default requests per minute per key
+	APIKeyMaxLifetime time.Duration // Longest time a key may stay valid
+
+	// Login Throttling
+	LoginMaxAttempts      int           // Failed logins before an account is locked
+	LoginLockoutDuration  time.Duration // How long a locked account stays locked
+	LoginAttemptWindow    time.Duration // How far back failed logins are counted
+	LoginBaseDelay        time.Duration // Delay after the first failure, doubled after each one
+	LoginMaxDelay         time.Duration
+	LoginMaxAttemptsPerIP int // Failed logins per IP within the window, across all accounts
+
+	// Password Reset
+	PasswordResetLifetime time.Duration
+
+	// Password Policy, enforced whenever a password is set
+	PasswordMinLength        int
+	PasswordRequireUppercase bool
+	PasswordRequireLowercase bool
+	PasswordRequireDigit     bool
+	PasswordRequireSymbol    bool
+	PasswordHistory          int    // Number of previous passwords that cannot be reused
+	PasswordBreachListPath   string // Directory of breached password range files; empty disables the check
+
+	// Email Verification
+	EmailVerificationLifetime       time.Duration
+	EmailVerificationResendInterval time.Duration
+
+	// Magic Link Login
+	MagicLinkLifetime        time.Duration
+	MagicLinkRequestInterval time.Duration // Minimum time between links sent to one email
+
+	// Inventory
+	StockReservationLifetime time.Duration // How long a checkout holds reserved stock
+
+	// Mail
+	MailDriver      string
+	MailFromAddress string
+	MailFromName    string
+	MailFilePath    string
+
 	// CORS
 	CORSAllowedOrigins []string
 
@@ -39,6 +119,9 @@ type Config struct {
 	RateLimitRequests int
 	RateLimitWindow   time.Duration
 
+	// Proxies
+	TrustedProxies []string // IP addresses or CIDR ranges whose X-Forwarded-For and X-Real-IP headers are honoured
+
 	// Logging
 	LogLevel      string
 	LogMaxSize    int
@@ -47,16 +130,33 @@ type Config struct {
 	LogCompress   bool
 }
 
+// OIDCProvider configures an OpenID Connect identity provider customers can log in with
+type OIDCProvider struct {
+	Name         string // Used in URLs and stored with linked identities, e.g. google
+	Issuer       string // Discovery is done at <issuer>/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	RedirectURL  string // Store app page the provider sends the customer back to
+}
+
 // Load reads configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if exists (silently ignore if not found)
 	_ = godotenv.Load()
 
+	sessionLifetime := getEnvAsDuration("SESSION_LIFETIME", 720*time.Hour) // 30 days
+
 	cfg := &Config{
 		// Application
 		AppEnv:  getEnv("APP_ENV", "development"),
 		AppPort: getEnv("APP_PORT", "8080"),
 		AppURL:  getEnv("APP_URL", "http://localhost:8080"),
+		AppKey:  getEnv("APP_KEY", ""),
+
+		// Frontends
+		AdminAppURL: getEnv("ADMIN_APP_URL", "http://localhost:3001"),
+		StoreAppURL: getEnv("STORE_APP_URL", "http://localhost:3000"),
 
 		// Database
 		DBHost:            getEnv("DB_HOST", "localhost"),
@@ -70,10 +170,78 @@ func Load() (*Config, error) {
 		DBConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 
 		// Session
-		SessionLifetime: getEnvAsDuration("SESSION_LIFETIME", 720*time.Hour), // 30 days
+		SessionLifetime: sessionLifetime,
 		SessionSecure:   getEnvAsBool("SESSION_SECURE", false),
 		SessionDomain:   getEnv("SESSION_DOMAIN", "localhost"),
 
+		SessionTokenKeys: getEnvAsSlice("SESSION_TOKEN_KEYS", nil),
+
+		AdminSessionIdleTimeout:    getEnvAsDuration("ADMIN_SESSION_IDLE_TIMEOUT", 2*time.Hour),
+		AdminSessionLifetime:       getEnvAsDuration("ADMIN_SESSION_LIFETIME", sessionLifetime),
+		CashierSessionIdleTimeout:  getEnvAsDuration("CASHIER_SESSION_IDLE_TIMEOUT", 10*time.Minute),
+		CashierSessionLifetime:     getEnvAsDuration("CASHIER_SESSION_LIFETIME", 12*time.Hour),
+		CustomerSessionIdleTimeout: getEnvAsDuration("CUSTOMER_SESSION_IDLE_TIMEOUT", 336*time.Hour), // 14 days
+		CustomerSessionLifetime:    getEnvAsDuration("CUSTOMER_SESSION_LIFETIME", sessionLifetime),
+
+		ImpersonationLifetime: getEnvAsDuration("IMPERSONATION_LIFETIME", 30*time.Minute),
+
+		AdminBearerTokens:    getEnvAsBool("ADMIN_BEARER_TOKENS", true),
+		CustomerBearerTokens: getEnvAsBool("CUSTOMER_BEARER_TOKENS", true),
+
+		// Two-Factor Authentication
+		TwoFactorIssuer:            getEnv("TWO_FACTOR_ISSUER", "Susano"),
+		TwoFactorChallengeLifetime: getEnvAsDuration("TWO_FACTOR_CHALLENGE_LIFETIME", 5*time.Minute),
+
+		// Passkeys (WebAuthn) for admins
+		WebAuthnRPID:              getEnv("WEBAUTHN_RP_ID", "localhost"),
+		WebAuthnRPName:            getEnv("WEBAUTHN_RP_NAME", "Susano Admin"),
+		WebAuthnOrigins:           getEnvAsSlice("WEBAUTHN_ORIGINS", nil),
+		WebAuthnChallengeLifetime: getEnvAsDuration("WEBAUTHN_CHALLENGE_LIFETIME", 5*time.Minute),
+
+		// Social login (OpenID Connect) for customers
+		OIDCStateLifetime: getEnvAsDuration("OIDC_STATE_LIFETIME", 10*time.Minute),
+
+		// API keys for admin integrations
+		APIKeyRateLimit:   getEnvAsInt("API_KEY_RATE_LIMIT", 60),
+		APIKeyMaxLifetime: getEnvAsDuration("API_KEY_MAX_LIFETIME", 8760*time.Hour), // 1 year
+
+		// Login Throttling
+		LoginMaxAttempts:      getEnvAsInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginLockoutDuration:  getEnvAsDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		LoginAttemptWindow:    getEnvAsDuration("LOGIN_ATTEMPT_WINDOW", 15*time.Minute),
+		LoginBaseDelay:        getEnvAsDuration("LOGIN_BASE_DELAY", 1*time.Second),
+		LoginMaxDelay:         getEnvAsDuration("LOGIN_MAX_DELAY", 30*time.Second),
+		LoginMaxAttemptsPerIP: getEnvAsInt("LOGIN_MAX_ATTEMPTS_PER_IP", 50),
+
+		// Password Reset
+		PasswordResetLifetime: getEnvAsDuration("PASSWORD_RESET_LIFETIME", 60*time.Minute),
+
+		// Password Policy
+		PasswordMinLength:        getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordRequireUppercase: getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", false),
+		PasswordRequireLowercase: getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", false),
+		PasswordRequireDigit:     getEnvAsBool("PASSWORD_REQUIRE_DIGIT", false),
+		PasswordRequireSymbol:    getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordHistory:          getEnvAsInt("PASSWORD_HISTORY", 5),
+		PasswordBreachListPath:   getEnv("PASSWORD_BREACH_LIST_PATH", ""),
+
+		// Email Verification
+		EmailVerificationLifetime:       getEnvAsDuration("EMAIL_VERIFICATION_LIFETIME", 24*time.Hour),
+		EmailVerificationResendInterval: getEnvAsDuration("EMAIL_VERIFICATION_RESEND_INTERVAL", 1*time.Minute),
+
+		// Magic Link Login
+		MagicLinkLifetime:        getEnvAsDuration("MAGIC_LINK_LIFETIME", 15*time.Minute),
+		MagicLinkRequestInterval: getEnvAsDuration("MAGIC_LINK_REQUEST_INTERVAL", 1*time.Minute),
+
+		// Inventory
+		StockReservationLifetime: getEnvAsDuration("STOCK_RESERVATION_LIFETIME", 15*time.Minute),
+
+		// Mail
+		MailDriver:      getEnv("MAIL_DRIVER", "log"),
+		MailFromAddress: getEnv("MAIL_FROM_ADDRESS", "no-reply@susano.id"),
+		MailFromName:    getEnv("MAIL_FROM_NAME", "Susano"),
+		MailFilePath:    getEnv("MAIL_FILE_PATH", "storage/mail"),
+
 		// CORS
 		CORSAllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
 
@@ -81,6 +249,9 @@ func Load() (*Config, error) {
 		RateLimitRequests: getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
 		RateLimitWindow:   getEnvAsDuration("RATE_LIMIT_WINDOW", 1*time.Minute),
 
+		// Proxies
+		TrustedProxies: getEnvAsSlice("TRUSTED_PROXIES", nil),
+
 		// Logging
 		LogLevel:      getEnv("LOG_LEVEL", "debug"),
 		LogMaxSize:    getEnvAsInt("LOG_MAX_SIZE", 100),
@@ -89,6 +260,21 @@ func Load() (*Config, error) {
 		LogCompress:   getEnvAsBool("LOG_COMPRESS", true),
 	}
 
+	// Session tokens are hashed with APP_KEY unless dedicated keys are set
+	if len(cfg.SessionTokenKeys) == 0 && cfg.AppKey != "" {
+		cfg.SessionTokenKeys = []string{cfg.AppKey}
+	}
+
+	// Passkeys are used from the admin app unless other origins are set
+	if len(cfg.WebAuthnOrigins) == 0 {
+		cfg.WebAuthnOrigins = []string{cfg.AdminAppURL}
+	}
+
+	// Providers are listed in OIDC_PROVIDERS and configured with OIDC_<NAME>_* variables
+	for _, name := range getEnvAsSlice("OIDC_PROVIDERS", nil) {
+		cfg.OIDCProviders = append(cfg.OIDCProviders, loadOIDCProvider(strings.TrimSpace(name), cfg.StoreAppURL))
+	}
+
 	// Validate configuration
 	if err := cfg.validate(); err != nil {
 		return nil, err
@@ -108,9 +294,70 @@ func (c *Config) validate() error {
 	if c.AppPort == "" {
 		return fmt.Errorf("APP_PORT is required")
 	}
+	if c.AppEnv == "production" && len(c.AppKey) < 32 {
+		return fmt.Errorf("APP_KEY must be at least 32 characters in production")
+	}
+	if c.AppEnv == "production" {
+		for _, key := range c.SessionTokenKeys {
+			if len(key) < 32 {
+				return fmt.Errorf("SESSION_TOKEN_KEYS must each be at least 32 characters in production")
+			}
+		}
+	}
+	if c.PasswordMinLength < 8 || c.PasswordMinLength > 72 {
+		return fmt.Errorf("PASSWORD_MIN_LENGTH must be between 8 and 72")
+	}
+	if c.WebAuthnRPID == "" {
+		return fmt.Errorf("WEBAUTHN_RP_ID is required")
+	}
+	for _, p := range c.OIDCProviders {
+		if p.Name == "" || p.Issuer == "" || p.ClientID == "" {
+			return fmt.Errorf("OIDC provider %q requires a name, an issuer and a client ID", p.Name)
+		}
+	}
+	if c.MailDriver != "log" && c.MailDriver != "file" {
+		return fmt.Errorf("MAIL_DRIVER must be one of: log, file")
+	}
+	for _, proxy := range c.TrustedProxies {
+		if ParseProxyNetwork(proxy) == nil {
+			return fmt.Errorf("TRUSTED_PROXIES entry %q is not an IP address or CIDR range", proxy)
+		}
+	}
 	return nil
 }
 
+// ParseProxyNetwork parses a trusted proxy given as an IP address or CIDR range.
+// Returns nil when the entry is neither.
+func ParseProxyNetwork(entry string) *net.IPNet {
+	entry = strings.TrimSpace(entry)
+	if _, network, err := net.ParseCIDR(entry); err == nil {
+		return network
+	}
+
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// loadOIDCProvider reads the OIDC_<NAME>_* variables of an identity provider
+func loadOIDCProvider(name, storeAppURL string) OIDCProvider {
+	prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+	return OIDCProvider{
+		Name:         strings.ToLower(name),
+		Issuer:       getEnv(prefix+"ISSUER", ""),
+		ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+		ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+		Scopes:       getEnvAsSlice(prefix+"SCOPES", []string{"openid", "email", "profile"}),
+		RedirectURL:  getEnv(prefix+"REDIRECT_URL", storeAppURL+"/auth/oidc/"+strings.ToLower(name)+"/callback"),
+	}
+}
+
 // Helper functions to read environment variables
 
 func getEnv(key, defaultValue string) string {
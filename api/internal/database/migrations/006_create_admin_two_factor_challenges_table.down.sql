@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_admin_two_factor_challenges_expires_at;
+DROP INDEX IF EXISTS idx_admin_two_factor_challenges_admin_id;
+DROP INDEX IF EXISTS idx_admin_two_factor_challenges_token;
+
+-- Drop table
+DROP TABLE IF EXISTS admin_two_factor_challenges;
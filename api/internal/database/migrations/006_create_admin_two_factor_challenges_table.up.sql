@@ -0,0 +1,18 @@
+-- Create admin_two_factor_challenges table
+-- A challenge is issued after a correct password for admins with 2FA enabled
+-- and must be completed with a code before a session is created
+CREATE TABLE admin_two_factor_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    admin_id UUID NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    token VARCHAR(255) NOT NULL UNIQUE,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_admin_two_factor_challenges_token ON admin_two_factor_challenges(token);
+CREATE INDEX idx_admin_two_factor_challenges_admin_id ON admin_two_factor_challenges(admin_id);
+CREATE INDEX idx_admin_two_factor_challenges_expires_at ON admin_two_factor_challenges(expires_at);
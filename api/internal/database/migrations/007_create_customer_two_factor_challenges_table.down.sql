@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_customer_two_factor_challenges_expires_at;
+DROP INDEX IF EXISTS idx_customer_two_factor_challenges_customer_id;
+DROP INDEX IF EXISTS idx_customer_two_factor_challenges_token;
+
+-- Drop table
+DROP TABLE IF EXISTS customer_two_factor_challenges;
@@ -0,0 +1,18 @@
+-- Create customer_two_factor_challenges table
+-- A challenge is issued after a correct password for customers with 2FA enabled
+-- and must be completed with a code before a session is created
+CREATE TABLE customer_two_factor_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    token VARCHAR(255) NOT NULL UNIQUE,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_customer_two_factor_challenges_token ON customer_two_factor_challenges(token);
+CREATE INDEX idx_customer_two_factor_challenges_customer_id ON customer_two_factor_challenges(customer_id);
+CREATE INDEX idx_customer_two_factor_challenges_expires_at ON customer_two_factor_challenges(expires_at);
@@ -0,0 +1,6 @@
+-- Restore email-only primary key
+DELETE FROM password_reset_tokens;
+
+ALTER TABLE password_reset_tokens DROP CONSTRAINT password_reset_tokens_pkey;
+ALTER TABLE password_reset_tokens DROP COLUMN user_type;
+ALTER TABLE password_reset_tokens ADD PRIMARY KEY (email);
@@ -0,0 +1,7 @@
+-- Admins and customers are separate accounts that may share an email address,
+-- so reset tokens are keyed by email and user type
+DELETE FROM password_reset_tokens;
+
+ALTER TABLE password_reset_tokens DROP CONSTRAINT password_reset_tokens_pkey;
+ALTER TABLE password_reset_tokens ADD COLUMN user_type VARCHAR(20) NOT NULL;
+ALTER TABLE password_reset_tokens ADD PRIMARY KEY (email, user_type);
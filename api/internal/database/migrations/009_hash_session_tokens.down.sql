@@ -0,0 +1,6 @@
+-- Hashed tokens cannot be turned back into plain tokens
+DELETE FROM admin_sessions;
+DELETE FROM customer_sessions;
+
+ALTER TABLE admin_sessions ALTER COLUMN token TYPE VARCHAR(255);
+ALTER TABLE customer_sessions ALTER COLUMN token TYPE VARCHAR(255);
@@ -0,0 +1,7 @@
+-- Session tokens are now stored as HMAC-SHA256 hashes. Existing rows hold plain
+-- tokens that can no longer be looked up, so every session is invalidated.
+DELETE FROM admin_sessions;
+DELETE FROM customer_sessions;
+
+ALTER TABLE admin_sessions ALTER COLUMN token TYPE CHAR(64);
+ALTER TABLE customer_sessions ALTER COLUMN token TYPE CHAR(64);
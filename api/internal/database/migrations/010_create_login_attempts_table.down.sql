@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_login_attempts_ip_address_created_at;
+DROP INDEX IF EXISTS idx_login_attempts_user_type_email_created_at;
+
+-- Drop table
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,14 @@
+-- Create login_attempts table (ledger of logins used for throttling and lockout)
+CREATE TABLE login_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    user_type VARCHAR(20) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    result VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_login_attempts_user_type_email_created_at ON login_attempts(user_type, email, created_at);
+CREATE INDEX idx_login_attempts_ip_address_created_at ON login_attempts(ip_address, created_at);
@@ -0,0 +1,23 @@
+-- Create role_permissions table (permissions granted to each admin role)
+-- Permission names come from the registry in the application, super admins always hold every permission
+CREATE TABLE role_permissions (
+    role admin_role NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (role, permission)
+);
+
+-- Seed default permissions
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'admins.view'),
+    ('admin', 'admins.create'),
+    ('admin', 'admins.update'),
+    ('admin', 'admins.delete'),
+    ('admin', 'customers.unlock'),
+    ('admin', 'orders.view'),
+    ('admin', 'orders.refund'),
+    ('admin', 'dashboard.view'),
+    ('admin', 'uploads.manage'),
+    ('admin', 'roles.view'),
+    ('cashier', 'orders.view'),
+    ('cashier', 'dashboard.view');
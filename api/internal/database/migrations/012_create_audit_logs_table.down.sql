@@ -0,0 +1,11 @@
+-- Revoke audit log permissions
+DELETE FROM role_permissions WHERE permission = 'audit_logs.view';
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_audit_logs_action;
+DROP INDEX IF EXISTS idx_audit_logs_entity_type_entity_id;
+DROP INDEX IF EXISTS idx_audit_logs_actor_id;
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
+
+-- Drop table
+DROP TABLE IF EXISTS audit_logs;
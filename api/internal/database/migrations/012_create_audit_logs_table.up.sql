@@ -0,0 +1,23 @@
+-- Create audit_logs table (append-only record of admin actions)
+CREATE TABLE audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    actor_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255),
+    old_values JSONB,
+    new_values JSONB,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs(actor_id);
+CREATE INDEX idx_audit_logs_entity_type_entity_id ON audit_logs(entity_type, entity_id);
+CREATE INDEX idx_audit_logs_action ON audit_logs(action);
+
+-- Grant viewing the audit log to admins
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'audit_logs.view');
@@ -0,0 +1,9 @@
+-- Revoke impersonation permission
+DELETE FROM role_permissions WHERE permission = 'customers.impersonate';
+
+-- Drop index
+DROP INDEX IF EXISTS idx_customer_sessions_impersonator_id;
+
+-- Drop column
+ALTER TABLE customer_sessions
+    DROP COLUMN IF EXISTS impersonator_id;
@@ -0,0 +1,10 @@
+-- Add impersonator_id to customer_sessions (set when an admin impersonates the customer)
+ALTER TABLE customer_sessions
+    ADD COLUMN impersonator_id UUID REFERENCES admins(id) ON DELETE CASCADE;
+
+-- Create index for impersonation lookup
+CREATE INDEX idx_customer_sessions_impersonator_id ON customer_sessions(impersonator_id) WHERE impersonator_id IS NOT NULL;
+
+-- Grant impersonating customers to admins
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'customers.impersonate');
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_login_history_user_type_user_id_created_at;
+
+-- Drop table
+DROP TABLE IF EXISTS login_history;
@@ -0,0 +1,17 @@
+-- Create login_history table (append-only record of logins per account, kept after sessions end)
+CREATE TABLE login_history (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    user_type VARCHAR(20) NOT NULL,
+    user_id UUID NOT NULL,
+    result VARCHAR(20) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    browser TEXT NOT NULL DEFAULT '',
+    os TEXT NOT NULL DEFAULT '',
+    device VARCHAR(20) NOT NULL DEFAULT '',
+    is_new_device BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_login_history_user_type_user_id_created_at ON login_history(user_type, user_id, created_at);
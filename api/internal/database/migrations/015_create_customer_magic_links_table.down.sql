@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_customer_magic_links_expires_at;
+DROP INDEX IF EXISTS idx_customer_magic_links_customer_id;
+
+-- Drop table
+DROP TABLE IF EXISTS customer_magic_links;
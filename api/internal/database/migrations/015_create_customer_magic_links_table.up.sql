@@ -0,0 +1,15 @@
+-- Create customer_magic_links table (single-use passwordless login links)
+CREATE TABLE customer_magic_links (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    token CHAR(64) NOT NULL UNIQUE,
+    browser_binding CHAR(64),
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_customer_magic_links_customer_id ON customer_magic_links(customer_id);
+CREATE INDEX idx_customer_magic_links_expires_at ON customer_magic_links(expires_at);
@@ -0,0 +1,11 @@
+-- Drop WebAuthn state from two-factor challenges
+ALTER TABLE admin_two_factor_challenges DROP COLUMN IF EXISTS webauthn_session;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_admin_webauthn_challenges_expires_at;
+DROP INDEX IF EXISTS idx_admin_webauthn_challenges_admin_id;
+DROP INDEX IF EXISTS idx_admin_passkeys_admin_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS admin_webauthn_challenges;
+DROP TABLE IF EXISTS admin_passkeys;
@@ -0,0 +1,41 @@
+-- Create admin_passkeys table (WebAuthn credentials, used as a second factor or for passwordless login)
+CREATE TABLE admin_passkeys (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    admin_id UUID NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    attestation_type VARCHAR(50) NOT NULL DEFAULT '',
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    aaguid BYTEA,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+    user_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    attachment VARCHAR(50) NOT NULL DEFAULT '',
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_admin_passkeys_admin_id ON admin_passkeys(admin_id);
+
+-- Create admin_webauthn_challenges table
+-- Holds the ceremony state between issuing WebAuthn options and verifying the browser response,
+-- for passkey registration (admin known) and passwordless login (admin unknown until verified)
+CREATE TABLE admin_webauthn_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    admin_id UUID REFERENCES admins(id) ON DELETE CASCADE,
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('registration', 'login')),
+    token CHAR(64) NOT NULL UNIQUE,
+    session JSONB NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_admin_webauthn_challenges_admin_id ON admin_webauthn_challenges(admin_id);
+CREATE INDEX idx_admin_webauthn_challenges_expires_at ON admin_webauthn_challenges(expires_at);
+
+-- Passkeys can complete a two-factor challenge, which then carries the WebAuthn ceremony state
+ALTER TABLE admin_two_factor_challenges ADD COLUMN webauthn_session JSONB;
@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_customer_oidc_states_expires_at;
+DROP INDEX IF EXISTS idx_customer_identities_customer_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS customer_oidc_states;
+DROP TABLE IF EXISTS customer_identities;
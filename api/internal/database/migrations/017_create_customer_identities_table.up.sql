@@ -0,0 +1,28 @@
+-- Create customer_identities table (accounts at OpenID Connect providers linked to customers)
+CREATE TABLE customer_identities (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+-- Create customer_oidc_states table (pending logins at OpenID Connect providers)
+CREATE TABLE customer_oidc_states (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    provider VARCHAR(50) NOT NULL,
+    state CHAR(64) NOT NULL UNIQUE,
+    nonce VARCHAR(255) NOT NULL,
+    code_verifier VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_customer_identities_customer_id ON customer_identities(customer_id);
+CREATE INDEX idx_customer_oidc_states_expires_at ON customer_oidc_states(expires_at);
@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_admin_api_keys_admin_id;
+
+-- Drop column
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS api_key_id;
+
+-- Drop table
+DROP TABLE IF EXISTS admin_api_keys;
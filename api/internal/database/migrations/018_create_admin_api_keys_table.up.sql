@@ -0,0 +1,21 @@
+-- Create admin_api_keys table (scoped keys for machine-to-machine integrations, stored hashed)
+CREATE TABLE admin_api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    admin_id UUID NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    rate_limit INTEGER NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Record which API key an audited action was made with
+ALTER TABLE audit_logs ADD COLUMN api_key_id UUID REFERENCES admin_api_keys(id) ON DELETE SET NULL;
+
+-- Create indexes for performance
+CREATE INDEX idx_admin_api_keys_admin_id ON admin_api_keys(admin_id);
@@ -0,0 +1,11 @@
+-- Create password_history table (previous password hashes per account, to prevent reuse)
+CREATE TABLE password_history (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    user_type VARCHAR(20) NOT NULL,
+    user_id UUID NOT NULL,
+    password VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_password_history_user_type_user_id_created_at ON password_history(user_type, user_id, created_at);
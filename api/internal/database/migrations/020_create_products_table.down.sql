@@ -0,0 +1,18 @@
+-- Revoke product permissions
+DELETE FROM role_permissions WHERE permission LIKE 'products.%';
+
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_products_updated_at ON products;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_images_product_id;
+DROP INDEX IF EXISTS idx_products_deleted_at;
+DROP INDEX IF EXISTS idx_products_status;
+DROP INDEX IF EXISTS idx_products_slug;
+
+-- Drop tables
+DROP TABLE IF EXISTS product_images;
+DROP TABLE IF EXISTS products;
+
+-- Drop enum
+DROP TYPE IF EXISTS product_status;
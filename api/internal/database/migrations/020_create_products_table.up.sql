@@ -0,0 +1,44 @@
+-- Create enum for product statuses
+CREATE TYPE product_status AS ENUM ('draft', 'active', 'archived');
+
+-- Create products table (the catalog, only active products are listed on the storefront)
+CREATE TABLE products (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status product_status NOT NULL DEFAULT 'draft',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+-- Create product_images table (ordered images of a product)
+CREATE TABLE product_images (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    url VARCHAR(500) NOT NULL,
+    alt_text VARCHAR(255) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE UNIQUE INDEX idx_products_slug ON products(slug) WHERE deleted_at IS NULL;
+CREATE INDEX idx_products_status ON products(status);
+CREATE INDEX idx_products_deleted_at ON products(deleted_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_product_images_product_id ON product_images(product_id, position);
+
+-- Apply trigger to products table
+CREATE TRIGGER update_products_updated_at
+    BEFORE UPDATE ON products
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Grant managing the catalog to admins and viewing it to cashiers
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'products.view'),
+    ('admin', 'products.create'),
+    ('admin', 'products.update'),
+    ('admin', 'products.delete'),
+    ('cashier', 'products.view');
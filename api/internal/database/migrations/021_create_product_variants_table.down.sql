@@ -0,0 +1,13 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_product_variants_updated_at ON product_variants;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_variants_barcode;
+DROP INDEX IF EXISTS idx_product_variants_product_id;
+DROP INDEX IF EXISTS idx_product_variants_product_id_options;
+DROP INDEX IF EXISTS idx_product_variants_sku;
+DROP INDEX IF EXISTS idx_product_options_product_id_name;
+
+-- Drop tables
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_options;
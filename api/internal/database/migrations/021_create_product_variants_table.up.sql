@@ -0,0 +1,42 @@
+-- Create product_options table (option types of a product with their ordered values, e.g. Size: S, M, L)
+CREATE TABLE product_options (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    "values" TEXT[] NOT NULL DEFAULT '{}',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create product_variants table (the variant matrix, one SKU per combination of option values)
+CREATE TABLE product_variants (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(100) NOT NULL,
+    options JSONB NOT NULL DEFAULT '{}',
+    price BIGINT NOT NULL DEFAULT 0 CHECK (price >= 0),
+    barcode VARCHAR(100),
+    weight INTEGER NOT NULL DEFAULT 0 CHECK (weight >= 0),
+    stock INTEGER NOT NULL DEFAULT 0,
+    is_enabled BOOLEAN NOT NULL DEFAULT true,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE UNIQUE INDEX idx_product_options_product_id_name ON product_options(product_id, name);
+CREATE UNIQUE INDEX idx_product_variants_sku ON product_variants(sku);
+CREATE UNIQUE INDEX idx_product_variants_product_id_options ON product_variants(product_id, options);
+CREATE INDEX idx_product_variants_product_id ON product_variants(product_id, position);
+CREATE INDEX idx_product_variants_barcode ON product_variants(barcode) WHERE barcode IS NOT NULL;
+
+-- Apply trigger to product_variants table
+CREATE TRIGGER update_product_variants_updated_at
+    BEFORE UPDATE ON product_variants
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Give existing products their single default variant
+INSERT INTO product_variants (product_id, sku)
+SELECT id, UPPER(slug) FROM products WHERE deleted_at IS NULL;
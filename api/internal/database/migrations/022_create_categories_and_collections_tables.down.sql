@@ -0,0 +1,26 @@
+-- Revoke catalog permissions
+DELETE FROM role_permissions WHERE permission = 'catalog.manage';
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_collections_updated_at ON collections;
+DROP TRIGGER IF EXISTS update_categories_updated_at ON categories;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_collection_products_product_id;
+DROP INDEX IF EXISTS idx_product_categories_category_id;
+DROP INDEX IF EXISTS idx_categories_path;
+DROP INDEX IF EXISTS idx_categories_parent_id;
+DROP INDEX IF EXISTS idx_products_tags;
+
+-- Drop tables
+DROP TABLE IF EXISTS collection_products;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS product_categories;
+DROP TABLE IF EXISTS categories;
+
+-- Drop enums
+DROP TYPE IF EXISTS collection_match;
+DROP TYPE IF EXISTS collection_type;
+
+-- Drop column
+ALTER TABLE products DROP COLUMN IF EXISTS tags;
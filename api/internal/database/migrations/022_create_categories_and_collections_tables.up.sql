@@ -0,0 +1,71 @@
+-- Add tags to products (free-form labels used by collection rules and filters)
+ALTER TABLE products ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Create categories table (a tree; path lists the IDs from the root down to the category, e.g. /<root>/<parent>/<id>/)
+CREATE TABLE categories (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    path TEXT NOT NULL,
+    depth INTEGER NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create product_categories table (many-to-many assignment of products to categories)
+CREATE TABLE product_categories (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, category_id)
+);
+
+-- Create enums for collections
+CREATE TYPE collection_type AS ENUM ('manual', 'automated');
+CREATE TYPE collection_match AS ENUM ('all', 'any');
+
+-- Create collections table (manual collections list their products, automated ones match products by rules)
+CREATE TABLE collections (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    type collection_type NOT NULL,
+    match collection_match NOT NULL DEFAULT 'all',
+    rules JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create collection_products table (products picked for manual collections, in order)
+CREATE TABLE collection_products (
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (collection_id, product_id)
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_products_tags ON products USING GIN (tags);
+CREATE INDEX idx_categories_parent_id ON categories(parent_id, position);
+CREATE INDEX idx_categories_path ON categories(path text_pattern_ops);
+CREATE INDEX idx_product_categories_category_id ON product_categories(category_id);
+CREATE INDEX idx_collection_products_product_id ON collection_products(product_id);
+
+-- Apply triggers
+CREATE TRIGGER update_categories_updated_at
+    BEFORE UPDATE ON categories
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_collections_updated_at
+    BEFORE UPDATE ON collections
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Grant managing categories and collections to admins
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'catalog.manage');
@@ -0,0 +1,33 @@
+-- Revoke inventory permissions
+DELETE FROM role_permissions WHERE permission LIKE 'inventory.%';
+
+-- Restore the stock counters of variants from the ledger
+ALTER TABLE product_variants ADD COLUMN stock INTEGER NOT NULL DEFAULT 0;
+
+UPDATE product_variants v
+SET stock = m.on_hand
+FROM (SELECT variant_id, SUM(quantity) AS on_hand FROM stock_movements GROUP BY variant_id) m
+WHERE m.variant_id = v.id;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_stock_reservations_updated_at ON stock_reservations;
+DROP TRIGGER IF EXISTS prevent_stock_movements_truncate ON stock_movements;
+DROP TRIGGER IF EXISTS prevent_stock_movements_update ON stock_movements;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_stock_reservation_items_variant_id;
+DROP INDEX IF EXISTS idx_stock_reservations_expires_at;
+DROP INDEX IF EXISTS idx_stock_reservations_customer_id;
+DROP INDEX IF EXISTS idx_stock_movements_variant_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS stock_reservation_items;
+DROP TABLE IF EXISTS stock_reservations;
+DROP TABLE IF EXISTS stock_movements;
+
+-- Drop function
+DROP FUNCTION IF EXISTS prevent_stock_movement_changes();
+
+-- Drop enums
+DROP TYPE IF EXISTS stock_reservation_status;
+DROP TYPE IF EXISTS stock_movement_type;
@@ -0,0 +1,83 @@
+-- Create enum for stock movement types
+CREATE TYPE stock_movement_type AS ENUM ('receipt', 'sale', 'return', 'adjustment', 'transfer');
+
+-- Create stock_movements table (append-only ledger, the stock of a variant is the sum of its quantities).
+-- variant_id has no foreign key so the history of removed variants is kept; sku records the SKU at the time.
+CREATE TABLE stock_movements (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    variant_id UUID NOT NULL,
+    sku VARCHAR(100) NOT NULL,
+    type stock_movement_type NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity <> 0),
+    reason TEXT NOT NULL DEFAULT '',
+    reference VARCHAR(255),
+    actor_id UUID REFERENCES admins(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Reject changes to recorded movements, corrections are made with new movements
+CREATE OR REPLACE FUNCTION prevent_stock_movement_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock movements are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER prevent_stock_movements_update
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_stock_movement_changes();
+
+CREATE TRIGGER prevent_stock_movements_truncate
+    BEFORE TRUNCATE ON stock_movements
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION prevent_stock_movement_changes();
+
+-- Create enum for reservation statuses
+CREATE TYPE stock_reservation_status AS ENUM ('active', 'released', 'committed');
+
+-- Create stock_reservations table (stock held during checkout; an active reservation stops holding stock once expired)
+CREATE TABLE stock_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    customer_id UUID REFERENCES customers(id) ON DELETE CASCADE,
+    status stock_reservation_status NOT NULL DEFAULT 'active',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create stock_reservation_items table (the quantity of each variant a reservation holds)
+CREATE TABLE stock_reservation_items (
+    reservation_id UUID NOT NULL REFERENCES stock_reservations(id) ON DELETE CASCADE,
+    variant_id UUID NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (reservation_id, variant_id)
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_stock_movements_variant_id ON stock_movements(variant_id, created_at);
+CREATE INDEX idx_stock_reservations_customer_id ON stock_reservations(customer_id) WHERE status = 'active';
+CREATE INDEX idx_stock_reservations_expires_at ON stock_reservations(expires_at) WHERE status = 'active';
+CREATE INDEX idx_stock_reservation_items_variant_id ON stock_reservation_items(variant_id);
+
+-- Apply trigger to stock_reservations table
+CREATE TRIGGER update_stock_reservations_updated_at
+    BEFORE UPDATE ON stock_reservations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Move the stock counters of variants into the ledger as opening balances
+INSERT INTO stock_movements (variant_id, sku, type, quantity, reason)
+SELECT id, sku, 'adjustment', stock, 'Opening balance'
+FROM product_variants
+WHERE stock <> 0;
+
+ALTER TABLE product_variants DROP COLUMN stock;
+
+-- Grant managing stock to admins and viewing stock and selling to cashiers
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'inventory.view'),
+    ('admin', 'inventory.adjust'),
+    ('admin', 'inventory.sell'),
+    ('cashier', 'inventory.view'),
+    ('cashier', 'inventory.sell');
@@ -0,0 +1,31 @@
+-- Revoke location and transfer permissions
+DELETE FROM role_permissions WHERE permission IN ('locations.manage', 'inventory.transfer');
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_transfer_orders_updated_at ON transfer_orders;
+DROP TRIGGER IF EXISTS update_locations_updated_at ON locations;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_transfer_order_items_variant_id;
+DROP INDEX IF EXISTS idx_transfer_orders_to_location_id;
+DROP INDEX IF EXISTS idx_transfer_orders_from_location_id;
+DROP INDEX IF EXISTS idx_transfer_orders_status;
+DROP INDEX IF EXISTS idx_admins_location_id;
+DROP INDEX IF EXISTS idx_stock_reservations_location_id;
+DROP INDEX IF EXISTS idx_stock_movements_location_id;
+DROP INDEX IF EXISTS idx_locations_fulfills_online;
+
+-- Drop tables
+DROP TABLE IF EXISTS transfer_order_items;
+DROP TABLE IF EXISTS transfer_orders;
+
+-- Drop location columns; the stock of a variant becomes the sum over all locations again
+ALTER TABLE admins DROP COLUMN IF EXISTS location_id;
+ALTER TABLE stock_reservations DROP COLUMN IF EXISTS location_id;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS location_id;
+
+DROP TABLE IF EXISTS locations;
+
+-- Drop enums
+DROP TYPE IF EXISTS transfer_status;
+DROP TYPE IF EXISTS location_type;
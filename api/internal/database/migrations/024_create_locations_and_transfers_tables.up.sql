@@ -0,0 +1,93 @@
+-- Create enum for location types
+CREATE TYPE location_type AS ENUM ('warehouse', 'store');
+
+-- Create locations table (places holding stock; the one fulfilling online orders backs the storefront)
+CREATE TABLE locations (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    name VARCHAR(255) NOT NULL,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    type location_type NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    fulfills_online BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- At most one location fulfils online orders
+CREATE UNIQUE INDEX idx_locations_fulfills_online ON locations(fulfills_online) WHERE fulfills_online;
+
+-- Apply trigger to locations table
+CREATE TRIGGER update_locations_updated_at
+    BEFORE UPDATE ON locations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Existing stock is held at the main warehouse, which fulfils online orders
+INSERT INTO locations (name, code, type, fulfills_online) VALUES ('Main warehouse', 'MAIN', 'warehouse', true);
+
+-- Record the location of every movement; the append-only trigger is lifted only to backfill existing ones
+ALTER TABLE stock_movements ADD COLUMN location_id UUID REFERENCES locations(id);
+
+ALTER TABLE stock_movements DISABLE TRIGGER prevent_stock_movements_update;
+UPDATE stock_movements SET location_id = (SELECT id FROM locations WHERE code = 'MAIN');
+ALTER TABLE stock_movements ENABLE TRIGGER prevent_stock_movements_update;
+
+ALTER TABLE stock_movements ALTER COLUMN location_id SET NOT NULL;
+
+-- Reservations hold stock of the location fulfilling the order
+ALTER TABLE stock_reservations ADD COLUMN location_id UUID REFERENCES locations(id);
+UPDATE stock_reservations SET location_id = (SELECT id FROM locations WHERE code = 'MAIN');
+ALTER TABLE stock_reservations ALTER COLUMN location_id SET NOT NULL;
+
+-- Home location of an admin, where their sales at the till take stock from
+ALTER TABLE admins ADD COLUMN location_id UUID REFERENCES locations(id);
+
+-- Create enum for transfer statuses
+CREATE TYPE transfer_status AS ENUM ('draft', 'in_transit', 'received', 'cancelled');
+
+-- Create transfer_orders table (stock moved between locations; shipped stock is in transit until received)
+CREATE TABLE transfer_orders (
+    id UUID PRIMARY KEY DEFAULT gen_uuid_v7(),
+    from_location_id UUID NOT NULL REFERENCES locations(id),
+    to_location_id UUID NOT NULL REFERENCES locations(id),
+    status transfer_status NOT NULL DEFAULT 'draft',
+    note TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES admins(id),
+    shipped_at TIMESTAMP,
+    received_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (from_location_id <> to_location_id)
+);
+
+-- Create transfer_order_items table (the quantity of each variant a transfer moves).
+-- Like the ledger, variant_id has no foreign key so past transfers of removed variants are kept.
+CREATE TABLE transfer_order_items (
+    transfer_id UUID NOT NULL REFERENCES transfer_orders(id) ON DELETE CASCADE,
+    variant_id UUID NOT NULL,
+    sku VARCHAR(100) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (transfer_id, variant_id)
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_stock_movements_location_id ON stock_movements(location_id, variant_id);
+CREATE INDEX idx_stock_reservations_location_id ON stock_reservations(location_id) WHERE status = 'active';
+CREATE INDEX idx_admins_location_id ON admins(location_id);
+CREATE INDEX idx_transfer_orders_status ON transfer_orders(status, created_at);
+CREATE INDEX idx_transfer_orders_from_location_id ON transfer_orders(from_location_id);
+CREATE INDEX idx_transfer_orders_to_location_id ON transfer_orders(to_location_id);
+CREATE INDEX idx_transfer_order_items_variant_id ON transfer_order_items(variant_id);
+
+-- Apply trigger to transfer_orders table
+CREATE TRIGGER update_transfer_orders_updated_at
+    BEFORE UPDATE ON transfer_orders
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Grant managing locations and transferring stock to admins
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'locations.manage'),
+    ('admin', 'inventory.transfer');
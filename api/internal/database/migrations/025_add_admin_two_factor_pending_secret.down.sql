@@ -0,0 +1,6 @@
+-- Keep secrets of setups still waiting for confirmation
+UPDATE admins
+SET two_factor_secret = two_factor_pending_secret
+WHERE two_factor_pending_secret IS NOT NULL AND two_factor_confirmed_at IS NULL;
+
+ALTER TABLE admins DROP COLUMN IF EXISTS two_factor_pending_secret;
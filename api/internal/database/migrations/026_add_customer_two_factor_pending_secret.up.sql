@@ -0,0 +1,7 @@
+-- Secret waiting for confirmation during setup or regeneration; the confirmed secret stays enforced until it is swapped in
+ALTER TABLE customers ADD COLUMN two_factor_pending_secret TEXT;
+
+-- Move secrets that were never confirmed to the pending column
+UPDATE customers
+SET two_factor_pending_secret = two_factor_secret, two_factor_secret = NULL
+WHERE two_factor_secret IS NOT NULL AND two_factor_confirmed_at IS NULL;
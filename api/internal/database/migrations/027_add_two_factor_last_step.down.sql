@@ -0,0 +1,2 @@
+ALTER TABLE customers DROP COLUMN IF EXISTS two_factor_last_step;
+ALTER TABLE admins DROP COLUMN IF EXISTS two_factor_last_step;
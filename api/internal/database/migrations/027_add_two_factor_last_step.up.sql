@@ -0,0 +1,3 @@
+-- Time step of the last accepted two-factor code, so an observed code cannot be replayed within its validity window
+ALTER TABLE admins ADD COLUMN two_factor_last_step BIGINT;
+ALTER TABLE customers ADD COLUMN two_factor_last_step BIGINT;
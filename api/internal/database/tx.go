@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// txKey is the context key of the transaction started by DB.Transaction
+type txKey struct{}
+
+// DB runs queries in the transaction carried by the context if there is one, and on the
+// connection pool otherwise, so repository methods called inside DB.Transaction share it
+type DB struct {
+	*sql.DB
+}
+
+// Wrap wraps a connection pool so it joins transactions carried by the context
+func Wrap(db *sql.DB) *DB {
+	return &DB{DB: db}
+}
+
+// Transaction runs fn in a transaction carried by the context passed to it, committing if fn
+// returns nil and rolling back otherwise. A nested call joins the outer transaction.
+func (db *DB) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// BeginTx starts a transaction, or joins the one carried by the context. A joined transaction
+// is committed or rolled back by whoever started it.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &Tx{Tx: tx, joined: true}, nil
+	}
+
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+// ExecContext executes a query in the transaction of the context, if any
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx.ExecContext(ctx, query, args...)
+	}
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs a query in the transaction of the context, if any
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx.QueryContext(ctx, query, args...)
+	}
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a query returning one row in the transaction of the context, if any
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+// Tx is a transaction started or joined by DB.BeginTx
+type Tx struct {
+	*sql.Tx
+	joined bool
+}
+
+// Commit commits the transaction, unless it was joined and is committed by its owner
+func (tx *Tx) Commit() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+// Rollback rolls the transaction back, unless it was joined; the owner then rolls back
+// when the error is returned to it
+func (tx *Tx) Rollback() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Rollback()
+}
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	"github.com/yeftaz/susano.id/api/pkg/twofactor"
 )
 
 // AdminRole represents the role of an admin user
@@ -26,8 +29,10 @@ type Admin struct {
 	IsActive               bool       `json:"is_active"`
 	EmailVerifiedAt        *time.Time `json:"email_verified_at,omitempty"`
 	TwoFactorSecret        *string    `json:"-"` // Never expose 2FA secret
+	TwoFactorPendingSecret *string    `json:"-"` // Secret waiting for confirmation, enforced only once confirmed
 	TwoFactorRecoveryCodes *string    `json:"-"` // Never expose recovery codes
 	TwoFactorConfirmedAt   *time.Time `json:"two_factor_confirmed_at,omitempty"`
+	LocationID             *uuid.UUID `json:"location_id"` // Home location, where sales at the till take stock from
 	CreatedAt              time.Time  `json:"created_at"`
 	UpdatedAt              time.Time  `json:"updated_at"`
 	DeletedAt              *time.Time `json:"deleted_at,omitempty"`
@@ -43,6 +48,29 @@ func (a *Admin) HasTwoFactor() bool {
 	return a.TwoFactorConfirmedAt != nil
 }
 
+// IsTwoFactorPending checks if admin has started 2FA setup or regeneration but not confirmed it yet
+func (a *Admin) IsTwoFactorPending() bool {
+	return a.TwoFactorPendingSecret != nil
+}
+
+// TwoFactorAccount returns the two-factor state of the admin
+func (a *Admin) TwoFactorAccount() *shared.TwoFactorAccount {
+	return &shared.TwoFactorAccount{
+		ID:            a.ID,
+		Email:         a.Email,
+		Password:      a.Password,
+		Secret:        a.TwoFactorSecret,
+		PendingSecret: a.TwoFactorPendingSecret,
+		RecoveryCodes: a.TwoFactorRecoveryCodes,
+		ConfirmedAt:   a.TwoFactorConfirmedAt,
+	}
+}
+
+// RecoveryCodesRemaining returns the number of unused 2FA recovery codes
+func (a *Admin) RecoveryCodesRemaining() int {
+	return len(twofactor.DecodeRecoveryCodes(a.TwoFactorRecoveryCodes))
+}
+
 // CanAccessAdminPanel checks if admin can access the admin panel
 func (a *Admin) CanAccessAdminPanel() bool {
 	return a.IsActive && !a.IsDeleted()
@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key, telling it apart from a session token
+const APIKeyPrefix = "sak_"
+
+// apiKeyUsageInterval is how often the last use of a key is written
+const apiKeyUsageInterval = time.Minute
+
+// APIKey represents a named key an admin creates for machine-to-machine integrations.
+// Requests made with it act as the owning admin, limited to the key's scopes.
+type APIKey struct {
+	ID         uuid.UUID    `json:"id"`
+	AdminID    uuid.UUID    `json:"admin_id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"` // Start of the key, shown to tell keys apart
+	KeyHash    string       `json:"-"`      // Never expose key hash in JSON
+	Scopes     []Permission `json:"scopes"`
+	RateLimit  int          `json:"rate_limit"` // Requests per minute
+	ExpiresAt  time.Time    `json:"expires_at"`
+	LastUsedAt *time.Time   `json:"last_used_at,omitempty"`
+	LastUsedIP *string      `json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time   `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// IsAPIKey checks if a bearer token is an API key rather than a session token
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// IsExpired checks if the key has passed its expiry
+func (k *APIKey) IsExpired() bool {
+	return time.Now().After(k.ExpiresAt)
+}
+
+// IsRevoked checks if the key was revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// IsUsable checks if requests can still be made with the key
+func (k *APIKey) IsUsable() bool {
+	return !k.IsRevoked() && !k.IsExpired()
+}
+
+// HasScope checks if the key may be used for an action
+func (k *APIKey) HasScope(permission Permission) bool {
+	for _, scope := range k.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldTouch checks if the last use is stale enough to be updated
+func (k *APIKey) ShouldTouch() bool {
+	return k.LastUsedAt == nil || time.Since(*k.LastUsedAt) > apiKeyUsageInterval
+}
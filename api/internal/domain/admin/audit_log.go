@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction names a recorded action, as "<entity>.<verb>"
+type AuditAction string
+
+const (
+	AuditActionLogin                  AuditAction = "auth.login"
+	AuditActionLogout                 AuditAction = "auth.logout"
+	AuditActionPasswordChanged        AuditAction = "auth.password_changed"
+	AuditActionAdminCreated           AuditAction = "admin.created"
+	AuditActionAdminUpdated           AuditAction = "admin.updated"
+	AuditActionAdminDeleted           AuditAction = "admin.deleted"
+	AuditActionAdminSessionsRevoked   AuditAction = "admin.sessions_revoked"
+	AuditActionAdminUnlocked          AuditAction = "admin.unlocked"
+	AuditActionCustomerUnlocked       AuditAction = "customer.unlocked"
+	AuditActionImpersonationStarted   AuditAction = "customer.impersonation_started"
+	AuditActionImpersonationEnded     AuditAction = "customer.impersonation_ended"
+	AuditActionRolePermissionsUpdated AuditAction = "role.permissions_updated"
+	AuditActionAPIKeyCreated          AuditAction = "api_key.created"
+	AuditActionAPIKeyRevoked          AuditAction = "api_key.revoked"
+	AuditActionProductCreated         AuditAction = "product.created"
+	AuditActionProductUpdated         AuditAction = "product.updated"
+	AuditActionProductDeleted         AuditAction = "product.deleted"
+	AuditActionProductOptionsUpdated  AuditAction = "product.options_updated"
+	AuditActionVariantUpdated         AuditAction = "product_variant.updated"
+	AuditActionProductCategorized     AuditAction = "product.categories_updated"
+	AuditActionCategoryCreated        AuditAction = "category.created"
+	AuditActionCategoryUpdated        AuditAction = "category.updated"
+	AuditActionCategoryMoved          AuditAction = "category.moved"
+	AuditActionCategoryDeleted        AuditAction = "category.deleted"
+	AuditActionCollectionCreated      AuditAction = "collection.created"
+	AuditActionCollectionUpdated      AuditAction = "collection.updated"
+	AuditActionCollectionDeleted      AuditAction = "collection.deleted"
+	AuditActionStockAdjusted          AuditAction = "stock.adjusted"
+	AuditActionLocationCreated        AuditAction = "location.created"
+	AuditActionLocationUpdated        AuditAction = "location.updated"
+	AuditActionAdminLocationAssigned  AuditAction = "admin.location_assigned"
+	AuditActionTransferCreated        AuditAction = "transfer.created"
+	AuditActionTransferShipped        AuditAction = "transfer.shipped"
+	AuditActionTransferReceived       AuditAction = "transfer.received"
+	AuditActionTransferCancelled      AuditAction = "transfer.cancelled"
+)
+
+// Audited entity types
+const (
+	AuditEntityAdmin      = "admin"
+	AuditEntityCustomer   = "customer"
+	AuditEntityRole       = "role"
+	AuditEntityAPIKey     = "api_key"
+	AuditEntityProduct    = "product"
+	AuditEntityVariant    = "product_variant"
+	AuditEntityCategory   = "category"
+	AuditEntityCollection = "collection"
+	AuditEntityLocation   = "location"
+	AuditEntityTransfer   = "transfer_order"
+)
+
+// Actor is the admin performing an action, along with where the request came from
+type Actor struct {
+	Admin     *Admin
+	APIKeyID  *uuid.UUID // Set when the admin acted through an API key
+	IPAddress string
+	UserAgent string
+}
+
+// AuditLog represents a recorded admin action and the values it changed
+type AuditLog struct {
+	ID         uuid.UUID       `json:"id"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty"`
+	APIKeyID   *uuid.UUID      `json:"api_key_id,omitempty"`
+	Action     AuditAction     `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   *string         `json:"entity_id,omitempty"`
+	OldValues  json.RawMessage `json:"old_values,omitempty"`
+	NewValues  json.RawMessage `json:"new_values,omitempty"`
+	IPAddress  string          `json:"ip_address"`
+	UserAgent  string          `json:"user_agent"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditLogFilter narrows down audit log queries; zero values are ignored
+type AuditLogFilter struct {
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Passkey represents a WebAuthn credential registered by an admin
+type Passkey struct {
+	ID              uuid.UUID  `json:"id"`
+	AdminID         uuid.UUID  `json:"admin_id"`
+	Name            string     `json:"name"`
+	CredentialID    []byte     `json:"-"`
+	PublicKey       []byte     `json:"-"`
+	AttestationType string     `json:"-"`
+	Transports      []string   `json:"transports"`
+	AAGUID          []byte     `json:"-"`
+	SignCount       uint32     `json:"-"`
+	BackupEligible  bool       `json:"backup_eligible"` // Synced passkey, e.g. through a password manager
+	BackupState     bool       `json:"backup_state"`
+	UserVerified    bool       `json:"-"`
+	Attachment      string     `json:"-"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// WebAuthnPurpose is the ceremony a WebAuthn challenge belongs to
+type WebAuthnPurpose string
+
+const (
+	WebAuthnPurposeRegistration WebAuthnPurpose = "registration"
+	WebAuthnPurposeLogin        WebAuthnPurpose = "login"
+)
+
+// WebAuthnChallenge holds the state of a passkey registration or passwordless login
+// between issuing the options and verifying the browser response
+type WebAuthnChallenge struct {
+	ID        uuid.UUID       `json:"id"`
+	AdminID   *uuid.UUID      `json:"admin_id,omitempty"` // Unknown for passwordless logins
+	Purpose   WebAuthnPurpose `json:"purpose"`
+	Token     string          `json:"-"` // Never expose token in JSON
+	Session   []byte          `json:"-"`
+	IPAddress string          `json:"ip_address"`
+	UserAgent string          `json:"user_agent"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// IsExpired checks if the challenge can no longer be completed
+func (c *WebAuthnChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
@@ -0,0 +1,113 @@
+package admin
+
+// Permission identifies an action an admin may perform, named "<resource>.<action>"
+type Permission string
+
+const (
+	PermissionAdminsView           Permission = "admins.view"
+	PermissionAdminsCreate         Permission = "admins.create"
+	PermissionAdminsUpdate         Permission = "admins.update"
+	PermissionAdminsDelete         Permission = "admins.delete"
+	PermissionAdminsRevokeSessions Permission = "admins.revoke_sessions"
+	PermissionAdminsUnlock         Permission = "admins.unlock"
+	PermissionCustomersUnlock      Permission = "customers.unlock"
+	PermissionCustomersImpersonate Permission = "customers.impersonate"
+	PermissionOrdersView           Permission = "orders.view"
+	PermissionOrdersRefund         Permission = "orders.refund"
+	PermissionDashboardView        Permission = "dashboard.view"
+	PermissionUploadsManage        Permission = "uploads.manage"
+	PermissionRolesView            Permission = "roles.view"
+	PermissionRolesUpdate          Permission = "roles.update"
+	PermissionAuditLogsView        Permission = "audit_logs.view"
+	PermissionAPIKeysManage        Permission = "api_keys.manage"
+	PermissionProductsView         Permission = "products.view"
+	PermissionProductsCreate       Permission = "products.create"
+	PermissionProductsUpdate       Permission = "products.update"
+	PermissionProductsDelete       Permission = "products.delete"
+	PermissionCatalogManage        Permission = "catalog.manage"
+	PermissionInventoryView        Permission = "inventory.view"
+	PermissionInventoryAdjust      Permission = "inventory.adjust"
+	PermissionInventorySell        Permission = "inventory.sell"
+	PermissionInventoryTransfer    Permission = "inventory.transfer"
+	PermissionLocationsManage      Permission = "locations.manage"
+)
+
+// PermissionDefinition describes a registered permission
+type PermissionDefinition struct {
+	Name        Permission `json:"name"`
+	Description string     `json:"description"`
+}
+
+// Permissions is the registry of every permission that can be granted to a role
+var Permissions = []PermissionDefinition{
+	{PermissionAdminsView, "View admin users"},
+	{PermissionAdminsCreate, "Create admin users"},
+	{PermissionAdminsUpdate, "Update admin users"},
+	{PermissionAdminsDelete, "Delete admin users"},
+	{PermissionAdminsRevokeSessions, "Sign admin users out of all sessions"},
+	{PermissionAdminsUnlock, "Unlock admin accounts after failed logins"},
+	{PermissionCustomersUnlock, "Unlock customer accounts after failed logins"},
+	{PermissionCustomersImpersonate, "Sign in to the storefront as a customer for support"},
+	{PermissionOrdersView, "View orders"},
+	{PermissionOrdersRefund, "Refund orders"},
+	{PermissionDashboardView, "View dashboard statistics"},
+	{PermissionUploadsManage, "Upload and delete files"},
+	{PermissionRolesView, "View roles and their permissions"},
+	{PermissionRolesUpdate, "Change the permissions of a role"},
+	{PermissionAuditLogsView, "View and export the audit log"},
+	{PermissionAPIKeysManage, "Create and revoke own API keys for integrations"},
+	{PermissionProductsView, "View products, including drafts"},
+	{PermissionProductsCreate, "Create products"},
+	{PermissionProductsUpdate, "Update and publish products"},
+	{PermissionProductsDelete, "Delete products"},
+	{PermissionCatalogManage, "Manage categories and collections"},
+	{PermissionInventoryView, "View stock levels and movements"},
+	{PermissionInventoryAdjust, "Receive and adjust stock"},
+	{PermissionInventorySell, "Record sales at the till"},
+	{PermissionInventoryTransfer, "Create, ship and receive stock transfers between locations"},
+	{PermissionLocationsManage, "Manage locations and assign admins to them"},
+}
+
+// IsValid checks if the permission is in the registry
+func (p Permission) IsValid() bool {
+	for _, def := range Permissions {
+		if def.Name == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RolePermissions lists the permissions granted to a role
+type RolePermissions struct {
+	Role        AdminRole    `json:"role"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Roles lists the admin roles from lowest to highest rank
+var Roles = []AdminRole{RoleCashier, RoleAdmin, RoleSuperAdmin}
+
+// IsValid checks if the role is a known admin role
+func (r AdminRole) IsValid() bool {
+	return r.Rank() > 0
+}
+
+// Rank returns the position of the role in the hierarchy; higher ranks outrank lower ones
+func (r AdminRole) Rank() int {
+	for i, role := range Roles {
+		if role == r {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// CanGrant checks if an admin can assign the role, which must not be above their own
+func (a *Admin) CanGrant(role AdminRole) bool {
+	return role.IsValid() && role.Rank() <= a.Role.Rank()
+}
+
+// CanManage checks if an admin can change or delete another admin, who must not outrank them
+func (a *Admin) CanManage(other *Admin) bool {
+	return other.Role.Rank() <= a.Role.Rank()
+}
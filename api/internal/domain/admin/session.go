@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
 )
 
 // Session represents an admin session entity
@@ -17,13 +19,17 @@ type Session struct {
 	CreatedAt      time.Time `json:"created_at"`
 }
 
-// IsExpired checks if the session has expired based on session lifetime
-func (s *Session) IsExpired(sessionLifetime time.Duration) bool {
-	expiresAt := s.CreatedAt.Add(sessionLifetime)
-	return time.Now().After(expiresAt)
+// IsExpired checks if the session has expired under the given policy
+func (s *Session) IsExpired(policy shared.SessionPolicy) bool {
+	return policy.IsExpired(s.CreatedAt, s.LastActivityAt)
+}
+
+// ExpiresAt returns when the session expires under the given policy if it stays idle
+func (s *Session) ExpiresAt(policy shared.SessionPolicy) time.Time {
+	return policy.ExpiresAt(s.CreatedAt, s.LastActivityAt)
 }
 
-// ShouldRefresh checks if session should be refreshed (last activity > 15 minutes ago)
-func (s *Session) ShouldRefresh() bool {
-	return time.Since(s.LastActivityAt) > 15*time.Minute
+// ShouldRefresh checks if the last activity timestamp is stale enough to be updated
+func (s *Session) ShouldRefresh(policy shared.SessionPolicy) bool {
+	return time.Since(s.LastActivityAt) > policy.RefreshInterval()
 }
@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorChallenge represents a pending login that must be completed with a two-factor code
+type TwoFactorChallenge struct {
+	ID        uuid.UUID `json:"id"`
+	AdminID   uuid.UUID `json:"admin_id"`
+	Token     string    `json:"-"` // Never expose token in JSON
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+
+	WebAuthnSession []byte `json:"-"` // Set when the challenge can be completed with a passkey
+}
+
+// IsExpired checks if the challenge can no longer be completed
+func (c *TwoFactorChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// HasAttemptsRemaining checks if the challenge still accepts codes
+func (c *TwoFactorChallenge) HasAttemptsRemaining(maxAttempts int) bool {
+	return c.Attempts < maxAttempts
+}
+
+// AcceptsPasskey checks if the challenge can be completed with a passkey
+func (c *TwoFactorChallenge) AcceptsPasskey() bool {
+	return c.WebAuthnSession != nil
+}
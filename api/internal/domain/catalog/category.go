@@ -0,0 +1,62 @@
+package catalog
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category is a node of the category tree. Its path lists the IDs from the root down to the
+// category itself, e.g. "/<root id>/<parent id>/<id>/", so a subtree is every category whose path
+// starts with the path of its root. Paths are built from IDs rather than slugs, which keeps slugs
+// stable when a subtree moves. Siblings are ordered by position.
+type Category struct {
+	ID          uuid.UUID   `json:"id"`
+	ParentID    *uuid.UUID  `json:"parent_id"`
+	Name        string      `json:"name"`
+	Slug        string      `json:"slug"`
+	Description string      `json:"description"`
+	Path        string      `json:"path"`
+	Depth       int         `json:"depth"` // Zero for root categories
+	Position    int         `json:"position"`
+	Children    []*Category `json:"children,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// CategoryPath is the path of a category under the given parent path; an empty parent path makes it a root
+func CategoryPath(parentPath string, id uuid.UUID) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return parentPath + id.String() + "/"
+}
+
+// Contains checks if a category is within the subtree of c, including c itself
+func (c *Category) Contains(other *Category) bool {
+	return strings.HasPrefix(other.Path, c.Path)
+}
+
+// BuildCategoryTree nests categories under their parents and returns the roots. Categories are
+// expected in sibling order; ones whose parent is missing are treated as roots.
+func BuildCategoryTree(categories []*Category) []*Category {
+	byID := make(map[uuid.UUID]*Category, len(categories))
+	for _, c := range categories {
+		c.Children = nil
+		byID[c.ID] = c
+	}
+
+	roots := []*Category{}
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+
+	return roots
+}
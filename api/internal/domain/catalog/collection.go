@@ -0,0 +1,106 @@
+package catalog
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CollectionType tells how the products of a collection are chosen
+type CollectionType string
+
+const (
+	CollectionTypeManual    CollectionType = "manual"    // Products are picked by hand
+	CollectionTypeAutomated CollectionType = "automated" // Products matching the rules are included
+)
+
+// IsValid checks if the type is a known collection type
+func (t CollectionType) IsValid() bool {
+	return t == CollectionTypeManual || t == CollectionTypeAutomated
+}
+
+// CollectionMatch tells if a product must match all or any of the rules of a collection
+type CollectionMatch string
+
+const (
+	CollectionMatchAll CollectionMatch = "all"
+	CollectionMatchAny CollectionMatch = "any"
+)
+
+// RuleField is the product attribute a collection rule compares
+type RuleField string
+
+const (
+	RuleFieldPrice RuleField = "price" // Matches when any enabled variant's price compares true
+	RuleFieldTag   RuleField = "tag"
+	RuleFieldName  RuleField = "name"
+)
+
+// RuleOperator is the comparison of a collection rule
+type RuleOperator string
+
+const (
+	RuleOperatorEq       RuleOperator = "eq"
+	RuleOperatorNeq      RuleOperator = "neq"
+	RuleOperatorLt       RuleOperator = "lt"
+	RuleOperatorLte      RuleOperator = "lte"
+	RuleOperatorGt       RuleOperator = "gt"
+	RuleOperatorGte      RuleOperator = "gte"
+	RuleOperatorContains RuleOperator = "contains"
+)
+
+// ruleOperators lists the operators each field supports
+var ruleOperators = map[RuleField][]RuleOperator{
+	RuleFieldPrice: {RuleOperatorEq, RuleOperatorNeq, RuleOperatorLt, RuleOperatorLte, RuleOperatorGt, RuleOperatorGte},
+	RuleFieldTag:   {RuleOperatorEq, RuleOperatorNeq},
+	RuleFieldName:  {RuleOperatorEq, RuleOperatorNeq, RuleOperatorContains},
+}
+
+// CollectionRule is a condition products of an automated collection must meet,
+// e.g. {"field": "price", "operator": "lt", "value": "100000"}
+type CollectionRule struct {
+	Field    RuleField    `json:"field"`
+	Operator RuleOperator `json:"operator"`
+	Value    string       `json:"value"`
+}
+
+// IsValid checks if the rule compares a known field with one of its operators and a suitable value
+func (r CollectionRule) IsValid() bool {
+	operators, ok := ruleOperators[r.Field]
+	if !ok || r.Value == "" {
+		return false
+	}
+
+	if r.Field == RuleFieldPrice {
+		if price, err := strconv.ParseInt(r.Value, 10, 64); err != nil || price < 0 {
+			return false
+		}
+	}
+
+	for _, op := range operators {
+		if op == r.Operator {
+			return true
+		}
+	}
+	return false
+}
+
+// Collection is a curated group of products, either picked by hand or matched by rules
+type Collection struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Slug        string           `json:"slug"`
+	Description string           `json:"description"`
+	Type        CollectionType   `json:"type"`
+	Match       CollectionMatch  `json:"match"`
+	Rules       []CollectionRule `json:"rules"`
+	ProductIDs  []uuid.UUID      `json:"product_ids,omitempty"` // Products of a manual collection, in order
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// IsAutomated checks if the products of the collection are matched by rules
+func (c *Collection) IsAutomated() bool {
+	return c.Type == CollectionTypeAutomated
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MovementType tells why the stock of a variant changed
+type MovementType string
+
+const (
+	MovementTypeReceipt    MovementType = "receipt"    // Stock received, e.g. from a supplier
+	MovementTypeSale       MovementType = "sale"       // Stock sold at the till or through a checkout
+	MovementTypeReturn     MovementType = "return"     // Sold stock brought back by a customer
+	MovementTypeAdjustment MovementType = "adjustment" // Correction after a count, damage or loss
+	MovementTypeTransfer   MovementType = "transfer"   // Stock moved between locations
+)
+
+// IsValid checks if the type is a known movement type
+func (t MovementType) IsValid() bool {
+	switch t {
+	case MovementTypeReceipt, MovementTypeSale, MovementTypeReturn, MovementTypeAdjustment, MovementTypeTransfer:
+		return true
+	}
+	return false
+}
+
+// IsManual checks if admins may record movements of this type by hand; sales and transfers
+// are recorded by their own flows
+func (t MovementType) IsManual() bool {
+	return t == MovementTypeReceipt || t == MovementTypeReturn || t == MovementTypeAdjustment
+}
+
+// AllowsQuantity checks if a movement of this type may change stock by the signed quantity:
+// receipts and returns add stock, sales take it away and adjustments go either way
+func (t MovementType) AllowsQuantity(quantity int) bool {
+	switch t {
+	case MovementTypeReceipt, MovementTypeReturn:
+		return quantity > 0
+	case MovementTypeSale:
+		return quantity < 0
+	case MovementTypeAdjustment, MovementTypeTransfer:
+		return quantity != 0
+	}
+	return false
+}
+
+// StockMovement is an entry of the append-only stock ledger; the stock of a variant at a location
+// is the sum of the quantities of its movements there. Entries are never changed, mistakes are
+// corrected by new ones.
+type StockMovement struct {
+	ID         uuid.UUID    `json:"id"`
+	VariantID  uuid.UUID    `json:"variant_id"`
+	LocationID uuid.UUID    `json:"location_id"`
+	SKU        string       `json:"sku"` // SKU at the time of the movement
+	Type       MovementType `json:"type"`
+	Quantity   int          `json:"quantity"` // Positive adds stock, negative takes it away
+	Reason     string       `json:"reason"`
+	Reference  *string      `json:"reference"` // e.g. a purchase order, receipt number, reservation or transfer ID
+	ActorID    *uuid.UUID   `json:"actor_id"`  // Admin who recorded the movement, empty for checkouts
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// StockLevel is the computed stock of a variant at a location
+type StockLevel struct {
+	VariantID  uuid.UUID `json:"variant_id"`
+	LocationID uuid.UUID `json:"location_id"`
+	SKU        string    `json:"sku"`
+	OnHand     int       `json:"on_hand"`    // Sum of the movements at the location
+	Reserved   int       `json:"reserved"`   // Held by active checkout reservations
+	Available  int       `json:"available"`  // On hand minus reserved, what can still be sold
+	InTransit  int       `json:"in_transit"` // Shipped to the location by transfers not received yet
+}
+
+// ReservationStatus represents where a stock reservation is in its lifecycle
+type ReservationStatus string
+
+const (
+	ReservationStatusActive    ReservationStatus = "active"    // Holding stock until it expires
+	ReservationStatusReleased  ReservationStatus = "released"  // Given up, the stock is available again
+	ReservationStatusCommitted ReservationStatus = "committed" // Turned into sale movements by order placement, which does not exist yet
+)
+
+// Reservation holds stock for a customer during checkout so it cannot be sold to someone else.
+// An active reservation stops holding stock once it expires.
+type Reservation struct {
+	ID         uuid.UUID         `json:"id"`
+	CustomerID *uuid.UUID        `json:"customer_id"`
+	LocationID uuid.UUID         `json:"location_id"` // Location the stock is held at
+	Status     ReservationStatus `json:"status"`
+	Items      []ReservationItem `json:"items"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// ReservationItem is the quantity of a variant held by a reservation
+type ReservationItem struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+// IsActive checks if the reservation still holds stock at the given time
+func (r *Reservation) IsActive(now time.Time) bool {
+	return r.Status == ReservationStatusActive && now.Before(r.ExpiresAt)
+}
+
+// MergeReservationItems adds up the quantities of items of the same variant, keeping the
+// order in which variants first appear
+func MergeReservationItems(items []ReservationItem) []ReservationItem {
+	merged := make([]ReservationItem, 0, len(items))
+	index := make(map[uuid.UUID]int, len(items))
+	for _, item := range items {
+		if i, ok := index[item.VariantID]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.VariantID] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
@@ -0,0 +1,44 @@
+package catalog
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocationType tells what kind of place a location is
+type LocationType string
+
+const (
+	LocationTypeWarehouse LocationType = "warehouse" // Holds stock without selling over the counter
+	LocationTypeStore     LocationType = "store"     // Physical shop where cashiers sell at the till
+)
+
+// IsValid checks if the type is a known location type
+func (t LocationType) IsValid() bool {
+	return t == LocationTypeWarehouse || t == LocationTypeStore
+}
+
+// Location is a place holding stock. Every movement of the ledger happens at a location, so the
+// stock of a variant at a location is the sum of the movements recorded there. At most one location
+// fulfils online orders; storefront stock and checkout reservations come from it. Locations are
+// deactivated rather than deleted, as the ledger keeps referring to them.
+type Location struct {
+	ID             uuid.UUID    `json:"id"`
+	Name           string       `json:"name"`
+	Code           string       `json:"code"` // Short unique code, e.g. "MAIN" or "JKT-01"
+	Type           LocationType `json:"type"`
+	Address        string       `json:"address"`
+	FulfillsOnline bool         `json:"fulfills_online"`
+	IsActive       bool         `json:"is_active"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+var locationCodePattern = regexp.MustCompile(`^[A-Z0-9]+(?:-[A-Z0-9]+)*$`)
+
+// IsValidLocationCode checks if a location code is uppercase letters and digits separated by single hyphens
+func IsValidLocationCode(code string) bool {
+	return locationCodePattern.MatchString(code)
+}
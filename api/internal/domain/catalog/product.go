@@ -0,0 +1,107 @@
+package catalog
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductStatus represents where a product is in its lifecycle
+type ProductStatus string
+
+const (
+	ProductStatusDraft    ProductStatus = "draft"    // Being prepared, hidden from the storefront
+	ProductStatusActive   ProductStatus = "active"   // Listed on the storefront
+	ProductStatusArchived ProductStatus = "archived" // No longer sold, hidden from the storefront
+)
+
+// IsValid checks if the status is a known product status
+func (s ProductStatus) IsValid() bool {
+	switch s {
+	case ProductStatusDraft, ProductStatusActive, ProductStatusArchived:
+		return true
+	}
+	return false
+}
+
+// Product represents an item of the catalog
+type Product struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Slug        string           `json:"slug"`
+	Description string           `json:"description"`
+	Status      ProductStatus    `json:"status"`
+	Tags        []string         `json:"tags"`
+	CategoryIDs []uuid.UUID      `json:"category_ids"`
+	Images      []ProductImage   `json:"images"`
+	Options     []ProductOption  `json:"options"`
+	Variants    []ProductVariant `json:"variants"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	DeletedAt   *time.Time       `json:"deleted_at,omitempty"`
+}
+
+// ProductImage is an image of a product; images are shown in order of position
+type ProductImage struct {
+	ID       uuid.UUID `json:"id"`
+	URL      string    `json:"url"`
+	AltText  string    `json:"alt_text"`
+	Position int       `json:"position"`
+}
+
+// IsDeleted checks if the product is soft deleted
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
+// IsPublished checks if the product is listed on the storefront
+func (p *Product) IsPublished() bool {
+	return p.Status == ProductStatusActive && !p.IsDeleted()
+}
+
+// EnabledVariants returns the variants that are for sale
+func (p *Product) EnabledVariants() []ProductVariant {
+	variants := []ProductVariant{}
+	for _, v := range p.Variants {
+		if v.IsEnabled {
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
+
+// Variant returns the variant with the given ID
+func (p *Product) Variant(id uuid.UUID) (*ProductVariant, bool) {
+	for i := range p.Variants {
+		if p.Variants[i].ID == id {
+			return &p.Variants[i], true
+		}
+	}
+	return nil, false
+}
+
+// ProductFilter narrows down product queries; zero values are ignored
+type ProductFilter struct {
+	Search       string
+	Status       ProductStatus
+	Tag          string
+	CategoryPath string      // Products in the category with this path or any of its descendants
+	Collection   *Collection // Products picked for or matching the rules of the collection
+}
+
+var (
+	slugPattern   = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// IsValidSlug checks if a slug is lowercase letters and digits separated by single hyphens
+func IsValidSlug(slug string) bool {
+	return slugPattern.MatchString(slug)
+}
+
+// Slugify derives a slug from a name, e.g. "Kopi Susu 250ml" becomes "kopi-susu-250ml"
+func Slugify(name string) string {
+	return strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
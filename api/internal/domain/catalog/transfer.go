@@ -0,0 +1,88 @@
+package catalog
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferStatus represents where a transfer order is in its lifecycle
+type TransferStatus string
+
+const (
+	TransferStatusDraft     TransferStatus = "draft"      // Being prepared, no stock has moved
+	TransferStatusInTransit TransferStatus = "in_transit" // Shipped, the stock has left the source but not reached the destination
+	TransferStatusReceived  TransferStatus = "received"   // The stock arrived at the destination
+	TransferStatusCancelled TransferStatus = "cancelled"  // Given up, shipped stock went back to the source
+)
+
+// IsValid checks if the status is a known transfer status
+func (s TransferStatus) IsValid() bool {
+	switch s {
+	case TransferStatusDraft, TransferStatusInTransit, TransferStatusReceived, TransferStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// TransferOrder moves stock of variants from one location to another. Shipping records transfer
+// movements taking the stock from the source, receiving records the ones adding it at the
+// destination; in between the stock is in transit and counted at neither location.
+type TransferOrder struct {
+	ID             uuid.UUID      `json:"id"`
+	FromLocationID uuid.UUID      `json:"from_location_id"`
+	ToLocationID   uuid.UUID      `json:"to_location_id"`
+	Status         TransferStatus `json:"status"`
+	Note           string         `json:"note"`
+	Items          []TransferItem `json:"items"`
+	CreatedBy      *uuid.UUID     `json:"created_by"`
+	ShippedAt      *time.Time     `json:"shipped_at"`
+	ReceivedAt     *time.Time     `json:"received_at"`
+	CancelledAt    *time.Time     `json:"cancelled_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// TransferItem is the quantity of a variant moved by a transfer order
+type TransferItem struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	SKU       string    `json:"sku"` // SKU at the time the transfer was created
+	Quantity  int       `json:"quantity"`
+}
+
+// TransferFilter narrows down transfer queries; zero values are ignored
+type TransferFilter struct {
+	Status     TransferStatus
+	LocationID *uuid.UUID // Transfers from or to the location
+}
+
+// CanShip checks if the transfer can be shipped, which only drafts can
+func (t *TransferOrder) CanShip() bool {
+	return t.Status == TransferStatusDraft
+}
+
+// CanReceive checks if the transfer can be received, which only shipped ones can
+func (t *TransferOrder) CanReceive() bool {
+	return t.Status == TransferStatusInTransit
+}
+
+// CanCancel checks if the transfer can be cancelled, which it can until received
+func (t *TransferOrder) CanCancel() bool {
+	return t.Status == TransferStatusDraft || t.Status == TransferStatusInTransit
+}
+
+// MergeTransferItems adds up the quantities of items of the same variant, keeping the
+// order in which variants first appear
+func MergeTransferItems(items []TransferItem) []TransferItem {
+	merged := make([]TransferItem, 0, len(items))
+	index := make(map[uuid.UUID]int, len(items))
+	for _, item := range items {
+		if i, ok := index[item.VariantID]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.VariantID] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
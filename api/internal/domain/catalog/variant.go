@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxVariants is the largest variant matrix a product may have
+const MaxVariants = 250
+
+// MaxSKULength is the longest SKU a variant may have
+const MaxSKULength = 100
+
+// ProductOption is an option type of a product, e.g. Size with the values S, M and L.
+// Options and their values are ordered by position, which is also the order of the SKU parts.
+type ProductOption struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Values   []string  `json:"values"`
+	Position int       `json:"position"`
+}
+
+// ProductVariant is a sellable combination of option values of a product, identified by its SKU.
+// A product without options has a single variant with no option values.
+type ProductVariant struct {
+	ID        uuid.UUID         `json:"id"`
+	SKU       string            `json:"sku"`
+	Options   map[string]string `json:"options"` // Option name to value, e.g. {"Size": "M", "Color": "Red"}
+	Price     int64             `json:"price"`   // In the smallest currency unit
+	Barcode   *string           `json:"barcode"`
+	Weight    int               `json:"weight"`     // In grams
+	Stock     int               `json:"stock"`      // Stock available for online orders, computed from the inventory ledger
+	IsEnabled bool              `json:"is_enabled"` // Disabled combinations are not sold
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Key identifies the combination of option values of the variant
+func (v *ProductVariant) Key() string {
+	return VariantKey(v.Options)
+}
+
+// VariantKey identifies a combination of option values regardless of the order of the options
+func VariantKey(options map[string]string) string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(options[name])
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+// VariantCount is the size of the variant matrix of the options
+func VariantCount(options []ProductOption) int {
+	count := 1
+	for _, option := range options {
+		count *= len(option.Values)
+	}
+	return count
+}
+
+// BuildVariants builds the variant matrix of a product's options, every combination of one value
+// per option. Variants in existing whose combination is still part of the matrix are kept as they
+// are; new combinations get a generated SKU, the given price and are enabled. Variants to be
+// created have no ID.
+func BuildVariants(slug string, options []ProductOption, existing []ProductVariant, price int64) []ProductVariant {
+	byKey := make(map[string]ProductVariant, len(existing))
+	for _, v := range existing {
+		byKey[v.Key()] = v
+	}
+
+	// Start with the single empty combination and multiply it by the values of each option
+	combinations := [][]string{{}}
+	for _, option := range options {
+		next := make([][]string, 0, len(combinations)*len(option.Values))
+		for _, combination := range combinations {
+			for _, value := range option.Values {
+				next = append(next, append(append([]string{}, combination...), value))
+			}
+		}
+		combinations = next
+	}
+
+	variants := make([]ProductVariant, 0, len(combinations))
+	for _, values := range combinations {
+		selected := make(map[string]string, len(values))
+		for i, value := range values {
+			selected[options[i].Name] = value
+		}
+
+		if v, ok := byKey[VariantKey(selected)]; ok {
+			variants = append(variants, v)
+			continue
+		}
+
+		variants = append(variants, ProductVariant{
+			SKU:       GenerateSKU(slug, values),
+			Options:   selected,
+			Price:     price,
+			IsEnabled: true,
+		})
+	}
+
+	return variants
+}
+
+// GenerateSKU derives a SKU from a product slug and option values, e.g. "KAOS-POLOS-M-RED".
+// Long SKUs are cut from the start of the slug so the option values stay distinguishable.
+func GenerateSKU(slug string, values []string) string {
+	parts := []string{slug}
+	for _, value := range values {
+		parts = append(parts, Slugify(value))
+	}
+
+	sku := strings.ToUpper(Slugify(strings.Join(parts, " ")))
+	if len(sku) > MaxSKULength {
+		sku = strings.TrimLeft(sku[len(sku)-MaxSKULength:], "-")
+	}
+	return sku
+}
@@ -1,6 +1,11 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Common domain errors
 var (
@@ -10,9 +15,89 @@ var (
 	ErrSessionNotFound    = errors.New("session not found")
 	ErrUnauthorized       = errors.New("unauthorized access")
 
+	// Authorization errors
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrRoleNotGrantable  = errors.New("cannot grant or manage a role above your own")
+	ErrRoleNotEditable   = errors.New("role permissions cannot be changed")
+	ErrInvalidRole       = errors.New("invalid role")
+	ErrInvalidPermission = errors.New("invalid permission")
+
+	// Two-factor authentication errors
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotInitiated   = errors.New("two-factor authentication setup has not been started")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor authentication code")
+	ErrChallengeNotFound       = errors.New("two-factor challenge not found")
+	ErrChallengeExpired        = errors.New("two-factor challenge has expired")
+
+	// Passkey errors
+	ErrInvalidPasskey           = errors.New("passkey could not be verified")
+	ErrPasskeyNotFound          = errors.New("passkey not found")
+	ErrPasskeyChallengeNotFound = errors.New("passkey challenge not found or has expired")
+
+	// API key errors
+	ErrInvalidAPIKey       = errors.New("API key is invalid, expired or revoked")
+	ErrAPIKeyNotFound      = errors.New("API key not found")
+	ErrAPIKeyScopeDenied   = errors.New("API key scope is not granted to your role")
+	ErrInvalidAPIKeyExpiry = errors.New("API key must expire in the future and within the maximum lifetime")
+
+	// Password reset errors
+	ErrInvalidResetToken = errors.New("password reset token is invalid or has expired")
+
+	// Magic link errors
+	ErrInvalidMagicLink         = errors.New("login link is invalid or has expired")
+	ErrMagicLinkBrowserMismatch = errors.New("login link must be opened in the browser that requested it")
+
+	// Social login errors
+	ErrOIDCProviderNotFound = errors.New("login provider not found")
+	ErrInvalidOIDCLogin     = errors.New("social login is invalid or has expired")
+	ErrOIDCEmailNotVerified = errors.New("email of the social login is missing or not verified")
+	ErrIdentityNotFound     = errors.New("linked identity not found")
+
+	// Email verification errors
+	ErrInvalidVerificationLink = errors.New("verification link is invalid or has expired")
+	ErrEmailAlreadyVerified    = errors.New("email is already verified")
+	ErrEmailNotVerified        = errors.New("email is not verified")
+
+	// Catalog errors
+	ErrProductNotFound       = errors.New("product not found")
+	ErrProductSlugTaken      = errors.New("product slug is already taken")
+	ErrInvalidProductStatus  = errors.New("invalid product status")
+	ErrInvalidSlug           = errors.New("slug must be lowercase letters and digits separated by hyphens")
+	ErrVariantNotFound       = errors.New("product variant not found")
+	ErrVariantSKUTaken       = errors.New("SKU is already taken")
+	ErrTooManyVariants       = errors.New("product options make too many variants")
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategorySlugTaken     = errors.New("category slug is already taken")
+	ErrCategoryHasChildren   = errors.New("category has subcategories")
+	ErrInvalidCategoryParent = errors.New("category cannot be moved into its own subtree")
+	ErrCollectionNotFound    = errors.New("collection not found")
+	ErrCollectionSlugTaken   = errors.New("collection slug is already taken")
+	ErrInvalidCollectionRule = errors.New("invalid collection rule")
+	ErrCollectionNotManual   = errors.New("products can only be picked for manual collections")
+
+	// Inventory errors
+	ErrInsufficientStock   = errors.New("not enough stock available")
+	ErrInvalidMovement     = errors.New("quantity does not match the stock movement type")
+	ErrVariantHasStock     = errors.New("variants with stock on hand, reserved or in transit cannot be removed")
+	ErrReservationNotFound = errors.New("stock reservation not found")
+	ErrReservationInactive = errors.New("stock reservation was released, committed or has expired")
+	ErrLocationNotFound    = errors.New("location not found")
+	ErrLocationCodeTaken   = errors.New("location code is already taken")
+	ErrInvalidLocationCode = errors.New("location code must be uppercase letters and digits separated by hyphens")
+	ErrInvalidLocationType = errors.New("invalid location type")
+	ErrLocationInactive    = errors.New("location is inactive")
+	ErrNoOnlineLocation    = errors.New("no location fulfils online orders")
+	ErrNoHomeLocation      = errors.New("admin has no home location")
+	ErrLocationNotAllowed  = errors.New("cashiers can only sell from their home location")
+	ErrTransferNotFound    = errors.New("transfer order not found")
+	ErrInvalidTransfer     = errors.New("transfer must move stock between two different locations")
+	ErrTransferStatus      = errors.New("transfer order cannot do this in its current status")
+
 	// User errors
 	ErrUserNotFound       = errors.New("user not found")
 	ErrUserInactive       = errors.New("user account is inactive")
+	ErrAccountLocked      = errors.New("account is temporarily locked")
 	ErrUserDeleted        = errors.New("user account has been deleted")
 	ErrEmailAlreadyExists = errors.New("email already exists")
 	ErrInvalidEmail       = errors.New("invalid email format")
@@ -23,7 +108,50 @@ var (
 	ErrRequiredField = errors.New("required field is missing")
 
 	// General errors
-	ErrNotFound       = errors.New("resource not found")
-	ErrForbidden      = errors.New("forbidden access")
-	ErrInternalServer = errors.New("internal server error")
+	ErrNotFound        = errors.New("resource not found")
+	ErrForbidden       = errors.New("forbidden access")
+	ErrInternalServer  = errors.New("internal server error")
+	ErrTooManyRequests = errors.New("too many requests")
 )
+
+// ThrottledError is returned when an action is rate limited; it matches ErrTooManyRequests
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many requests, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+// Is makes errors.Is(err, ErrTooManyRequests) match throttled errors
+func (e *ThrottledError) Is(target error) bool {
+	return target == ErrTooManyRequests
+}
+
+// AccountLockedError is returned when too many failed logins locked an account; it matches ErrAccountLocked
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account is temporarily locked, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+// Is makes errors.Is(err, ErrAccountLocked) match lockout errors
+func (e *AccountLockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
+
+// WeakPasswordError lists the password policy rules a new password breaks; it matches ErrWeakPassword
+type WeakPasswordError struct {
+	Problems []string
+}
+
+func (e *WeakPasswordError) Error() string {
+	return "password does not meet security requirements: " + strings.Join(e.Problems, ", ")
+}
+
+// Is makes errors.Is(err, ErrWeakPassword) match weak password errors
+func (e *WeakPasswordError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
@@ -0,0 +1,83 @@
+package shared
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginAttemptResult is the outcome recorded in the login attempt ledger
+type LoginAttemptResult string
+
+const (
+	LoginAttemptSuccess LoginAttemptResult = "success"
+	LoginAttemptFailure LoginAttemptResult = "failure"
+	LoginAttemptUnlock  LoginAttemptResult = "unlock" // An admin lifted a lockout
+)
+
+// LoginAttempt represents an entry in the login attempt ledger
+type LoginAttempt struct {
+	ID        uuid.UUID          `json:"id"`
+	UserType  UserType           `json:"user_type"`
+	Email     string             `json:"email"`
+	IPAddress string             `json:"ip_address"`
+	UserAgent string             `json:"user_agent"`
+	Result    LoginAttemptResult `json:"result"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// LoginFailures summarizes consecutive failed logins since the last success or unlock
+type LoginFailures struct {
+	Count  int
+	LastAt time.Time
+}
+
+// LoginThrottlePolicy controls progressive delays and lockout after failed logins.
+// Every failure doubles the delay before the next attempt, starting at BaseDelay and
+// capped at MaxDelay, until MaxAttempts failures lock the account for LockoutDuration.
+type LoginThrottlePolicy struct {
+	MaxAttempts     int
+	LockoutDuration time.Duration
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+}
+
+// IsLocked checks if the failures lock the account
+func (p LoginThrottlePolicy) IsLocked(failures LoginFailures) bool {
+	return p.MaxAttempts > 0 && failures.Count >= p.MaxAttempts
+}
+
+// RetryAfter returns how long to wait before the next login attempt is accepted
+func (p LoginThrottlePolicy) RetryAfter(failures LoginFailures, now time.Time) time.Duration {
+	if failures.Count == 0 {
+		return 0
+	}
+
+	var next time.Time
+	if p.IsLocked(failures) {
+		next = failures.LastAt.Add(p.LockoutDuration)
+	} else {
+		next = failures.LastAt.Add(p.delay(failures.Count))
+	}
+
+	if wait := next.Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// delay returns the progressive delay after the given number of failures
+func (p LoginThrottlePolicy) delay(count int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < count; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
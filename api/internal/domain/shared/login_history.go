@@ -0,0 +1,43 @@
+package shared
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginHistory represents an entry in the append-only login history of an account
+type LoginHistory struct {
+	ID          uuid.UUID          `json:"id"`
+	UserType    UserType           `json:"user_type"`
+	UserID      uuid.UUID          `json:"user_id"`
+	Result      LoginAttemptResult `json:"result"`
+	IPAddress   string             `json:"ip_address"`
+	UserAgent   string             `json:"user_agent"`
+	Browser     string             `json:"browser"`
+	OS          string             `json:"os"`
+	Device      string             `json:"device"`
+	IsNewDevice bool               `json:"is_new_device"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// Device types parsed from the user agent
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceBot     = "bot"
+)
+
+// DeviceLabel describes the device of a login for people, e.g. "Chrome on Windows 10"
+func (h *LoginHistory) DeviceLabel() string {
+	switch {
+	case h.Browser != "" && h.OS != "":
+		return h.Browser + " on " + h.OS
+	case h.Browser != "":
+		return h.Browser
+	case h.OS != "":
+		return h.OS
+	default:
+		return "Unknown device"
+	}
+}
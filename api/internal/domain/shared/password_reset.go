@@ -0,0 +1,16 @@
+package shared
+
+import "time"
+
+// PasswordResetToken represents a pending password reset request
+type PasswordResetToken struct {
+	Email     string    `json:"email"`
+	UserType  UserType  `json:"user_type"`
+	Token     string    `json:"-"` // Hash of the token sent by email, never expose
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsExpired checks if the reset token is older than the allowed lifetime
+func (t *PasswordResetToken) IsExpired(lifetime time.Duration) bool {
+	return time.Now().After(t.CreatedAt.Add(lifetime))
+}
@@ -0,0 +1,60 @@
+package shared
+
+import "time"
+
+// maxRefreshInterval caps how often last activity is written for long idle timeouts
+const maxRefreshInterval = 15 * time.Minute
+
+// SessionPolicy limits how long a session stays valid.
+// IdleTimeout counts from the last activity, Lifetime from login; zero disables a limit.
+type SessionPolicy struct {
+	IdleTimeout time.Duration
+	Lifetime    time.Duration
+}
+
+// ExpiresAt returns when a session with the given timestamps expires if it stays idle
+func (p SessionPolicy) ExpiresAt(createdAt, lastActivityAt time.Time) time.Time {
+	var expiresAt time.Time
+
+	if p.Lifetime > 0 {
+		expiresAt = createdAt.Add(p.Lifetime)
+	}
+
+	if p.IdleTimeout > 0 {
+		idleAt := lastActivityAt.Add(p.IdleTimeout)
+		if expiresAt.IsZero() || idleAt.Before(expiresAt) {
+			expiresAt = idleAt
+		}
+	}
+
+	return expiresAt
+}
+
+// IsExpired checks if a session with the given timestamps is no longer valid
+func (p SessionPolicy) IsExpired(createdAt, lastActivityAt time.Time) bool {
+	expiresAt := p.ExpiresAt(createdAt, lastActivityAt)
+	return !expiresAt.IsZero() && time.Now().After(expiresAt)
+}
+
+// CookieMaxAge returns the Max-Age in seconds of a cookie holding a session created at createdAt,
+// so the browser drops it when the lifetime ends. Without a lifetime it is 0, a browser session cookie.
+func (p SessionPolicy) CookieMaxAge(createdAt time.Time) int {
+	if p.Lifetime <= 0 {
+		return 0
+	}
+
+	remaining := int(time.Until(createdAt.Add(p.Lifetime)).Seconds())
+	if remaining <= 0 {
+		return -1
+	}
+	return remaining
+}
+
+// RefreshInterval returns how stale last activity may get before it is updated,
+// a tenth of the idle timeout so short timeouts are tracked closely enough
+func (p SessionPolicy) RefreshInterval() time.Duration {
+	if p.IdleTimeout <= 0 || p.IdleTimeout/10 > maxRefreshInterval {
+		return maxRefreshInterval
+	}
+	return p.IdleTimeout / 10
+}
@@ -0,0 +1,28 @@
+package shared
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorAccount is the two-factor state of an admin or customer
+type TwoFactorAccount struct {
+	ID            uuid.UUID
+	Email         string
+	Password      string     // Password hash, re-verified before sensitive changes
+	Secret        *string    // Confirmed secret, enforced at login
+	PendingSecret *string    // Secret waiting for confirmation
+	RecoveryCodes *string    // Encoded hashes of the unused recovery codes
+	ConfirmedAt   *time.Time // When the current secret was confirmed, empty while 2FA is off
+}
+
+// HasTwoFactor checks if two-factor authentication is enabled
+func (a *TwoFactorAccount) HasTwoFactor() bool {
+	return a.ConfirmedAt != nil
+}
+
+// IsTwoFactorPending checks if a setup or regeneration is waiting for confirmation
+func (a *TwoFactorAccount) IsTwoFactorPending() bool {
+	return a.PendingSecret != nil
+}
@@ -0,0 +1,9 @@
+package shared
+
+// UserType identifies which account table a record belongs to
+type UserType string
+
+const (
+	UserTypeAdmin    UserType = "admin"
+	UserTypeCustomer UserType = "customer"
+)
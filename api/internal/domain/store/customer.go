@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	"github.com/yeftaz/susano.id/api/pkg/twofactor"
 )
 
 // Customer represents a customer user entity
@@ -16,6 +19,7 @@ type Customer struct {
 	IsActive               bool       `json:"is_active"`
 	EmailVerifiedAt        *time.Time `json:"email_verified_at,omitempty"`
 	TwoFactorSecret        *string    `json:"-"` // Never expose 2FA secret
+	TwoFactorPendingSecret *string    `json:"-"` // Secret waiting for confirmation, enforced only once confirmed
 	TwoFactorRecoveryCodes *string    `json:"-"` // Never expose recovery codes
 	TwoFactorConfirmedAt   *time.Time `json:"two_factor_confirmed_at,omitempty"`
 	CreatedAt              time.Time  `json:"created_at"`
@@ -33,6 +37,29 @@ func (c *Customer) HasTwoFactor() bool {
 	return c.TwoFactorConfirmedAt != nil
 }
 
+// IsTwoFactorPending checks if customer has started 2FA setup or regeneration but not confirmed it yet
+func (c *Customer) IsTwoFactorPending() bool {
+	return c.TwoFactorPendingSecret != nil
+}
+
+// TwoFactorAccount returns the two-factor state of the customer
+func (c *Customer) TwoFactorAccount() *shared.TwoFactorAccount {
+	return &shared.TwoFactorAccount{
+		ID:            c.ID,
+		Email:         c.Email,
+		Password:      c.Password,
+		Secret:        c.TwoFactorSecret,
+		PendingSecret: c.TwoFactorPendingSecret,
+		RecoveryCodes: c.TwoFactorRecoveryCodes,
+		ConfirmedAt:   c.TwoFactorConfirmedAt,
+	}
+}
+
+// RecoveryCodesRemaining returns the number of unused 2FA recovery codes
+func (c *Customer) RecoveryCodesRemaining() int {
+	return len(twofactor.DecodeRecoveryCodes(c.TwoFactorRecoveryCodes))
+}
+
 // CanPurchase checks if customer can make purchases
 func (c *Customer) CanPurchase() bool {
 	return c.IsActive && !c.IsDeleted()
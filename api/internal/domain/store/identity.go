@@ -0,0 +1,36 @@
+package store
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity represents an account at an OpenID Connect provider linked to a customer
+type Identity struct {
+	ID         uuid.UUID  `json:"id"`
+	CustomerID uuid.UUID  `json:"customer_id"`
+	Provider   string     `json:"provider"`
+	Subject    string     `json:"-"` // Account ID at the provider
+	Email      *string    `json:"email,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// OIDCState represents a pending login at an OpenID Connect provider
+type OIDCState struct {
+	ID           uuid.UUID `json:"id"`
+	Provider     string    `json:"provider"`
+	State        string    `json:"-"` // Never expose state in JSON
+	Nonce        string    `json:"-"`
+	CodeVerifier string    `json:"-"`
+	IPAddress    string    `json:"ip_address"`
+	UserAgent    string    `json:"user_agent"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// IsExpired checks if the login can no longer be completed
+func (s *OIDCState) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MagicLink represents a pending passwordless login link
+type MagicLink struct {
+	ID             uuid.UUID `json:"id"`
+	CustomerID     uuid.UUID `json:"customer_id"`
+	Token          string    `json:"-"` // Never expose token in JSON
+	BrowserBinding *string   `json:"-"` // Hash of the nonce given to the requesting browser, if any
+	IPAddress      string    `json:"ip_address"`
+	UserAgent      string    `json:"user_agent"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// IsExpired checks if the link can no longer be used
+func (l *MagicLink) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
+
+// IsBound checks if the link may only be used by the browser that requested it
+func (l *MagicLink) IsBound() bool {
+	return l.BrowserBinding != nil
+}
@@ -4,26 +4,38 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
 )
 
 // Session represents a customer session entity
 type Session struct {
-	ID             uuid.UUID `json:"id"`
-	CustomerID     uuid.UUID `json:"customer_id"`
-	Token          string    `json:"-"` // Never expose token in JSON
-	IPAddress      string    `json:"ip_address"`
-	UserAgent      string    `json:"user_agent"`
-	LastActivityAt time.Time `json:"last_activity_at"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             uuid.UUID  `json:"id"`
+	CustomerID     uuid.UUID  `json:"customer_id"`
+	Token          string     `json:"-"` // Never expose token in JSON
+	IPAddress      string     `json:"ip_address"`
+	UserAgent      string     `json:"user_agent"`
+	ImpersonatorID *uuid.UUID `json:"-"` // Admin who started the session on the customer's behalf
+	LastActivityAt time.Time  `json:"last_activity_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// IsImpersonated checks if the session was started by an admin impersonating the customer
+func (s *Session) IsImpersonated() bool {
+	return s.ImpersonatorID != nil
+}
+
+// IsExpired checks if the session has expired under the given policy
+func (s *Session) IsExpired(policy shared.SessionPolicy) bool {
+	return policy.IsExpired(s.CreatedAt, s.LastActivityAt)
 }
 
-// IsExpired checks if the session has expired based on session lifetime
-func (s *Session) IsExpired(sessionLifetime time.Duration) bool {
-	expiresAt := s.CreatedAt.Add(sessionLifetime)
-	return time.Now().After(expiresAt)
+// ExpiresAt returns when the session expires under the given policy if it stays idle
+func (s *Session) ExpiresAt(policy shared.SessionPolicy) time.Time {
+	return policy.ExpiresAt(s.CreatedAt, s.LastActivityAt)
 }
 
-// ShouldRefresh checks if session should be refreshed (last activity > 15 minutes ago)
-func (s *Session) ShouldRefresh() bool {
-	return time.Since(s.LastActivityAt) > 15*time.Minute
+// ShouldRefresh checks if the last activity timestamp is stale enough to be updated
+func (s *Session) ShouldRefresh(policy shared.SessionPolicy) bool {
+	return time.Since(s.LastActivityAt) > policy.RefreshInterval()
 }
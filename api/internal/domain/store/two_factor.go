@@ -0,0 +1,29 @@
+package store
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorChallenge represents a pending login that must be completed with a two-factor code
+type TwoFactorChallenge struct {
+	ID         uuid.UUID `json:"id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	Token      string    `json:"-"` // Never expose token in JSON
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Attempts   int       `json:"attempts"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IsExpired checks if the challenge can no longer be completed
+func (c *TwoFactorChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// HasAttemptsRemaining checks if the challenge still accepts codes
+func (c *TwoFactorChallenge) HasAttemptsRemaining(maxAttempts int) bool {
+	return c.Attempts < maxAttempts
+}
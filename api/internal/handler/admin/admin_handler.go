@@ -3,10 +3,14 @@ package admin
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/handler/shared"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
 	"github.com/yeftaz/susano.id/api/internal/service/admin"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
 	"github.com/yeftaz/susano.id/api/pkg/response"
@@ -27,7 +31,7 @@ func NewAdminHandler(adminService *admin.AdminService, logger *logger.Logger) *A
 
 type CreateAdminRequest struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required,max=72"` // Length and composition are checked by the password policy
 	Name     string `json:"name" validate:"required"`
 	Role     string `json:"role" validate:"required,oneof=super_admin admin cashier"`
 }
@@ -90,6 +94,12 @@ func (h *AdminHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 // Create handles POST /api/v1/admin/admins
 func (h *AdminHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
 	var req CreateAdminRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.Error(w, http.StatusBadRequest, "Invalid request body")
@@ -103,8 +113,19 @@ func (h *AdminHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create admin
-	admin, err := h.adminService.Create(r.Context(), req.Email, req.Password, req.Name, req.Role)
+	admin, err := h.adminService.Create(r.Context(), actor, req.Email, req.Password, req.Name, req.Role)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRole) {
+			response.ValidationError(w, map[string]string{"role": "role is not a known admin role"})
+			return
+		}
+		if errors.Is(err, domain.ErrRoleNotGrantable) {
+			response.Error(w, http.StatusForbidden, "Cannot grant a role above your own")
+			return
+		}
+		if shared.RespondWeakPassword(w, err) {
+			return
+		}
 		h.logger.Error("Failed to create admin", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to create admin")
 		return
@@ -116,6 +137,12 @@ func (h *AdminHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 // Update handles PATCH /api/v1/admin/admins/{id}
 func (h *AdminHandler) Update(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
 	vars := mux.Vars(r)
 	id := vars["id"]
 
@@ -132,12 +159,20 @@ func (h *AdminHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update admin
-	admin, err := h.adminService.Update(r.Context(), id, req.Email, req.Name, req.Role)
+	admin, err := h.adminService.Update(r.Context(), actor, id, req.Email, req.Name, req.Role)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			response.Error(w, http.StatusNotFound, "Admin not found")
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidRole) {
+			response.ValidationError(w, map[string]string{"role": "role is not a known admin role"})
+			return
+		}
+		if errors.Is(err, domain.ErrRoleNotGrantable) {
+			response.Error(w, http.StatusForbidden, "Cannot manage or grant a role above your own")
+			return
+		}
 		h.logger.Error("Failed to update admin", "id", id, "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to update admin")
 		return
@@ -149,15 +184,25 @@ func (h *AdminHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete handles DELETE /api/v1/admin/admins/{id}
 func (h *AdminHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
 	vars := mux.Vars(r)
 	id := vars["id"]
 
 	// Soft delete admin
-	if err := h.adminService.Delete(r.Context(), id); err != nil {
+	if err := h.adminService.Delete(r.Context(), actor, id); err != nil {
 		if err == sql.ErrNoRows {
 			response.Error(w, http.StatusNotFound, "Admin not found")
 			return
 		}
+		if errors.Is(err, domain.ErrRoleNotGrantable) {
+			response.Error(w, http.StatusForbidden, "Cannot manage an admin above your own role")
+			return
+		}
 		h.logger.Error("Failed to delete admin", "id", id, "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to delete admin")
 		return
@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/admin"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *admin.APIKeyService
+	logger        *logger.Logger
+}
+
+func NewAPIKeyHandler(apiKeyService *admin.APIKeyService, logger *logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		logger:        logger,
+	}
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string                   `json:"name" validate:"required,max=100"`
+	Scopes    []adminDomain.Permission `json:"scopes" validate:"required,min=1"`
+	ExpiresAt time.Time                `json:"expires_at" validate:"required"`
+	RateLimit int                      `json:"rate_limit" validate:"omitempty,min=1,max=10000"` // requests per minute, defaults to API_KEY_RATE_LIMIT
+}
+
+// List handles GET /api/v1/admin/auth/api-keys
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	currentAdmin, ok := middleware.AdminFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	keys, err := h.apiKeyService.List(r.Context(), currentAdmin.ID)
+	if err != nil {
+		h.logger.Error("Failed to list API keys", "admin_id", currentAdmin.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve API keys")
+		return
+	}
+
+	response.Success(w, keys, "API keys retrieved successfully")
+}
+
+// Create handles POST /api/v1/admin/auth/api-keys
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	key, err := h.apiKeyService.Create(r.Context(), actor, req.Name, req.Scopes, req.ExpiresAt, req.RateLimit)
+	if err != nil {
+		h.respondError(w, "API key creation failed", actor.Admin.ID.String(), err)
+		return
+	}
+
+	h.logger.Info("Admin API key created", "admin_id", actor.Admin.ID, "api_key_id", key.ID)
+	response.Created(w, key, "API key created successfully, store the key now as it will not be shown again")
+}
+
+// Revoke handles DELETE /api/v1/admin/auth/api-keys/{id}
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "API key not found")
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(r.Context(), actor, id); err != nil {
+		h.respondError(w, "API key revocation failed", actor.Admin.ID.String(), err)
+		return
+	}
+
+	h.logger.Info("Admin API key revoked", "admin_id", actor.Admin.ID, "api_key_id", id)
+	response.Success(w, nil, "API key revoked successfully")
+}
+
+// respondError maps API key service errors to HTTP responses
+func (h *APIKeyHandler) respondError(w http.ResponseWriter, message, adminID string, err error) {
+	h.logger.Error(message, "admin_id", adminID, "error", err)
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidPermission):
+		response.Error(w, http.StatusUnprocessableEntity, "Unknown permission")
+	case errors.Is(err, domain.ErrAPIKeyScopeDenied):
+		response.Error(w, http.StatusForbidden, "API keys can only be scoped to permissions of your role")
+	case errors.Is(err, domain.ErrInvalidAPIKeyExpiry):
+		response.Error(w, http.StatusUnprocessableEntity, "API key must expire in the future and within the maximum lifetime")
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		response.Error(w, http.StatusNotFound, "API key not found")
+	default:
+		response.Error(w, http.StatusInternalServerError, "Failed to update API keys")
+	}
+}
@@ -2,12 +2,18 @@ package admin
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
 	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/handler/shared"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
 	"github.com/yeftaz/susano.id/api/internal/service/admin"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/passkey"
 	"github.com/yeftaz/susano.id/api/pkg/response"
 	"github.com/yeftaz/susano.id/api/pkg/validator"
 )
@@ -36,6 +42,36 @@ type LoginResponse struct {
 	Token string      `json:"token"`
 }
 
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool                    `json:"two_factor_required"`
+	ChallengeToken    string                  `json:"challenge_token"`
+	ExpiresAt         time.Time               `json:"expires_at"`
+	Methods           []string                `json:"methods"`                   // "totp" and/or "passkey"
+	PasskeyOptions    *passkey.RequestOptions `json:"passkey_options,omitempty"` // For navigator.credentials.get()
+}
+
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required_without=RecoveryCode,omitempty,numeric,len=6"`
+	RecoveryCode   string `json:"recovery_code" validate:"required_without=Code"`
+}
+
+type PasskeyCredentialRequest struct {
+	ChallengeToken string          `json:"challenge_token" validate:"required"`
+	Credential     json.RawMessage `json:"credential" validate:"required"` // PublicKeyCredential from the browser, as JSON
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword      string `json:"current_password" validate:"required"`
+	Password             string `json:"password" validate:"required,max=72"`
+	PasswordConfirmation string `json:"password_confirmation" validate:"omitempty,eqfield=Password"`
+}
+
+type CurrentAdminResponse struct {
+	*adminDomain.Admin
+	RecoveryCodesRemaining int `json:"recovery_codes_remaining"`
+}
+
 // Login handles POST /api/v1/admin/auth/login
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
@@ -51,26 +87,157 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Authenticate admin
-	admin, session, err := h.authService.Login(r.Context(), req.Email, req.Password, r.RemoteAddr, r.UserAgent())
+	result, err := h.authService.Login(r.Context(), req.Email, req.Password, middleware.ClientIP(r), r.UserAgent())
 	if err != nil {
 		h.logger.Error("Login failed", "email", req.Email, "error", err)
-		response.Error(w, http.StatusUnauthorized, "Invalid email or password")
+		shared.RespondLoginError(w, err)
+		return
+	}
+
+	// Password was correct but a second factor is still required
+	if result.RequiresTwoFactor() {
+		methods := []string{}
+		if result.Admin.HasTwoFactor() {
+			methods = append(methods, "totp")
+		}
+		if result.PasskeyOptions != nil {
+			methods = append(methods, "passkey")
+		}
+
+		h.logger.Info("Admin two-factor challenge issued", "admin_id", result.Admin.ID, "methods", methods)
+		response.Success(w, TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.Challenge.Token,
+			ExpiresAt:         result.Challenge.ExpiresAt,
+			Methods:           methods,
+			PasskeyOptions:    result.PasskeyOptions,
+		}, "Two-factor authentication required")
 		return
 	}
 
-	h.logger.Info("Admin logged in", "admin_id", admin.ID, "email", admin.Email)
+	h.logger.Info("Admin logged in", "admin_id", result.Admin.ID, "email", result.Admin.Email)
 
 	// Set session cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session_token",
-		Value:    session.Token,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   h.config.SessionSecure,
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   int(h.config.SessionLifetime.Seconds()),
-		Domain:   h.config.SessionDomain,
-	})
+	h.setSessionCookie(w, result.Admin, result.Session)
+
+	response.Success(w, LoginResponse{
+		Admin: result.Admin,
+		Token: result.Session.Token,
+	}, "Login successful")
+}
+
+// VerifyTwoFactor handles POST /api/v1/admin/auth/2fa/verify
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req VerifyTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	// Complete the challenge
+	admin, session, err := h.authService.VerifyTwoFactor(r.Context(), req.ChallengeToken, req.Code, req.RecoveryCode, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		h.logger.Error("Two-factor verification failed", "error", err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+			response.Error(w, http.StatusUnauthorized, "Invalid two-factor authentication code")
+		case errors.Is(err, domain.ErrChallengeNotFound), errors.Is(err, domain.ErrChallengeExpired):
+			response.Error(w, http.StatusUnauthorized, "Two-factor challenge is invalid or has expired, please login again")
+		default:
+			shared.RespondLoginError(w, err)
+		}
+		return
+	}
+
+	h.logger.Info("Admin logged in", "admin_id", admin.ID, "email", admin.Email, "two_factor", true)
+
+	// Set session cookie
+	h.setSessionCookie(w, admin, session)
+
+	response.Success(w, LoginResponse{
+		Admin: admin,
+		Token: session.Token,
+	}, "Login successful")
+}
+
+// VerifyTwoFactorPasskey handles POST /api/v1/admin/auth/2fa/passkey
+func (h *AuthHandler) VerifyTwoFactorPasskey(w http.ResponseWriter, r *http.Request) {
+	var req PasskeyCredentialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	// Complete the challenge
+	admin, session, err := h.authService.VerifyTwoFactorPasskey(r.Context(), req.ChallengeToken, req.Credential, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		h.logger.Error("Two-factor passkey verification failed", "error", err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidPasskey):
+			response.Error(w, http.StatusUnauthorized, "Passkey could not be verified")
+		case errors.Is(err, domain.ErrChallengeNotFound), errors.Is(err, domain.ErrChallengeExpired):
+			response.Error(w, http.StatusUnauthorized, "Two-factor challenge is invalid or has expired, please login again")
+		default:
+			shared.RespondLoginError(w, err)
+		}
+		return
+	}
+
+	h.logger.Info("Admin logged in", "admin_id", admin.ID, "email", admin.Email, "two_factor", true, "passkey", true)
+
+	// Set session cookie
+	h.setSessionCookie(w, admin, session)
+
+	response.Success(w, LoginResponse{
+		Admin: admin,
+		Token: session.Token,
+	}, "Login successful")
+}
+
+// LoginWithPasskey handles POST /api/v1/admin/auth/passkey/login
+func (h *AuthHandler) LoginWithPasskey(w http.ResponseWriter, r *http.Request) {
+	var req PasskeyCredentialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	admin, session, err := h.authService.LoginWithPasskey(r.Context(), req.ChallengeToken, req.Credential, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		h.logger.Error("Passkey login failed", "error", err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidPasskey):
+			response.Error(w, http.StatusUnauthorized, "Passkey could not be verified")
+		case errors.Is(err, domain.ErrPasskeyChallengeNotFound):
+			response.Error(w, http.StatusUnauthorized, "Passkey challenge is invalid or has expired, please try again")
+		default:
+			shared.RespondLoginError(w, err)
+		}
+		return
+	}
+
+	h.logger.Info("Admin logged in", "admin_id", admin.ID, "email", admin.Email, "passkey", true)
+
+	// Set session cookie
+	h.setSessionCookie(w, admin, session)
 
 	response.Success(w, LoginResponse{
 		Admin: admin,
@@ -80,15 +247,21 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 // Logout handles POST /api/v1/admin/auth/logout
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	// Get session token from cookie
-	cookie, err := r.Cookie("session_token")
-	if err != nil {
+	// Get session token used to authenticate this request (bearer or cookie)
+	token, ok := middleware.SessionTokenFromContext(r.Context())
+	if !ok {
 		response.Error(w, http.StatusUnauthorized, "No session found")
 		return
 	}
 
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
 	// Delete session
-	if err := h.authService.Logout(r.Context(), cookie.Value); err != nil {
+	if err := h.authService.Logout(r.Context(), actor, token); err != nil {
 		h.logger.Error("Logout failed", "error", err)
 		response.Error(w, http.StatusInternalServerError, "Failed to logout")
 		return
@@ -111,26 +284,72 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 // GetCurrentUser handles GET /api/v1/admin/auth/me
 func (h *AuthHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	// Get admin from context (set by auth middleware)
-	admin, ok := r.Context().Value("admin").(*adminDomain.Admin)
+	admin, ok := middleware.AdminFromContext(r.Context())
 	if !ok {
 		response.Error(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
-	response.Success(w, admin, "Admin retrieved successfully")
+	response.Success(w, CurrentAdminResponse{
+		Admin:                  admin,
+		RecoveryCodesRemaining: admin.RecoveryCodesRemaining(),
+	}, "Admin retrieved successfully")
+}
+
+// ChangePassword handles POST /api/v1/admin/auth/password
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	session, ok := middleware.AdminSessionFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "No session found")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	if err := h.authService.ChangePassword(r.Context(), actor, session.ID, req.CurrentPassword, req.Password); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			response.Error(w, http.StatusUnprocessableEntity, "Current password is incorrect")
+			return
+		}
+		if shared.RespondWeakPassword(w, err) {
+			return
+		}
+		h.logger.Error("Password change failed", "admin_id", actor.Admin.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to change password")
+		return
+	}
+
+	h.logger.Info("Admin password changed", "admin_id", actor.Admin.ID)
+	response.Success(w, nil, "Password changed successfully, other sessions have been signed out")
 }
 
 // RefreshSession handles POST /api/v1/admin/auth/refresh
 func (h *AuthHandler) RefreshSession(w http.ResponseWriter, r *http.Request) {
-	// Get session token from cookie
-	cookie, err := r.Cookie("session_token")
-	if err != nil {
+	// Get session token used to authenticate this request (bearer or cookie)
+	token, ok := middleware.SessionTokenFromContext(r.Context())
+	if !ok {
 		response.Error(w, http.StatusUnauthorized, "No session found")
 		return
 	}
 
 	// Refresh session
-	if err := h.authService.RefreshSession(r.Context(), cookie.Value); err != nil {
+	if err := h.authService.RefreshSession(r.Context(), token); err != nil {
 		h.logger.Error("Session refresh failed", "error", err)
 		response.Error(w, http.StatusUnauthorized, "Failed to refresh session")
 		return
@@ -138,3 +357,17 @@ func (h *AuthHandler) RefreshSession(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, nil, "Session refreshed successfully")
 }
+
+// setSessionCookie sets the admin session cookie to expire with the absolute lifetime of the session
+func (h *AuthHandler) setSessionCookie(w http.ResponseWriter, admin *adminDomain.Admin, session *adminDomain.Session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session_token",
+		Value:    session.Token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   h.config.SessionSecure,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   h.authService.SessionPolicy(admin).CookieMaxAge(session.CreatedAt),
+		Domain:   h.config.SessionDomain,
+	})
+}
@@ -0,0 +1,233 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type CategoryHandler struct {
+	categoryService *catalog.CategoryService
+	logger          *logger.Logger
+}
+
+func NewCategoryHandler(categoryService *catalog.CategoryService, logger *logger.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		logger:          logger,
+	}
+}
+
+type CreateCategoryRequest struct {
+	Name        string     `json:"name" validate:"required,max=255"`
+	Slug        string     `json:"slug" validate:"omitempty,max=255"` // Derived from the name when empty
+	Description string     `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id"` // Creates a root category when empty
+}
+
+type UpdateCategoryRequest struct {
+	Name        *string `json:"name" validate:"omitnil,min=1,max=255"`
+	Slug        *string `json:"slug" validate:"omitnil,min=1,max=255"`
+	Description *string `json:"description"`
+}
+
+type MoveCategoryRequest struct {
+	ParentID *uuid.UUID `json:"parent_id"`                 // Moves the category to the root when empty
+	Position int        `json:"position" validate:"min=0"` // Index among the new siblings, past the end appends
+}
+
+// Tree handles GET /api/v1/admin/categories
+func (h *CategoryHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.categoryService.Tree(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get categories", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve categories")
+		return
+	}
+
+	response.Success(w, categories, "Categories retrieved successfully")
+}
+
+// GetByID handles GET /api/v1/admin/categories/{id}
+func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Category not found")
+		return
+	}
+
+	category, err := h.categoryService.GetByID(r.Context(), id)
+	if err != nil {
+		h.respondError(w, "Failed to get category", id.String(), err)
+		return
+	}
+
+	response.Success(w, category, "Category retrieved successfully")
+}
+
+// Create handles POST /api/v1/admin/categories
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	category, err := h.categoryService.Create(r.Context(), actor, catalog.CategoryInput{
+		Name:        &req.Name,
+		Slug:        &req.Slug,
+		Description: &req.Description,
+		ParentID:    req.ParentID,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			response.ValidationError(w, map[string]string{"parent_id": "parent_id must be an existing category"})
+			return
+		}
+		h.respondError(w, "Failed to create category", "", err)
+		return
+	}
+
+	h.logger.Info("Category created", "category_id", category.ID, "slug", category.Slug)
+	response.Created(w, category, "Category created successfully")
+}
+
+// Update handles PATCH /api/v1/admin/categories/{id}
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Category not found")
+		return
+	}
+
+	var req UpdateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	category, err := h.categoryService.Update(r.Context(), actor, id, catalog.CategoryInput{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.respondError(w, "Failed to update category", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Category updated", "category_id", id)
+	response.Success(w, category, "Category updated successfully")
+}
+
+// Move handles POST /api/v1/admin/categories/{id}/move
+func (h *CategoryHandler) Move(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Category not found")
+		return
+	}
+
+	var req MoveCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	category, err := h.categoryService.Move(r.Context(), actor, id, req.ParentID, req.Position)
+	if err != nil {
+		h.respondError(w, "Failed to move category", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Category moved", "category_id", id, "parent_id", category.ParentID, "position", category.Position)
+	response.Success(w, category, "Category moved successfully")
+}
+
+// Delete handles DELETE /api/v1/admin/categories/{id}
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Category not found")
+		return
+	}
+
+	if err := h.categoryService.Delete(r.Context(), actor, id); err != nil {
+		h.respondError(w, "Failed to delete category", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Category deleted", "category_id", id)
+	response.Success(w, nil, "Category deleted successfully")
+}
+
+// respondError maps category service errors to HTTP responses
+func (h *CategoryHandler) respondError(w http.ResponseWriter, message, categoryID string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCategoryNotFound):
+		response.Error(w, http.StatusNotFound, "Category not found")
+	case errors.Is(err, domain.ErrCategorySlugTaken):
+		response.ValidationError(w, map[string]string{"slug": "slug is already taken"})
+	case errors.Is(err, domain.ErrInvalidSlug):
+		response.ValidationError(w, map[string]string{"slug": "slug must be lowercase letters and digits separated by hyphens"})
+	case errors.Is(err, domain.ErrInvalidCategoryParent):
+		response.ValidationError(w, map[string]string{"parent_id": "a category cannot be moved into its own subtree"})
+	case errors.Is(err, domain.ErrCategoryHasChildren):
+		response.Error(w, http.StatusConflict, "Move or delete the subcategories first")
+	default:
+		h.logger.Error(message, "category_id", categoryID, "error", err)
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
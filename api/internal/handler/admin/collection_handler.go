@@ -0,0 +1,305 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	catalogDomain "github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type CollectionHandler struct {
+	collectionService *catalog.CollectionService
+	logger            *logger.Logger
+}
+
+func NewCollectionHandler(collectionService *catalog.CollectionService, logger *logger.Logger) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+		logger:            logger,
+	}
+}
+
+type CollectionRuleRequest struct {
+	Field    string `json:"field" validate:"required,oneof=price tag name"`
+	Operator string `json:"operator" validate:"required,oneof=eq neq lt lte gt gte contains"`
+	Value    string `json:"value" validate:"required,max=255"`
+}
+
+type CreateCollectionRequest struct {
+	Name        string                  `json:"name" validate:"required,max=255"`
+	Slug        string                  `json:"slug" validate:"omitempty,max=255"` // Derived from the name when empty
+	Description string                  `json:"description"`
+	Type        string                  `json:"type" validate:"required,oneof=manual automated"`
+	Match       string                  `json:"match" validate:"omitempty,oneof=all any"`
+	Rules       []CollectionRuleRequest `json:"rules" validate:"max=20,dive"` // Only for automated collections
+}
+
+type UpdateCollectionRequest struct {
+	Name        *string                 `json:"name" validate:"omitnil,min=1,max=255"`
+	Slug        *string                 `json:"slug" validate:"omitnil,min=1,max=255"`
+	Description *string                 `json:"description"`
+	Match       *string                 `json:"match" validate:"omitnil,oneof=all any"`
+	Rules       []CollectionRuleRequest `json:"rules" validate:"max=20,dive"` // Replaces all rules when present
+}
+
+type SetCollectionProductsRequest struct {
+	ProductIDs []uuid.UUID `json:"product_ids" validate:"max=500"`
+}
+
+// GetAll handles GET /api/v1/admin/collections
+func (h *CollectionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	collections, err := h.collectionService.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get collections", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve collections")
+		return
+	}
+
+	response.Success(w, collections, "Collections retrieved successfully")
+}
+
+// GetByID handles GET /api/v1/admin/collections/{id}
+func (h *CollectionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Collection not found")
+		return
+	}
+
+	collection, err := h.collectionService.GetByID(r.Context(), id)
+	if err != nil {
+		h.respondError(w, "Failed to get collection", id.String(), err)
+		return
+	}
+
+	response.Success(w, collection, "Collection retrieved successfully")
+}
+
+// Create handles POST /api/v1/admin/collections
+func (h *CollectionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	input := catalog.CollectionInput{
+		Name:        &req.Name,
+		Slug:        &req.Slug,
+		Description: &req.Description,
+		Type:        catalogDomain.CollectionType(req.Type),
+		Rules:       collectionRules(req.Rules),
+	}
+	if req.Match != "" {
+		match := catalogDomain.CollectionMatch(req.Match)
+		input.Match = &match
+	}
+
+	collection, err := h.collectionService.Create(r.Context(), actor, input)
+	if err != nil {
+		h.respondError(w, "Failed to create collection", "", err)
+		return
+	}
+
+	h.logger.Info("Collection created", "collection_id", collection.ID, "slug", collection.Slug)
+	response.Created(w, collection, "Collection created successfully")
+}
+
+// Update handles PATCH /api/v1/admin/collections/{id}
+func (h *CollectionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Collection not found")
+		return
+	}
+
+	var req UpdateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	input := catalog.CollectionInput{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		Rules:       collectionRules(req.Rules),
+	}
+	if req.Match != nil {
+		match := catalogDomain.CollectionMatch(*req.Match)
+		input.Match = &match
+	}
+
+	collection, err := h.collectionService.Update(r.Context(), actor, id, input)
+	if err != nil {
+		h.respondError(w, "Failed to update collection", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Collection updated", "collection_id", id)
+	response.Success(w, collection, "Collection updated successfully")
+}
+
+// SetProducts handles PUT /api/v1/admin/collections/{id}/products
+func (h *CollectionHandler) SetProducts(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Collection not found")
+		return
+	}
+
+	var req SetCollectionProductsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	collection, err := h.collectionService.SetProducts(r.Context(), actor, id, req.ProductIDs)
+	if err != nil {
+		h.respondError(w, "Failed to update collection products", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Collection products updated", "collection_id", id, "products", len(collection.ProductIDs))
+	response.Success(w, collection, "Collection products updated successfully")
+}
+
+// ListProducts handles GET /api/v1/admin/collections/{id}/products
+func (h *CollectionHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Collection not found")
+		return
+	}
+
+	// Parse pagination
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	products, total, err := h.collectionService.ListProducts(r.Context(), id, page, limit)
+	if err != nil {
+		h.respondError(w, "Failed to get collection products", id.String(), err)
+		return
+	}
+
+	response.SuccessWithMeta(w, products, "Collection products retrieved successfully", map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// Delete handles DELETE /api/v1/admin/collections/{id}
+func (h *CollectionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Collection not found")
+		return
+	}
+
+	if err := h.collectionService.Delete(r.Context(), actor, id); err != nil {
+		h.respondError(w, "Failed to delete collection", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Collection deleted", "collection_id", id)
+	response.Success(w, nil, "Collection deleted successfully")
+}
+
+// respondError maps collection service errors to HTTP responses
+func (h *CollectionHandler) respondError(w http.ResponseWriter, message, collectionID string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCollectionNotFound):
+		response.Error(w, http.StatusNotFound, "Collection not found")
+	case errors.Is(err, domain.ErrCollectionSlugTaken):
+		response.ValidationError(w, map[string]string{"slug": "slug is already taken"})
+	case errors.Is(err, domain.ErrInvalidSlug):
+		response.ValidationError(w, map[string]string{"slug": "slug must be lowercase letters and digits separated by hyphens"})
+	case errors.Is(err, domain.ErrInvalidCollectionRule):
+		response.ValidationError(w, map[string]string{"rules": "rules are only allowed on automated collections and must compare price (a whole amount), tag or name with a supported operator"})
+	case errors.Is(err, domain.ErrCollectionNotManual):
+		response.Error(w, http.StatusConflict, "Products of an automated collection are matched by its rules")
+	case errors.Is(err, domain.ErrProductNotFound):
+		response.ValidationError(w, map[string]string{"product_ids": "product_ids must be existing products"})
+	default:
+		h.logger.Error(message, "collection_id", collectionID, "error", err)
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
+
+// collectionRules converts requested rules to collection rules; nil stays nil
+func collectionRules(rules []CollectionRuleRequest) []catalogDomain.CollectionRule {
+	if rules == nil {
+		return nil
+	}
+
+	result := make([]catalogDomain.CollectionRule, len(rules))
+	for i, rule := range rules {
+		result[i] = catalogDomain.CollectionRule{
+			Field:    catalogDomain.RuleField(rule.Field),
+			Operator: catalogDomain.RuleOperator(rule.Operator),
+			Value:    rule.Value,
+		}
+	}
+	return result
+}
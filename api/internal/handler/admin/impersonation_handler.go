@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/admin"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type ImpersonationHandler struct {
+	impersonationService *admin.ImpersonationService
+	logger               *logger.Logger
+}
+
+func NewImpersonationHandler(impersonationService *admin.ImpersonationService, logger *logger.Logger) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+		logger:               logger,
+	}
+}
+
+// Start handles POST /api/v1/admin/customers/{id}/impersonate
+func (h *ImpersonationHandler) Start(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	impersonation, err := h.impersonationService.Start(r.Context(), actor, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFound):
+			response.Error(w, http.StatusNotFound, "Customer not found")
+		case errors.Is(err, domain.ErrUserInactive):
+			response.Error(w, http.StatusForbidden, "Customer account is inactive or deleted")
+		default:
+			h.logger.Error("Failed to start impersonation", "customer_id", id, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to impersonate customer")
+		}
+		return
+	}
+
+	h.logger.Info("Customer impersonation started", "admin_id", actor.Admin.ID, "customer_id", id, "session_id", impersonation.Session.ID)
+	response.Created(w, impersonation, "Impersonation started, use the token as the customer session on the storefront")
+}
+
+// End handles DELETE /api/v1/admin/impersonations/{id}
+func (h *ImpersonationHandler) End(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	sessionID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Impersonation not found")
+		return
+	}
+
+	if err := h.impersonationService.End(r.Context(), actor, sessionID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			response.Error(w, http.StatusNotFound, "Impersonation not found")
+			return
+		}
+		h.logger.Error("Failed to end impersonation", "session_id", sessionID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to end impersonation")
+		return
+	}
+
+	h.logger.Info("Customer impersonation ended", "admin_id", actor.Admin.ID, "session_id", sessionID)
+	response.Success(w, nil, "Impersonation ended successfully")
+}
@@ -0,0 +1,212 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	catalogDomain "github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type InventoryHandler struct {
+	inventoryService *catalog.InventoryService
+	logger           *logger.Logger
+}
+
+func NewInventoryHandler(inventoryService *catalog.InventoryService, logger *logger.Logger) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+		logger:           logger,
+	}
+}
+
+type AdjustStockRequest struct {
+	LocationID uuid.UUID `json:"location_id" validate:"required"`
+	Type       string    `json:"type" validate:"required,oneof=receipt return adjustment"`
+	Quantity   int       `json:"quantity" validate:"required"` // Positive adds stock, negative takes it away; receipts and returns must be positive
+	Reason     string    `json:"reason" validate:"required,max=500"`
+	Reference  *string   `json:"reference" validate:"omitnil,max=255"`
+}
+
+type SellStockRequest struct {
+	LocationID *uuid.UUID `json:"location_id"` // Defaults to the home location of the admin; cashiers cannot pick another
+	Quantity   int        `json:"quantity" validate:"required,min=1"`
+	Reference  *string    `json:"reference" validate:"omitnil,max=255"` // e.g. the receipt number of the till
+}
+
+type StockMovementResponse struct {
+	Movement *catalogDomain.StockMovement `json:"movement"`
+	Level    *catalogDomain.StockLevel    `json:"level"`
+}
+
+// Levels handles GET /api/v1/admin/inventory/variants/{variantId}
+func (h *InventoryHandler) Levels(w http.ResponseWriter, r *http.Request) {
+	variantID, err := uuid.Parse(mux.Vars(r)["variantId"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Variant not found")
+		return
+	}
+
+	levels, err := h.inventoryService.Levels(r.Context(), variantID)
+	if err != nil {
+		h.respondError(w, "Failed to get stock levels", variantID.String(), err)
+		return
+	}
+
+	response.Success(w, levels, "Stock levels retrieved successfully")
+}
+
+// Movements handles GET /api/v1/admin/inventory/variants/{variantId}/movements
+func (h *InventoryHandler) Movements(w http.ResponseWriter, r *http.Request) {
+	variantID, err := uuid.Parse(mux.Vars(r)["variantId"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Variant not found")
+		return
+	}
+
+	// Parse pagination
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var locationID *uuid.UUID
+	if raw := r.URL.Query().Get("location_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response.ValidationError(w, map[string]string{"location_id": "location_id must be a UUID"})
+			return
+		}
+		locationID = &id
+	}
+
+	movements, total, err := h.inventoryService.Movements(r.Context(), variantID, locationID, page, limit)
+	if err != nil {
+		h.respondError(w, "Failed to get stock movements", variantID.String(), err)
+		return
+	}
+
+	response.SuccessWithMeta(w, movements, "Stock movements retrieved successfully", map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// Adjust handles POST /api/v1/admin/inventory/variants/{variantId}/adjustments
+func (h *InventoryHandler) Adjust(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	variantID, err := uuid.Parse(mux.Vars(r)["variantId"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Variant not found")
+		return
+	}
+
+	var req AdjustStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	movement, level, err := h.inventoryService.Adjust(r.Context(), actor, variantID, catalog.AdjustmentInput{
+		LocationID: req.LocationID,
+		Type:       catalogDomain.MovementType(req.Type),
+		Quantity:   req.Quantity,
+		Reason:     req.Reason,
+		Reference:  req.Reference,
+	})
+	if err != nil {
+		h.respondError(w, "Failed to adjust stock", variantID.String(), err)
+		return
+	}
+
+	h.logger.Info("Stock adjusted", "variant_id", variantID, "location_id", movement.LocationID, "type", movement.Type, "quantity", movement.Quantity, "admin_id", actor.Admin.ID)
+	response.Created(w, StockMovementResponse{Movement: movement, Level: level}, "Stock adjusted successfully")
+}
+
+// Sell handles POST /api/v1/admin/inventory/variants/{variantId}/sales
+func (h *InventoryHandler) Sell(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	variantID, err := uuid.Parse(mux.Vars(r)["variantId"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Variant not found")
+		return
+	}
+
+	var req SellStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	movement, level, err := h.inventoryService.Sell(r.Context(), actor, variantID, req.Quantity, req.LocationID, req.Reference)
+	if err != nil {
+		h.respondError(w, "Failed to record sale", variantID.String(), err)
+		return
+	}
+
+	h.logger.Info("Sale recorded", "variant_id", variantID, "location_id", movement.LocationID, "quantity", req.Quantity, "admin_id", actor.Admin.ID)
+	response.Created(w, StockMovementResponse{Movement: movement, Level: level}, "Sale recorded successfully")
+}
+
+// respondError maps inventory service errors to HTTP responses
+func (h *InventoryHandler) respondError(w http.ResponseWriter, message, variantID string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrVariantNotFound):
+		response.Error(w, http.StatusNotFound, "Variant not found")
+	case errors.Is(err, domain.ErrInsufficientStock):
+		response.Error(w, http.StatusConflict, "Not enough stock available")
+	case errors.Is(err, domain.ErrInvalidMovement):
+		response.ValidationError(w, map[string]string{"quantity": "quantity must be positive for receipts and returns and cannot be zero"})
+	case errors.Is(err, domain.ErrRequiredField):
+		response.ValidationError(w, map[string]string{"reason": "reason is required"})
+	case errors.Is(err, domain.ErrLocationNotFound):
+		response.Error(w, http.StatusNotFound, "Location not found")
+	case errors.Is(err, domain.ErrLocationInactive):
+		response.ValidationError(w, map[string]string{"location_id": "location is inactive"})
+	case errors.Is(err, domain.ErrNoHomeLocation):
+		response.ValidationError(w, map[string]string{"location_id": "location_id is required without a home location"})
+	case errors.Is(err, domain.ErrLocationNotAllowed):
+		response.Error(w, http.StatusForbidden, "Cashiers can only sell from their home location")
+	default:
+		h.logger.Error(message, "variant_id", variantID, "error", err)
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,223 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	catalogDomain "github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type LocationHandler struct {
+	locationService *catalog.LocationService
+	logger          *logger.Logger
+}
+
+func NewLocationHandler(locationService *catalog.LocationService, logger *logger.Logger) *LocationHandler {
+	return &LocationHandler{
+		locationService: locationService,
+		logger:          logger,
+	}
+}
+
+type CreateLocationRequest struct {
+	Name           string `json:"name" validate:"required,max=255"`
+	Code           string `json:"code" validate:"required,max=50"` // Stored uppercase
+	Type           string `json:"type" validate:"required,oneof=warehouse store"`
+	Address        string `json:"address"`
+	FulfillsOnline bool   `json:"fulfills_online"` // Takes over online orders from the location fulfilling them before
+}
+
+type UpdateLocationRequest struct {
+	Name           *string `json:"name" validate:"omitnil,min=1,max=255"`
+	Code           *string `json:"code" validate:"omitnil,min=1,max=50"`
+	Type           *string `json:"type" validate:"omitnil,oneof=warehouse store"`
+	Address        *string `json:"address"`
+	FulfillsOnline *bool   `json:"fulfills_online"`
+	IsActive       *bool   `json:"is_active"`
+}
+
+type AssignLocationRequest struct {
+	LocationID *uuid.UUID `json:"location_id"` // Clears the home location when empty
+}
+
+// GetAll handles GET /api/v1/admin/locations
+func (h *LocationHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	locations, err := h.locationService.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get locations", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve locations")
+		return
+	}
+
+	response.Success(w, locations, "Locations retrieved successfully")
+}
+
+// GetByID handles GET /api/v1/admin/locations/{id}
+func (h *LocationHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Location not found")
+		return
+	}
+
+	location, err := h.locationService.GetByID(r.Context(), id)
+	if err != nil {
+		h.respondError(w, "Failed to get location", id.String(), err)
+		return
+	}
+
+	response.Success(w, location, "Location retrieved successfully")
+}
+
+// Create handles POST /api/v1/admin/locations
+func (h *LocationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	locationType := catalogDomain.LocationType(req.Type)
+	location, err := h.locationService.Create(r.Context(), actor, catalog.LocationInput{
+		Name:           &req.Name,
+		Code:           &req.Code,
+		Type:           &locationType,
+		Address:        &req.Address,
+		FulfillsOnline: &req.FulfillsOnline,
+	})
+	if err != nil {
+		h.respondError(w, "Failed to create location", "", err)
+		return
+	}
+
+	h.logger.Info("Location created", "location_id", location.ID, "code", location.Code)
+	response.Created(w, location, "Location created successfully")
+}
+
+// Update handles PATCH /api/v1/admin/locations/{id}
+func (h *LocationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Location not found")
+		return
+	}
+
+	var req UpdateLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	input := catalog.LocationInput{
+		Name:           req.Name,
+		Code:           req.Code,
+		Address:        req.Address,
+		FulfillsOnline: req.FulfillsOnline,
+		IsActive:       req.IsActive,
+	}
+	if req.Type != nil {
+		locationType := catalogDomain.LocationType(*req.Type)
+		input.Type = &locationType
+	}
+
+	location, err := h.locationService.Update(r.Context(), actor, id, input)
+	if err != nil {
+		h.respondError(w, "Failed to update location", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Location updated", "location_id", id)
+	response.Success(w, location, "Location updated successfully")
+}
+
+// AssignAdmin handles PUT /api/v1/admin/admins/{id}/location
+func (h *LocationHandler) AssignAdmin(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	adminID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Admin not found")
+		return
+	}
+
+	var req AssignLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	admin, err := h.locationService.AssignAdmin(r.Context(), actor, adminID, req.LocationID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFound):
+			response.Error(w, http.StatusNotFound, "Admin not found")
+		case errors.Is(err, domain.ErrRoleNotGrantable):
+			response.Error(w, http.StatusForbidden, "Cannot manage an admin above your own role")
+		default:
+			h.respondError(w, "Failed to assign location", "", err)
+		}
+		return
+	}
+
+	h.logger.Info("Admin location assigned", "admin_id", adminID, "location_id", admin.LocationID)
+	response.Success(w, admin, "Location assigned successfully")
+}
+
+// respondError maps location service errors to HTTP responses
+func (h *LocationHandler) respondError(w http.ResponseWriter, message, locationID string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrLocationNotFound):
+		response.Error(w, http.StatusNotFound, "Location not found")
+	case errors.Is(err, domain.ErrLocationCodeTaken):
+		response.ValidationError(w, map[string]string{"code": "code is already taken"})
+	case errors.Is(err, domain.ErrInvalidLocationCode):
+		response.ValidationError(w, map[string]string{"code": "code must be uppercase letters and digits separated by hyphens"})
+	case errors.Is(err, domain.ErrInvalidLocationType):
+		response.ValidationError(w, map[string]string{"type": "type must be one of: warehouse store"})
+	case errors.Is(err, domain.ErrLocationInactive):
+		response.ValidationError(w, map[string]string{"location": "location is inactive and cannot fulfil online orders or take admins"})
+	case errors.Is(err, domain.ErrRequiredField):
+		response.ValidationError(w, map[string]string{"name": "name is required"})
+	default:
+		h.logger.Error(message, "location_id", locationID, "error", err)
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/admin"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type LockoutHandler struct {
+	lockoutService *admin.LockoutService
+	logger         *logger.Logger
+}
+
+func NewLockoutHandler(lockoutService *admin.LockoutService, logger *logger.Logger) *LockoutHandler {
+	return &LockoutHandler{
+		lockoutService: lockoutService,
+		logger:         logger,
+	}
+}
+
+// UnlockAdmin handles POST /api/v1/admin/admins/{id}/unlock
+func (h *LockoutHandler) UnlockAdmin(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	if err := h.lockoutService.UnlockAdmin(r.Context(), actor, id); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.Error(w, http.StatusNotFound, "Admin not found")
+			return
+		}
+		h.logger.Error("Failed to unlock admin", "id", id, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to unlock admin")
+		return
+	}
+
+	h.logger.Info("Admin account unlocked", "admin_id", id)
+	response.Success(w, nil, "Admin account unlocked successfully")
+}
+
+// UnlockCustomer handles POST /api/v1/admin/customers/{id}/unlock
+func (h *LockoutHandler) UnlockCustomer(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	if err := h.lockoutService.UnlockCustomer(r.Context(), actor, id); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.Error(w, http.StatusNotFound, "Customer not found")
+			return
+		}
+		h.logger.Error("Failed to unlock customer", "id", id, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to unlock customer")
+		return
+	}
+
+	h.logger.Info("Customer account unlocked", "customer_id", id)
+	response.Success(w, nil, "Customer account unlocked successfully")
+}
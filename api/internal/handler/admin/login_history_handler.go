@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type LoginHistoryHandler struct {
+	historyService *sharedService.LoginHistoryService
+	logger         *logger.Logger
+}
+
+func NewLoginHistoryHandler(historyService *sharedService.LoginHistoryService, logger *logger.Logger) *LoginHistoryHandler {
+	return &LoginHistoryHandler{
+		historyService: historyService,
+		logger:         logger,
+	}
+}
+
+// List handles GET /api/v1/admin/auth/login-history
+func (h *LoginHistoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	adminUser, ok := middleware.AdminFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	h.respond(w, r, adminUser.ID)
+}
+
+// ListForAdmin handles GET /api/v1/admin/admins/{id}/login-history
+func (h *LoginHistoryHandler) ListForAdmin(w http.ResponseWriter, r *http.Request) {
+	adminID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Admin not found")
+		return
+	}
+
+	h.respond(w, r, adminID)
+}
+
+// respond writes a page of an admin's login history
+func (h *LoginHistoryHandler) respond(w http.ResponseWriter, r *http.Request, adminID uuid.UUID) {
+	// Parse pagination
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	history, total, err := h.historyService.List(r.Context(), shared.UserTypeAdmin, adminID, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get login history", "admin_id", adminID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve login history")
+		return
+	}
+
+	response.SuccessWithMeta(w, history, "Login history retrieved successfully", map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
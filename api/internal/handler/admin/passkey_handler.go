@@ -0,0 +1,169 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/admin"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type PasskeyHandler struct {
+	passkeyService *admin.PasskeyService
+	logger         *logger.Logger
+}
+
+func NewPasskeyHandler(passkeyService *admin.PasskeyService, logger *logger.Logger) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyService: passkeyService,
+		logger:         logger,
+	}
+}
+
+type PasskeyRegistrationOptionsRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type RegisterPasskeyRequest struct {
+	ChallengeToken string          `json:"challenge_token" validate:"required"`
+	Name           string          `json:"name" validate:"omitempty,max=100"`
+	Credential     json.RawMessage `json:"credential" validate:"required"` // PublicKeyCredential from the browser, as JSON
+}
+
+// List handles GET /api/v1/admin/auth/passkeys
+func (h *PasskeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	currentAdmin, ok := middleware.AdminFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	passkeys, err := h.passkeyService.List(r.Context(), currentAdmin.ID)
+	if err != nil {
+		h.logger.Error("Failed to list passkeys", "admin_id", currentAdmin.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve passkeys")
+		return
+	}
+
+	response.Success(w, passkeys, "Passkeys retrieved successfully")
+}
+
+// RegistrationOptions handles POST /api/v1/admin/auth/passkeys/options
+func (h *PasskeyHandler) RegistrationOptions(w http.ResponseWriter, r *http.Request) {
+	currentAdmin, ok := middleware.AdminFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req PasskeyRegistrationOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	registration, err := h.passkeyService.BeginRegistration(r.Context(), currentAdmin.ID.String(), req.Password, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		h.respondError(w, "Passkey registration failed", currentAdmin.ID.String(), err)
+		return
+	}
+
+	response.Success(w, registration, "Create the passkey in the browser and send it back to finish the registration")
+}
+
+// Register handles POST /api/v1/admin/auth/passkeys
+func (h *PasskeyHandler) Register(w http.ResponseWriter, r *http.Request) {
+	currentAdmin, ok := middleware.AdminFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req RegisterPasskeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(r.Context(), currentAdmin.ID.String(), req.ChallengeToken, req.Name, req.Credential)
+	if err != nil {
+		h.respondError(w, "Passkey registration failed", currentAdmin.ID.String(), err)
+		return
+	}
+
+	h.logger.Info("Admin passkey registered", "admin_id", currentAdmin.ID, "passkey_id", passkey.ID)
+	response.Created(w, passkey, "Passkey registered successfully")
+}
+
+// Delete handles DELETE /api/v1/admin/auth/passkeys/{id}
+func (h *PasskeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	currentAdmin, ok := middleware.AdminFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Passkey not found")
+		return
+	}
+
+	if err := h.passkeyService.Delete(r.Context(), currentAdmin.ID, id); err != nil {
+		h.respondError(w, "Passkey deletion failed", currentAdmin.ID.String(), err)
+		return
+	}
+
+	h.logger.Info("Admin passkey deleted", "admin_id", currentAdmin.ID, "passkey_id", id)
+	response.Success(w, nil, "Passkey deleted successfully")
+}
+
+// LoginOptions handles POST /api/v1/admin/auth/passkey/options
+func (h *PasskeyHandler) LoginOptions(w http.ResponseWriter, r *http.Request) {
+	login, err := h.passkeyService.BeginPasswordless(r.Context(), middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		h.logger.Error("Failed to start passkey login", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to start passkey login")
+		return
+	}
+
+	response.Success(w, login, "Sign in with a passkey in the browser and send it back to finish the login")
+}
+
+// respondError maps passkey service errors to HTTP responses
+func (h *PasskeyHandler) respondError(w http.ResponseWriter, message, adminID string, err error) {
+	h.logger.Error(message, "admin_id", adminID, "error", err)
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		response.Error(w, http.StatusUnprocessableEntity, "Invalid password")
+	case errors.Is(err, domain.ErrInvalidPasskey):
+		response.Error(w, http.StatusUnprocessableEntity, "Passkey could not be verified")
+	case errors.Is(err, domain.ErrPasskeyChallengeNotFound):
+		response.Error(w, http.StatusUnprocessableEntity, "Passkey registration is invalid or has expired, please start again")
+	case errors.Is(err, domain.ErrPasskeyNotFound):
+		response.Error(w, http.StatusNotFound, "Passkey not found")
+	default:
+		response.Error(w, http.StatusInternalServerError, "Failed to update passkeys")
+	}
+}
@@ -0,0 +1,423 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	catalogDomain "github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type ProductHandler struct {
+	productService *catalog.ProductService
+	logger         *logger.Logger
+}
+
+func NewProductHandler(productService *catalog.ProductService, logger *logger.Logger) *ProductHandler {
+	return &ProductHandler{
+		productService: productService,
+		logger:         logger,
+	}
+}
+
+type ProductImageRequest struct {
+	URL     string `json:"url" validate:"required,url,max=500"`
+	AltText string `json:"alt_text" validate:"max=255"`
+}
+
+type ProductOptionRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Values []string `json:"values" validate:"required,min=1,max=50,unique,dive,required,max=100"`
+}
+
+type CreateProductRequest struct {
+	Name        string                 `json:"name" validate:"required,max=255"`
+	Slug        string                 `json:"slug" validate:"omitempty,max=255"` // Derived from the name when empty
+	Description string                 `json:"description"`
+	Status      string                 `json:"status" validate:"omitempty,oneof=draft active archived"`
+	Tags        []string               `json:"tags" validate:"max=20,dive,required,max=50"`
+	Images      []ProductImageRequest  `json:"images" validate:"max=20,dive"`             // Shown in the given order
+	Options     []ProductOptionRequest `json:"options" validate:"max=3,unique=Name,dive"` // None makes a single variant
+	Price       int64                  `json:"price" validate:"min=0"`                    // Price of each generated variant
+}
+
+type UpdateProductRequest struct {
+	Name        *string               `json:"name" validate:"omitnil,min=1,max=255"`
+	Slug        *string               `json:"slug" validate:"omitnil,min=1,max=255"`
+	Description *string               `json:"description"`
+	Status      *string               `json:"status" validate:"omitnil,oneof=draft active archived"`
+	Tags        []string              `json:"tags" validate:"max=20,dive,required,max=50"` // Replaces all tags when present
+	Images      []ProductImageRequest `json:"images" validate:"max=20,dive"`               // Replaces all images when present
+}
+
+type SetProductOptionsRequest struct {
+	Options []ProductOptionRequest `json:"options" validate:"max=3,unique=Name,dive"`
+	Price   int64                  `json:"price" validate:"min=0"` // Price of variants of new combinations
+}
+
+type SetProductCategoriesRequest struct {
+	CategoryIDs []uuid.UUID `json:"category_ids" validate:"max=50"`
+}
+
+type UpdateVariantRequest struct {
+	SKU       *string `json:"sku" validate:"omitnil,min=1,max=100"`
+	Price     *int64  `json:"price" validate:"omitnil,min=0"`
+	Barcode   *string `json:"barcode" validate:"omitnil,max=100"` // Empty clears the barcode
+	Weight    *int    `json:"weight" validate:"omitnil,min=0"`
+	IsEnabled *bool   `json:"is_enabled"`
+}
+
+// GetAll handles GET /api/v1/admin/products
+func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	filter := catalogDomain.ProductFilter{
+		Search: r.URL.Query().Get("search"),
+		Status: catalogDomain.ProductStatus(r.URL.Query().Get("status")),
+		Tag:    r.URL.Query().Get("tag"),
+	}
+
+	products, total, err := h.productService.GetAll(r.Context(), page, limit, filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidProductStatus) {
+			response.ValidationError(w, map[string]string{"status": "status must be one of: draft active archived"})
+			return
+		}
+		h.logger.Error("Failed to get products", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve products")
+		return
+	}
+
+	response.SuccessWithMeta(w, products, "Products retrieved successfully", map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetByID handles GET /api/v1/admin/products/{id}
+func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Product not found")
+		return
+	}
+
+	product, err := h.productService.GetByID(r.Context(), id)
+	if err != nil {
+		h.respondError(w, "Failed to get product", id.String(), err)
+		return
+	}
+
+	response.Success(w, product, "Product retrieved successfully")
+}
+
+// Create handles POST /api/v1/admin/products
+func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	input := catalog.ProductInput{
+		Name:        &req.Name,
+		Slug:        &req.Slug,
+		Description: &req.Description,
+		Tags:        req.Tags,
+		Images:      productImages(req.Images),
+		Options:     productOptions(req.Options),
+		Price:       req.Price,
+	}
+	if req.Status != "" {
+		status := catalogDomain.ProductStatus(req.Status)
+		input.Status = &status
+	}
+
+	product, err := h.productService.Create(r.Context(), actor, input)
+	if err != nil {
+		h.respondError(w, "Failed to create product", "", err)
+		return
+	}
+
+	h.logger.Info("Product created", "product_id", product.ID, "slug", product.Slug)
+	response.Created(w, product, "Product created successfully")
+}
+
+// Update handles PATCH /api/v1/admin/products/{id}
+func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Product not found")
+		return
+	}
+
+	var req UpdateProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	input := catalog.ProductInput{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		Tags:        req.Tags,
+		Images:      productImages(req.Images),
+	}
+	if req.Status != nil {
+		status := catalogDomain.ProductStatus(*req.Status)
+		input.Status = &status
+	}
+
+	product, err := h.productService.Update(r.Context(), actor, id, input)
+	if err != nil {
+		h.respondError(w, "Failed to update product", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Product updated", "product_id", id)
+	response.Success(w, product, "Product updated successfully")
+}
+
+// SetOptions handles PUT /api/v1/admin/products/{id}/options
+func (h *ProductHandler) SetOptions(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Product not found")
+		return
+	}
+
+	var req SetProductOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	product, err := h.productService.SetOptions(r.Context(), actor, id, productOptions(req.Options), req.Price)
+	if err != nil {
+		h.respondError(w, "Failed to update product options", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Product options updated", "product_id", id, "variants", len(product.Variants))
+	response.Success(w, product, "Product options updated successfully")
+}
+
+// UpdateVariant handles PATCH /api/v1/admin/products/{id}/variants/{variantId}
+func (h *ProductHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Product not found")
+		return
+	}
+	variantID, err := uuid.Parse(vars["variantId"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Variant not found")
+		return
+	}
+
+	var req UpdateVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	variant, err := h.productService.UpdateVariant(r.Context(), actor, id, variantID, catalog.VariantInput{
+		SKU:       req.SKU,
+		Price:     req.Price,
+		Barcode:   req.Barcode,
+		Weight:    req.Weight,
+		IsEnabled: req.IsEnabled,
+	})
+	if err != nil {
+		h.respondError(w, "Failed to update variant", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Product variant updated", "product_id", id, "variant_id", variantID)
+	response.Success(w, variant, "Variant updated successfully")
+}
+
+// SetCategories handles PUT /api/v1/admin/products/{id}/categories
+func (h *ProductHandler) SetCategories(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Product not found")
+		return
+	}
+
+	var req SetProductCategoriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	product, err := h.productService.SetCategories(r.Context(), actor, id, req.CategoryIDs)
+	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			response.ValidationError(w, map[string]string{"category_ids": "category_ids must be existing categories"})
+			return
+		}
+		h.respondError(w, "Failed to update product categories", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Product categories updated", "product_id", id, "categories", len(product.CategoryIDs))
+	response.Success(w, product, "Product categories updated successfully")
+}
+
+// Delete handles DELETE /api/v1/admin/products/{id}
+func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Product not found")
+		return
+	}
+
+	// Soft delete product
+	if err := h.productService.Delete(r.Context(), actor, id); err != nil {
+		h.respondError(w, "Failed to delete product", id.String(), err)
+		return
+	}
+
+	h.logger.Info("Product deleted", "product_id", id)
+	response.Success(w, nil, "Product deleted successfully")
+}
+
+// respondError maps product service errors to HTTP responses
+func (h *ProductHandler) respondError(w http.ResponseWriter, message, productID string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound):
+		response.Error(w, http.StatusNotFound, "Product not found")
+	case errors.Is(err, domain.ErrProductSlugTaken):
+		response.ValidationError(w, map[string]string{"slug": "slug is already taken"})
+	case errors.Is(err, domain.ErrInvalidSlug):
+		response.ValidationError(w, map[string]string{"slug": "slug must be lowercase letters and digits separated by hyphens"})
+	case errors.Is(err, domain.ErrInvalidProductStatus):
+		response.ValidationError(w, map[string]string{"status": "status must be one of: draft active archived"})
+	case errors.Is(err, domain.ErrVariantNotFound):
+		response.Error(w, http.StatusNotFound, "Variant not found")
+	case errors.Is(err, domain.ErrVariantSKUTaken):
+		response.ValidationError(w, map[string]string{"sku": "sku is already taken"})
+	case errors.Is(err, domain.ErrVariantHasStock):
+		response.Error(w, http.StatusConflict, "Variants to remove still have stock on hand, reserved or in transit, adjust their stock first")
+	case errors.Is(err, domain.ErrTooManyVariants):
+		response.ValidationError(w, map[string]string{"options": "options must make at most " + strconv.Itoa(catalogDomain.MaxVariants) + " variants"})
+	default:
+		h.logger.Error(message, "product_id", productID, "error", err)
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
+
+// productImages converts requested images to product images, keeping their order; nil stays nil
+func productImages(images []ProductImageRequest) []catalogDomain.ProductImage {
+	if images == nil {
+		return nil
+	}
+
+	result := make([]catalogDomain.ProductImage, len(images))
+	for i, img := range images {
+		result[i] = catalogDomain.ProductImage{URL: img.URL, AltText: img.AltText, Position: i}
+	}
+	return result
+}
+
+// productOptions converts requested options to product options, keeping their order; nil stays nil
+func productOptions(options []ProductOptionRequest) []catalogDomain.ProductOption {
+	if options == nil {
+		return nil
+	}
+
+	result := make([]catalogDomain.ProductOption, len(options))
+	for i, option := range options {
+		result[i] = catalogDomain.ProductOption{Name: option.Name, Values: option.Values, Position: i}
+	}
+	return result
+}
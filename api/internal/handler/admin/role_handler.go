@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/admin"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type RoleHandler struct {
+	permissionService *admin.PermissionService
+	logger            *logger.Logger
+}
+
+func NewRoleHandler(permissionService *admin.PermissionService, logger *logger.Logger) *RoleHandler {
+	return &RoleHandler{
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+type UpdateRolePermissionsRequest struct {
+	Permissions []adminDomain.Permission `json:"permissions" validate:"required"`
+}
+
+// ListPermissions handles GET /api/v1/admin/permissions
+func (h *RoleHandler) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, h.permissionService.Registry(), "Permissions retrieved successfully")
+}
+
+// ListRoles handles GET /api/v1/admin/roles
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.permissionService.ListRoles(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get roles", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve roles")
+		return
+	}
+
+	response.Success(w, roles, "Roles retrieved successfully")
+}
+
+// UpdatePermissions handles PUT /api/v1/admin/roles/{role}/permissions
+func (h *RoleHandler) UpdatePermissions(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	role := adminDomain.AdminRole(mux.Vars(r)["role"])
+
+	var req UpdateRolePermissionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	updated, err := h.permissionService.UpdateRole(r.Context(), actor, role, req.Permissions)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPermissionDenied):
+			response.Error(w, http.StatusForbidden, "Cannot grant permissions you do not hold")
+		case errors.Is(err, domain.ErrRoleNotGrantable):
+			response.Error(w, http.StatusForbidden, "Cannot change the permissions of a role above your own")
+		case errors.Is(err, domain.ErrInvalidRole):
+			response.Error(w, http.StatusNotFound, "Role not found")
+		case errors.Is(err, domain.ErrRoleNotEditable):
+			response.Error(w, http.StatusForbidden, "Super admin permissions cannot be changed")
+		case errors.Is(err, domain.ErrInvalidPermission):
+			response.Error(w, http.StatusUnprocessableEntity, "Unknown permission")
+		default:
+			h.logger.Error("Failed to update role permissions", "role", role, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to update role permissions")
+		}
+		return
+	}
+
+	h.logger.Info("Role permissions updated", "role", role, "admin_id", actor.Admin.ID)
+	response.Success(w, updated, "Role permissions updated successfully")
+}
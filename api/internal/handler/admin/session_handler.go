@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/admin"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type SessionHandler struct {
+	sessionService *admin.SessionService
+	logger         *logger.Logger
+}
+
+func NewSessionHandler(sessionService *admin.SessionService, logger *logger.Logger) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+		logger:         logger,
+	}
+}
+
+type SessionResponse struct {
+	*adminDomain.Session
+	Current bool `json:"current"`
+}
+
+type RevokedSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// List handles GET /api/v1/admin/auth/sessions
+func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	adminUser, ok := middleware.AdminFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	current, _ := middleware.AdminSessionFromContext(r.Context())
+
+	sessions, err := h.sessionService.List(r.Context(), adminUser)
+	if err != nil {
+		h.logger.Error("Failed to list sessions", "admin_id", adminUser.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve sessions")
+		return
+	}
+
+	// Mark the session used for this request
+	data := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, SessionResponse{
+			Session: session,
+			Current: current != nil && session.ID == current.ID,
+		})
+	}
+
+	response.Success(w, data, "Sessions retrieved successfully")
+}
+
+// Revoke handles DELETE /api/v1/admin/auth/sessions/{id}
+func (h *SessionHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	adminUser, ok := middleware.AdminFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	sessionID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	if err := h.sessionService.Revoke(r.Context(), adminUser.ID, sessionID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			response.Error(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		h.logger.Error("Failed to revoke session", "admin_id", adminUser.ID, "session_id", sessionID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+
+	h.logger.Info("Admin session revoked", "admin_id", adminUser.ID, "session_id", sessionID)
+	response.Success(w, nil, "Session revoked successfully")
+}
+
+// RevokeOthers handles DELETE /api/v1/admin/auth/sessions
+func (h *SessionHandler) RevokeOthers(w http.ResponseWriter, r *http.Request) {
+	adminUser, ok := middleware.AdminFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	current, ok := middleware.AdminSessionFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeOthers(r.Context(), adminUser.ID, current.ID)
+	if err != nil {
+		h.logger.Error("Failed to revoke other sessions", "admin_id", adminUser.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	h.logger.Info("Admin other sessions revoked", "admin_id", adminUser.ID, "revoked", revoked)
+	response.Success(w, RevokedSessionsResponse{Revoked: revoked}, "Logged out of all other sessions")
+}
+
+// RevokeAll handles DELETE /api/v1/admin/admins/{id}/sessions
+func (h *SessionHandler) RevokeAll(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	if err := h.sessionService.RevokeAll(r.Context(), actor, id); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.Error(w, http.StatusNotFound, "Admin not found")
+			return
+		}
+		if errors.Is(err, domain.ErrRoleNotGrantable) {
+			response.Error(w, http.StatusForbidden, "Cannot manage an admin above your own role")
+			return
+		}
+		h.logger.Error("Failed to revoke admin sessions", "id", id, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	h.logger.Info("All admin sessions revoked", "admin_id", id)
+	response.Success(w, nil, "All sessions of the admin have been revoked")
+}
@@ -0,0 +1,208 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
+	catalogDomain "github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type TransferHandler struct {
+	transferService *catalog.TransferService
+	logger          *logger.Logger
+}
+
+func NewTransferHandler(transferService *catalog.TransferService, logger *logger.Logger) *TransferHandler {
+	return &TransferHandler{
+		transferService: transferService,
+		logger:          logger,
+	}
+}
+
+type TransferItemRequest struct {
+	VariantID uuid.UUID `json:"variant_id" validate:"required"`
+	Quantity  int       `json:"quantity" validate:"required,min=1,max=100000"`
+}
+
+type CreateTransferRequest struct {
+	FromLocationID uuid.UUID             `json:"from_location_id" validate:"required"`
+	ToLocationID   uuid.UUID             `json:"to_location_id" validate:"required"`
+	Note           string                `json:"note" validate:"max=1000"`
+	Items          []TransferItemRequest `json:"items" validate:"required,min=1,max=500,dive"`
+}
+
+// GetAll handles GET /api/v1/admin/transfers
+func (h *TransferHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	// Parse pagination
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := catalogDomain.TransferFilter{
+		Status: catalogDomain.TransferStatus(r.URL.Query().Get("status")),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		response.ValidationError(w, map[string]string{"status": "status must be one of: draft in_transit received cancelled"})
+		return
+	}
+	if raw := r.URL.Query().Get("location_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response.ValidationError(w, map[string]string{"location_id": "location_id must be a UUID"})
+			return
+		}
+		filter.LocationID = &id
+	}
+
+	transfers, total, err := h.transferService.GetAll(r.Context(), page, limit, filter)
+	if err != nil {
+		h.respondError(w, "Failed to retrieve transfers", "", err)
+		return
+	}
+
+	response.SuccessWithMeta(w, transfers, "Transfers retrieved successfully", map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetByID handles GET /api/v1/admin/transfers/{id}
+func (h *TransferHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Transfer not found")
+		return
+	}
+
+	transfer, err := h.transferService.GetByID(r.Context(), id)
+	if err != nil {
+		h.respondError(w, "Failed to get transfer", id.String(), err)
+		return
+	}
+
+	response.Success(w, transfer, "Transfer retrieved successfully")
+}
+
+// Create handles POST /api/v1/admin/transfers
+func (h *TransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	items := make([]catalogDomain.TransferItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = catalogDomain.TransferItem{VariantID: item.VariantID, Quantity: item.Quantity}
+	}
+
+	transfer, err := h.transferService.Create(r.Context(), actor, catalog.TransferInput{
+		FromLocationID: req.FromLocationID,
+		ToLocationID:   req.ToLocationID,
+		Note:           req.Note,
+		Items:          items,
+	})
+	if err != nil {
+		h.respondError(w, "Failed to create transfer", "", err)
+		return
+	}
+
+	h.logger.Info("Transfer created", "transfer_id", transfer.ID, "from_location_id", transfer.FromLocationID, "to_location_id", transfer.ToLocationID)
+	response.Created(w, transfer, "Transfer created successfully")
+}
+
+// Ship handles POST /api/v1/admin/transfers/{id}/ship
+func (h *TransferHandler) Ship(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.transferService.Ship, "ship", "Transfer shipped")
+}
+
+// Receive handles POST /api/v1/admin/transfers/{id}/receive
+func (h *TransferHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.transferService.Receive, "receive", "Transfer received")
+}
+
+// Cancel handles POST /api/v1/admin/transfers/{id}/cancel
+func (h *TransferHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.transferService.Cancel, "cancel", "Transfer cancelled")
+}
+
+// transition moves a transfer on with the given service method
+func (h *TransferHandler) transition(w http.ResponseWriter, r *http.Request, move func(ctx context.Context, actor adminDomain.Actor, id uuid.UUID) (*catalogDomain.TransferOrder, error), verb, done string) {
+	actor, ok := middleware.AdminActor(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Transfer not found")
+		return
+	}
+
+	transfer, err := move(r.Context(), actor, id)
+	if err != nil {
+		h.respondError(w, "Failed to "+verb+" transfer", id.String(), err)
+		return
+	}
+
+	h.logger.Info(done, "transfer_id", id, "admin_id", actor.Admin.ID)
+	response.Success(w, transfer, done+" successfully")
+}
+
+// respondError maps transfer service errors to HTTP responses
+func (h *TransferHandler) respondError(w http.ResponseWriter, message, transferID string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTransferNotFound):
+		response.Error(w, http.StatusNotFound, "Transfer not found")
+	case errors.Is(err, domain.ErrTransferStatus):
+		response.Error(w, http.StatusConflict, "Drafts can be shipped, transfers in transit received, and both cancelled")
+	case errors.Is(err, domain.ErrInsufficientStock):
+		response.Error(w, http.StatusConflict, "Not enough stock available at the source location")
+	case errors.Is(err, domain.ErrInvalidTransfer):
+		response.ValidationError(w, map[string]string{"to_location_id": "to_location_id must differ from from_location_id"})
+	case errors.Is(err, domain.ErrLocationNotFound):
+		response.ValidationError(w, map[string]string{"location_id": "locations must exist"})
+	case errors.Is(err, domain.ErrLocationInactive):
+		response.ValidationError(w, map[string]string{"to_location_id": "destination location is inactive"})
+	case errors.Is(err, domain.ErrVariantNotFound):
+		response.ValidationError(w, map[string]string{"items": "items must be existing variants"})
+	case errors.Is(err, domain.ErrInvalidInput):
+		response.ValidationError(w, map[string]string{"items": "items must have a positive quantity"})
+	default:
+		h.logger.Error(message, "transfer_id", transferID, "error", err)
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,30 @@
+package shared
+
+import (
+	"net/http"
+
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type CSRFHandler struct{}
+
+func NewCSRFHandler() *CSRFHandler {
+	return &CSRFHandler{}
+}
+
+type CSRFTokenResponse struct {
+	Token  string `json:"csrf_token"`
+	Header string `json:"header"`
+}
+
+// Token handles GET /api/v1/admin/auth/csrf and GET /api/v1/store/auth/csrf
+func (h *CSRFHandler) Token(w http.ResponseWriter, r *http.Request) {
+	token, ok := middleware.CSRFTokenFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "No session found")
+		return
+	}
+
+	response.Success(w, CSRFTokenResponse{Token: token, Header: middleware.HeaderCSRFToken}, "CSRF token retrieved successfully")
+}
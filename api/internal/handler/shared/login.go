@@ -0,0 +1,28 @@
+package shared
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+// RespondLoginError maps login errors of admins and customers to HTTP responses
+func RespondLoginError(w http.ResponseWriter, err error) {
+	var locked *domain.AccountLockedError
+	var throttled *domain.ThrottledError
+
+	switch {
+	case errors.As(err, &locked):
+		response.ErrorWithRetryAfter(w, http.StatusLocked, "Account is temporarily locked due to too many failed login attempts", locked.RetryAfter)
+	case errors.As(err, &throttled):
+		response.ErrorWithRetryAfter(w, http.StatusTooManyRequests, "Too many login attempts, please try again later", throttled.RetryAfter)
+	case errors.Is(err, domain.ErrUserInactive):
+		response.Error(w, http.StatusForbidden, "Account is inactive or deleted")
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		response.Error(w, http.StatusUnauthorized, "Invalid email or password")
+	default:
+		response.Error(w, http.StatusInternalServerError, "Failed to login")
+	}
+}
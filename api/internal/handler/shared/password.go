@@ -0,0 +1,24 @@
+package shared
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+// RespondWeakPassword sends the password policy rules a new password breaks as a validation error
+// on the password field, and reports whether err was a weak password error
+func RespondWeakPassword(w http.ResponseWriter, err error) bool {
+	var weak *domain.WeakPasswordError
+	if !errors.As(err, &weak) {
+		return false
+	}
+
+	response.ValidationError(w, map[string]string{
+		"password": "password " + strings.Join(weak.Problems, ", "),
+	})
+	return true
+}
@@ -0,0 +1,225 @@
+package shared
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+// TwoFactorHandler serves the two-factor setup endpoints of either admins or customers
+type TwoFactorHandler struct {
+	twoFactorService *sharedService.TwoFactorService
+	userType         shared.UserType
+	logger           *logger.Logger
+}
+
+func NewTwoFactorHandler(twoFactorService *sharedService.TwoFactorService, userType shared.UserType, logger *logger.Logger) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		userType:         userType,
+		logger:           logger,
+	}
+}
+
+type ConfirmTwoFactorRequest struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+type ReauthenticateTwoFactorRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,numeric,len=6"`
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Setup handles POST /api/v1/admin/auth/2fa/setup and POST /api/v1/store/auth/2fa/setup
+func (h *TwoFactorHandler) Setup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.currentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	setup, err := h.twoFactorService.Setup(r.Context(), userID.String())
+	if err != nil {
+		h.respondError(w, "Two-factor setup failed", userID, err)
+		return
+	}
+
+	h.logger.Info("Two-factor setup started", "user_type", h.userType, "user_id", userID)
+	response.Success(w, setup, "Scan the secret with your authenticator app and confirm with a code")
+}
+
+// Confirm handles POST /api/v1/admin/auth/2fa/confirm and POST /api/v1/store/auth/2fa/confirm
+func (h *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.currentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req ConfirmTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(r.Context(), userID.String(), req.Code)
+	if err != nil {
+		h.respondError(w, "Two-factor confirmation failed", userID, err)
+		return
+	}
+
+	h.logger.Info("Two-factor enabled", "user_type", h.userType, "user_id", userID)
+	response.Success(w, RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}, "Two-factor authentication enabled successfully, store the recovery codes in a safe place")
+}
+
+// Disable handles POST /api/v1/admin/auth/2fa/disable and POST /api/v1/store/auth/2fa/disable
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.currentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req ReauthenticateTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	if err := h.twoFactorService.Disable(r.Context(), userID.String(), req.Password, req.Code); err != nil {
+		h.respondError(w, "Two-factor disable failed", userID, err)
+		return
+	}
+
+	h.logger.Info("Two-factor disabled", "user_type", h.userType, "user_id", userID)
+	response.Success(w, nil, "Two-factor authentication disabled successfully")
+}
+
+// Regenerate handles POST /api/v1/admin/auth/2fa/regenerate and POST /api/v1/store/auth/2fa/regenerate
+func (h *TwoFactorHandler) Regenerate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.currentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req ReauthenticateTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	setup, err := h.twoFactorService.Regenerate(r.Context(), userID.String(), req.Password, req.Code)
+	if err != nil {
+		h.respondError(w, "Two-factor regenerate failed", userID, err)
+		return
+	}
+
+	h.logger.Info("Two-factor secret regenerated", "user_type", h.userType, "user_id", userID)
+	response.Success(w, setup, "Scan the new secret with your authenticator app and confirm with a code")
+}
+
+// RegenerateRecoveryCodes handles POST /api/v1/admin/auth/2fa/recovery-codes and POST /api/v1/store/auth/2fa/recovery-codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.currentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req RegenerateRecoveryCodesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(r.Context(), userID.String(), req.Password)
+	if err != nil {
+		h.respondError(w, "Recovery code regeneration failed", userID, err)
+		return
+	}
+
+	h.logger.Info("Recovery codes regenerated", "user_type", h.userType, "user_id", userID)
+	response.Success(w, RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}, "Recovery codes regenerated successfully, previous codes no longer work")
+}
+
+// currentUserID returns the ID of the authenticated admin or customer
+func (h *TwoFactorHandler) currentUserID(r *http.Request) (uuid.UUID, bool) {
+	switch h.userType {
+	case shared.UserTypeAdmin:
+		if admin, ok := middleware.AdminFromContext(r.Context()); ok {
+			return admin.ID, true
+		}
+	case shared.UserTypeCustomer:
+		if customer, ok := middleware.CustomerFromContext(r.Context()); ok {
+			return customer.ID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// respondError maps two-factor service errors to HTTP responses
+func (h *TwoFactorHandler) respondError(w http.ResponseWriter, message string, userID uuid.UUID, err error) {
+	h.logger.Error(message, "user_type", h.userType, "user_id", userID, "error", err)
+
+	switch {
+	case errors.Is(err, domain.ErrTwoFactorAlreadyEnabled):
+		response.Error(w, http.StatusConflict, "Two-factor authentication is already enabled")
+	case errors.Is(err, domain.ErrTwoFactorNotEnabled):
+		response.Error(w, http.StatusBadRequest, "Two-factor authentication is not enabled")
+	case errors.Is(err, domain.ErrTwoFactorNotInitiated):
+		response.Error(w, http.StatusBadRequest, "Two-factor authentication setup has not been started")
+	case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+		response.Error(w, http.StatusUnprocessableEntity, "Invalid two-factor authentication code")
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		response.Error(w, http.StatusUnprocessableEntity, "Invalid password")
+	default:
+		response.Error(w, http.StatusInternalServerError, "Failed to update two-factor authentication")
+	}
+}
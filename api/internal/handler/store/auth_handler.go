@@ -1,36 +1,489 @@
 package store
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	storeDomain "github.com/yeftaz/susano.id/api/internal/domain/store"
+	"github.com/yeftaz/susano.id/api/internal/handler/shared"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/store"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
 	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
 )
 
+// magicLinkBindingCookie holds the nonce that binds a magic link to the browser that requested it
+const magicLinkBindingCookie = "magic_link_binding"
+
+// oidcStateCookie holds the state that binds a social login to the browser that started it
+const oidcStateCookie = "oidc_state"
+
 type AuthHandler struct {
-	logger *logger.Logger
+	authService      *store.AuthService
+	magicLinkService *store.MagicLinkService
+	oidcService      *store.OIDCService
+	logger           *logger.Logger
+	config           *config.Config
 }
 
-func NewAuthHandler(logger *logger.Logger) *AuthHandler {
+func NewAuthHandler(authService *store.AuthService, magicLinkService *store.MagicLinkService, oidcService *store.OIDCService, logger *logger.Logger, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
-		logger: logger,
+		authService:      authService,
+		magicLinkService: magicLinkService,
+		oidcService:      oidcService,
+		logger:           logger,
+		config:           cfg,
 	}
 }
 
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+type RegisterRequest struct {
+	Email                string `json:"email" validate:"required,email"`
+	Password             string `json:"password" validate:"required,max=72"`
+	PasswordConfirmation string `json:"password_confirmation" validate:"omitempty,eqfield=Password"`
+	Name                 string `json:"name" validate:"required"`
+}
+
+type LoginResponse struct {
+	Customer interface{} `json:"customer"`
+	Token    string      `json:"token"`
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type OIDCProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword      string `json:"current_password" validate:"required"`
+	Password             string `json:"password" validate:"required,max=72"`
+	PasswordConfirmation string `json:"password_confirmation" validate:"omitempty,eqfield=Password"`
+}
+
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required_without=RecoveryCode,omitempty,numeric,len=6"`
+	RecoveryCode   string `json:"recovery_code" validate:"required_without=Code"`
+}
+
 // Login handles POST /api/v1/store/auth/login
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement customer login
-	response.Success(w, nil, "Customer login endpoint - not implemented yet")
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	// Authenticate customer
+	result, err := h.authService.Login(r.Context(), req.Email, req.Password, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		h.logger.Error("Customer login failed", "email", req.Email, "error", err)
+		shared.RespondLoginError(w, err)
+		return
+	}
+
+	// Password was correct but a second factor is still required
+	h.respondLoginResult(w, result)
+}
+
+// RequestMagicLink handles POST /api/v1/store/auth/magic-link
+func (h *AuthHandler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req MagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	// Browsers send an Origin header with the request and can keep the binding cookie
+	bindBrowser := r.Header.Get("Origin") != ""
+
+	binding, err := h.magicLinkService.Request(r.Context(), req.Email, bindBrowser, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		var throttled *domain.ThrottledError
+		if errors.As(err, &throttled) {
+			response.ErrorWithRetryAfter(w, http.StatusTooManyRequests, "Please wait before requesting another login link", throttled.RetryAfter)
+			return
+		}
+		h.logger.Error("Magic link request failed", "email", req.Email, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to send login link")
+		return
+	}
+
+	if binding != "" {
+		h.setMagicLinkBindingCookie(w, binding, int(h.config.MagicLinkLifetime.Seconds()))
+	}
+
+	// Always succeed so the response does not reveal whether the email is registered
+	response.Success(w, nil, "If the email is registered, a login link has been sent")
+}
+
+// VerifyMagicLink handles POST /api/v1/store/auth/magic-link/verify
+func (h *AuthHandler) VerifyMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req store.MagicLinkParams
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	var binding string
+	if cookie, err := r.Cookie(magicLinkBindingCookie); err == nil {
+		binding = cookie.Value
+	}
+
+	result, err := h.magicLinkService.Consume(r.Context(), req, binding, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		h.logger.Error("Magic link login failed", "error", err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidMagicLink):
+			response.Error(w, http.StatusUnauthorized, "Login link is invalid or has expired")
+		case errors.Is(err, domain.ErrMagicLinkBrowserMismatch):
+			response.Error(w, http.StatusForbidden, "Please open the login link in the browser you requested it from")
+		default:
+			shared.RespondLoginError(w, err)
+		}
+		return
+	}
+
+	// The link is used up, so the binding is no longer needed
+	h.setMagicLinkBindingCookie(w, "", -1)
+
+	h.respondLoginResult(w, result)
+}
+
+// OIDCProviders handles GET /api/v1/store/auth/oidc/providers
+func (h *AuthHandler) OIDCProviders(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, OIDCProvidersResponse{Providers: h.oidcService.Providers()}, "Login providers retrieved successfully")
+}
+
+// AuthorizeOIDC handles POST /api/v1/store/auth/oidc/{provider}
+func (h *AuthHandler) AuthorizeOIDC(w http.ResponseWriter, r *http.Request) {
+	provider := mux.Vars(r)["provider"]
+
+	authorization, err := h.oidcService.Authorize(r.Context(), provider, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		if errors.Is(err, domain.ErrOIDCProviderNotFound) {
+			response.Error(w, http.StatusNotFound, "Login provider not found")
+			return
+		}
+		h.logger.Error("Social login authorization failed", "provider", provider, "error", err)
+		response.Error(w, http.StatusBadGateway, "Failed to start login with the provider")
+		return
+	}
+
+	h.setOIDCStateCookie(w, authorization.State, int(h.config.OIDCStateLifetime.Seconds()))
+
+	response.Success(w, authorization, "Redirect to the provider to continue")
+}
+
+// OIDCCallback handles POST /api/v1/store/auth/oidc/{provider}/callback
+func (h *AuthHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	provider := mux.Vars(r)["provider"]
+
+	var req store.OIDCCallback
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	var state string
+	if cookie, err := r.Cookie(oidcStateCookie); err == nil {
+		state = cookie.Value
+	}
+
+	// The state is used up whatever the outcome
+	h.setOIDCStateCookie(w, "", -1)
+
+	result, err := h.oidcService.Callback(r.Context(), provider, req, state, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		h.logger.Error("Social login failed", "provider", provider, "error", err)
+		switch {
+		case errors.Is(err, domain.ErrOIDCProviderNotFound):
+			response.Error(w, http.StatusNotFound, "Login provider not found")
+		case errors.Is(err, domain.ErrInvalidOIDCLogin):
+			response.Error(w, http.StatusUnauthorized, "Login is invalid or has expired, please try again")
+		case errors.Is(err, domain.ErrOIDCEmailNotVerified):
+			response.Error(w, http.StatusConflict, "The provider did not confirm your email, please login with your password to link the account")
+		case errors.Is(err, domain.ErrEmailAlreadyExists):
+			response.Error(w, http.StatusConflict, "Email is already registered")
+		default:
+			shared.RespondLoginError(w, err)
+		}
+		return
+	}
+
+	h.respondLoginResult(w, result)
+}
+
+// VerifyTwoFactor handles POST /api/v1/store/auth/2fa/verify
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req VerifyTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	// Complete the challenge
+	customer, session, err := h.authService.VerifyTwoFactor(r.Context(), req.ChallengeToken, req.Code, req.RecoveryCode, middleware.ClientIP(r), r.UserAgent())
+	if err != nil {
+		h.logger.Error("Customer two-factor verification failed", "error", err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+			response.Error(w, http.StatusUnauthorized, "Invalid two-factor authentication code")
+		case errors.Is(err, domain.ErrChallengeNotFound), errors.Is(err, domain.ErrChallengeExpired):
+			response.Error(w, http.StatusUnauthorized, "Two-factor challenge is invalid or has expired, please login again")
+		default:
+			shared.RespondLoginError(w, err)
+		}
+		return
+	}
+
+	h.logger.Info("Customer logged in", "customer_id", customer.ID, "email", customer.Email, "two_factor", true)
+
+	// Set session cookie
+	h.setSessionCookie(w, customer, session)
+
+	response.Success(w, LoginResponse{
+		Customer: customer,
+		Token:    session.Token,
+	}, "Login successful")
 }
 
 // Register handles POST /api/v1/store/auth/register
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement customer registration
-	response.Success(w, nil, "Customer register endpoint - not implemented yet")
+	var req RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	// Create customer
+	customer, err := h.authService.Register(r.Context(), req.Email, req.Password, req.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailAlreadyExists) {
+			response.Error(w, http.StatusConflict, "Email is already registered")
+			return
+		}
+		if shared.RespondWeakPassword(w, err) {
+			return
+		}
+		h.logger.Error("Customer registration failed", "email", req.Email, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to register")
+		return
+	}
+
+	h.logger.Info("Customer registered", "customer_id", customer.ID, "email", customer.Email)
+	response.Created(w, customer, "Registration successful, please check your email to verify your address")
 }
 
 // Logout handles POST /api/v1/store/auth/logout
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement customer logout
-	response.Success(w, nil, "Customer logout endpoint - not implemented yet")
+	// Get session token used to authenticate this request (bearer or cookie)
+	token, ok := middleware.SessionTokenFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "No session found")
+		return
+	}
+
+	// Delete session
+	if err := h.authService.Logout(r.Context(), token); err != nil {
+		h.logger.Error("Customer logout failed", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to logout")
+		return
+	}
+
+	// Clear cookie
+	http.SetCookie(w, &http.Cookie{
+		Name:     "customer_session_token",
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   h.config.SessionSecure,
+		MaxAge:   -1,
+		Domain:   h.config.SessionDomain,
+	})
+
+	response.Success(w, nil, "Logout successful")
+}
+
+// ChangePassword handles POST /api/v1/store/auth/password
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	session, ok := middleware.CustomerSessionFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "No session found")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	if err := h.authService.ChangePassword(r.Context(), customer.ID, session.ID, req.CurrentPassword, req.Password); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			response.Error(w, http.StatusUnprocessableEntity, "Current password is incorrect")
+			return
+		}
+		if shared.RespondWeakPassword(w, err) {
+			return
+		}
+		h.logger.Error("Password change failed", "customer_id", customer.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to change password")
+		return
+	}
+
+	h.logger.Info("Customer password changed", "customer_id", customer.ID)
+	response.Success(w, nil, "Password changed successfully, other sessions have been signed out")
+}
+
+// RefreshSession handles POST /api/v1/store/auth/refresh
+func (h *AuthHandler) RefreshSession(w http.ResponseWriter, r *http.Request) {
+	// Get session token used to authenticate this request (bearer or cookie)
+	token, ok := middleware.SessionTokenFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "No session found")
+		return
+	}
+
+	// Refresh session
+	if err := h.authService.RefreshSession(r.Context(), token); err != nil {
+		h.logger.Error("Customer session refresh failed", "error", err)
+		response.Error(w, http.StatusUnauthorized, "Failed to refresh session")
+		return
+	}
+
+	response.Success(w, nil, "Session refreshed successfully")
+}
+
+// setSessionCookie sets the customer session cookie to expire with the absolute lifetime of the session
+func (h *AuthHandler) setSessionCookie(w http.ResponseWriter, customer *storeDomain.Customer, session *storeDomain.Session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "customer_session_token",
+		Value:    session.Token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   h.config.SessionSecure,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   h.authService.SessionPolicy(customer, session).CookieMaxAge(session.CreatedAt),
+		Domain:   h.config.SessionDomain,
+	})
+}
+
+// setMagicLinkBindingCookie sets the magic link binding cookie, scoped to the magic link endpoints
+func (h *AuthHandler) setMagicLinkBindingCookie(w http.ResponseWriter, binding string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     magicLinkBindingCookie,
+		Value:    binding,
+		Path:     "/api/v1/store/auth/magic-link",
+		HttpOnly: true,
+		Secure:   h.config.SessionSecure,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   maxAge,
+		Domain:   h.config.SessionDomain,
+	})
+}
+
+// setOIDCStateCookie sets the social login state cookie, scoped to the social login endpoints
+func (h *AuthHandler) setOIDCStateCookie(w http.ResponseWriter, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/v1/store/auth/oidc",
+		HttpOnly: true,
+		Secure:   h.config.SessionSecure,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   maxAge,
+		Domain:   h.config.SessionDomain,
+	})
+}
+
+// respondLoginResult responds with the session of a completed login, or the pending two-factor challenge
+func (h *AuthHandler) respondLoginResult(w http.ResponseWriter, result *store.LoginResult) {
+	if result.RequiresTwoFactor() {
+		h.logger.Info("Customer two-factor challenge issued", "customer_id", result.Customer.ID)
+		response.Success(w, TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.Challenge.Token,
+			ExpiresAt:         result.Challenge.ExpiresAt,
+		}, "Two-factor authentication required")
+		return
+	}
+
+	h.logger.Info("Customer logged in", "customer_id", result.Customer.ID, "email", result.Customer.Email)
+
+	// Set session cookie
+	h.setSessionCookie(w, result.Customer, result.Session)
+
+	response.Success(w, LoginResponse{
+		Customer: result.Customer,
+		Token:    result.Session.Token,
+	}, "Login successful")
 }
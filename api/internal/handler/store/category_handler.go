@@ -0,0 +1,73 @@
+package store
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type CategoryHandler struct {
+	categoryService *catalog.CategoryService
+	logger          *logger.Logger
+}
+
+func NewCategoryHandler(categoryService *catalog.CategoryService, logger *logger.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		logger:          logger,
+	}
+}
+
+// Tree handles GET /api/v1/store/categories
+func (h *CategoryHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.categoryService.Tree(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get categories", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve categories")
+		return
+	}
+
+	response.Success(w, categories, "Categories retrieved successfully")
+}
+
+// Products handles GET /api/v1/store/categories/{slug}/products
+func (h *CategoryHandler) Products(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	// Parse query parameters
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// Products of subcategories are included, only active products are listed
+	category, products, total, err := h.categoryService.ListPublishedProducts(r.Context(), slug, page, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			response.Error(w, http.StatusNotFound, "Category not found")
+			return
+		}
+		h.logger.Error("Failed to list category products", "slug", slug, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve products")
+		return
+	}
+
+	response.SuccessWithMeta(w, products, "Products retrieved successfully", map[string]interface{}{
+		"page":     page,
+		"limit":    limit,
+		"total":    total,
+		"category": category,
+	})
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type CollectionHandler struct {
+	collectionService *catalog.CollectionService
+	logger            *logger.Logger
+}
+
+func NewCollectionHandler(collectionService *catalog.CollectionService, logger *logger.Logger) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+		logger:            logger,
+	}
+}
+
+// Products handles GET /api/v1/store/collections/{slug}/products
+func (h *CollectionHandler) Products(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	// Parse query parameters
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// Only active products are listed
+	collection, products, total, err := h.collectionService.ListPublishedProducts(r.Context(), slug, page, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrCollectionNotFound) {
+			response.Error(w, http.StatusNotFound, "Collection not found")
+			return
+		}
+		h.logger.Error("Failed to list collection products", "slug", slug, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve products")
+		return
+	}
+
+	response.SuccessWithMeta(w, products, "Products retrieved successfully", map[string]interface{}{
+		"page":       page,
+		"limit":      limit,
+		"total":      total,
+		"collection": collection,
+	})
+}
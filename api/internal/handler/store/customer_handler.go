@@ -1,30 +1,83 @@
 package store
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/store"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
 	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
 )
 
 type CustomerHandler struct {
-	logger *logger.Logger
+	customerService *store.CustomerService
+	logger          *logger.Logger
 }
 
-func NewCustomerHandler(logger *logger.Logger) *CustomerHandler {
+func NewCustomerHandler(customerService *store.CustomerService, logger *logger.Logger) *CustomerHandler {
 	return &CustomerHandler{
-		logger: logger,
+		customerService: customerService,
+		logger:          logger,
 	}
 }
 
+type UpdateProfileRequest struct {
+	Email string `json:"email" validate:"omitempty,email"`
+	Name  string `json:"name" validate:"omitempty"`
+}
+
 // GetProfile handles GET /api/v1/store/profile
 func (h *CustomerHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement get customer profile
-	response.Success(w, nil, "Customer profile endpoint - not implemented yet")
+	// Get customer from context (set by auth middleware)
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	response.Success(w, customer, "Profile retrieved successfully")
 }
 
 // UpdateProfile handles PATCH /api/v1/store/profile
 func (h *CustomerHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement update customer profile
-	response.Success(w, nil, "Update customer profile endpoint - not implemented yet")
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	// Update customer
+	updated, err := h.customerService.Update(r.Context(), customer.ID.String(), req.Email, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailAlreadyExists):
+			response.Error(w, http.StatusConflict, "Email is already registered")
+		case errors.Is(err, sql.ErrNoRows):
+			response.Error(w, http.StatusNotFound, "Customer not found")
+		default:
+			h.logger.Error("Failed to update profile", "customer_id", customer.ID, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to update profile")
+		}
+		return
+	}
+
+	h.logger.Info("Customer profile updated", "customer_id", customer.ID)
+	response.Success(w, updated, "Profile updated successfully")
 }
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/store"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type EmailVerificationHandler struct {
+	emailVerificationService *store.EmailVerificationService
+	logger                   *logger.Logger
+}
+
+func NewEmailVerificationHandler(emailVerificationService *store.EmailVerificationService, logger *logger.Logger) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		emailVerificationService: emailVerificationService,
+		logger:                   logger,
+	}
+}
+
+// Verify handles POST /api/v1/store/auth/email/verify
+func (h *EmailVerificationHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	var req store.VerificationLink
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	customer, err := h.emailVerificationService.Verify(r.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidVerificationLink):
+			response.Error(w, http.StatusUnprocessableEntity, "Verification link is invalid or has expired")
+		case errors.Is(err, domain.ErrEmailAlreadyVerified):
+			response.Error(w, http.StatusConflict, "Email is already verified")
+		default:
+			h.logger.Error("Email verification failed", "customer_id", req.ID, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to verify email")
+		}
+		return
+	}
+
+	h.logger.Info("Customer email verified", "customer_id", customer.ID)
+	response.Success(w, customer, "Email verified successfully")
+}
+
+// Resend handles POST /api/v1/store/auth/email/resend
+func (h *EmailVerificationHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.emailVerificationService.Resend(r.Context(), customer.ID.String()); err != nil {
+		var throttled *domain.ThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			response.ErrorWithRetryAfter(w, http.StatusTooManyRequests, "Please wait before requesting another verification email", throttled.RetryAfter)
+		case errors.Is(err, domain.ErrEmailAlreadyVerified):
+			response.Error(w, http.StatusConflict, "Email is already verified")
+		default:
+			h.logger.Error("Resend verification email failed", "customer_id", customer.ID, "error", err)
+			response.Error(w, http.StatusInternalServerError, "Failed to send verification email")
+		}
+		return
+	}
+
+	response.Success(w, nil, "Verification email sent")
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/store"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type IdentityHandler struct {
+	oidcService *store.OIDCService
+	logger      *logger.Logger
+}
+
+func NewIdentityHandler(oidcService *store.OIDCService, logger *logger.Logger) *IdentityHandler {
+	return &IdentityHandler{
+		oidcService: oidcService,
+		logger:      logger,
+	}
+}
+
+// List handles GET /api/v1/store/auth/identities
+func (h *IdentityHandler) List(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	identities, err := h.oidcService.Identities(r.Context(), customer.ID)
+	if err != nil {
+		h.logger.Error("Failed to list linked identities", "customer_id", customer.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve linked accounts")
+		return
+	}
+
+	response.Success(w, identities, "Linked accounts retrieved successfully")
+}
+
+// Unlink handles DELETE /api/v1/store/auth/identities/{id}
+func (h *IdentityHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	identityID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Linked account not found")
+		return
+	}
+
+	if err := h.oidcService.Unlink(r.Context(), customer.ID, identityID); err != nil {
+		if errors.Is(err, domain.ErrIdentityNotFound) {
+			response.Error(w, http.StatusNotFound, "Linked account not found")
+			return
+		}
+		h.logger.Error("Failed to unlink identity", "customer_id", customer.ID, "identity_id", identityID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to unlink account")
+		return
+	}
+
+	h.logger.Info("Customer identity unlinked", "customer_id", customer.ID, "identity_id", identityID)
+	response.Success(w, nil, "Account unlinked successfully")
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type LoginHistoryHandler struct {
+	historyService *sharedService.LoginHistoryService
+	logger         *logger.Logger
+}
+
+func NewLoginHistoryHandler(historyService *sharedService.LoginHistoryService, logger *logger.Logger) *LoginHistoryHandler {
+	return &LoginHistoryHandler{
+		historyService: historyService,
+		logger:         logger,
+	}
+}
+
+// List handles GET /api/v1/store/auth/login-history
+func (h *LoginHistoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse pagination
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	history, total, err := h.historyService.List(r.Context(), shared.UserTypeCustomer, customer.ID, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to get login history", "customer_id", customer.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve login history")
+		return
+	}
+
+	response.SuccessWithMeta(w, history, "Login history retrieved successfully", map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/handler/shared"
+	"github.com/yeftaz/susano.id/api/internal/service/store"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type PasswordResetHandler struct {
+	passwordResetService *store.PasswordResetService
+	logger               *logger.Logger
+}
+
+func NewPasswordResetHandler(passwordResetService *store.PasswordResetService, logger *logger.Logger) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetService: passwordResetService,
+		logger:               logger,
+	}
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Email                string `json:"email" validate:"required,email"`
+	Token                string `json:"token" validate:"required"`
+	Password             string `json:"password" validate:"required,max=72"`
+	PasswordConfirmation string `json:"password_confirmation" validate:"required,eqfield=Password"`
+}
+
+// ForgotPassword handles POST /api/v1/store/auth/forgot-password
+func (h *PasswordResetHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	if err := h.passwordResetService.ForgotPassword(r.Context(), req.Email); err != nil {
+		h.logger.Error("Forgot password failed", "email", req.Email, "error", err)
+	}
+
+	// Same response whether or not the email exists
+	response.Success(w, nil, "If the email is registered, a password reset link has been sent")
+}
+
+// ResetPassword handles POST /api/v1/store/auth/reset-password
+func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	if err := h.passwordResetService.ResetPassword(r.Context(), req.Email, req.Token, req.Password); err != nil {
+		if errors.Is(err, domain.ErrInvalidResetToken) {
+			response.Error(w, http.StatusUnprocessableEntity, "Password reset token is invalid or has expired")
+			return
+		}
+		if shared.RespondWeakPassword(w, err) {
+			return
+		}
+		h.logger.Error("Reset password failed", "email", req.Email, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to reset password")
+		return
+	}
+
+	h.logger.Info("Customer password reset", "email", req.Email)
+	response.Success(w, nil, "Password has been reset, please login with your new password")
+}
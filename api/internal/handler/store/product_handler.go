@@ -0,0 +1,75 @@
+package store
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type ProductHandler struct {
+	productService *catalog.ProductService
+	logger         *logger.Logger
+}
+
+func NewProductHandler(productService *catalog.ProductService, logger *logger.Logger) *ProductHandler {
+	return &ProductHandler{
+		productService: productService,
+		logger:         logger,
+	}
+}
+
+// List handles GET /api/v1/store/products
+func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	search := r.URL.Query().Get("search")
+	tag := r.URL.Query().Get("tag")
+
+	// Only active products are listed
+	products, total, err := h.productService.ListPublished(r.Context(), page, limit, search, tag)
+	if err != nil {
+		h.logger.Error("Failed to list products", "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve products")
+		return
+	}
+
+	response.SuccessWithMeta(w, products, "Products retrieved successfully", map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetBySlug handles GET /api/v1/store/products/{slug}
+func (h *ProductHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	product, err := h.productService.GetPublishedBySlug(r.Context(), slug)
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			response.Error(w, http.StatusNotFound, "Product not found")
+			return
+		}
+		h.logger.Error("Failed to get product", "slug", slug, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve product")
+		return
+	}
+
+	response.Success(w, product, "Product retrieved successfully")
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	catalogDomain "github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/catalog"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+	"github.com/yeftaz/susano.id/api/pkg/validator"
+)
+
+type ReservationHandler struct {
+	inventoryService *catalog.InventoryService
+	logger           *logger.Logger
+}
+
+func NewReservationHandler(inventoryService *catalog.InventoryService, logger *logger.Logger) *ReservationHandler {
+	return &ReservationHandler{
+		inventoryService: inventoryService,
+		logger:           logger,
+	}
+}
+
+type ReservationItemRequest struct {
+	VariantID uuid.UUID `json:"variant_id" validate:"required"`
+	Quantity  int       `json:"quantity" validate:"required,min=1,max=1000"`
+}
+
+type ReserveRequest struct {
+	Items []ReservationItemRequest `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// Reserve handles POST /api/v1/store/checkout/reservations
+func (h *ReservationHandler) Reserve(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req ReserveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Validate request
+	if err := validator.Validate(req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	items := make([]catalogDomain.ReservationItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = catalogDomain.ReservationItem{VariantID: item.VariantID, Quantity: item.Quantity}
+	}
+
+	reservation, err := h.inventoryService.Reserve(r.Context(), customer.ID, items)
+	if err != nil {
+		h.respondError(w, "Failed to reserve stock", customer.ID.String(), err)
+		return
+	}
+
+	h.logger.Info("Stock reserved", "customer_id", customer.ID, "reservation_id", reservation.ID)
+	response.Created(w, reservation, "Stock reserved successfully")
+}
+
+// Get handles GET /api/v1/store/checkout/reservations/{id}
+func (h *ReservationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Reservation not found")
+		return
+	}
+
+	reservation, err := h.inventoryService.GetReservation(r.Context(), customer.ID, id)
+	if err != nil {
+		h.respondError(w, "Failed to get reservation", customer.ID.String(), err)
+		return
+	}
+
+	response.Success(w, reservation, "Reservation retrieved successfully")
+}
+
+// Release handles DELETE /api/v1/store/checkout/reservations/{id}
+func (h *ReservationHandler) Release(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Reservation not found")
+		return
+	}
+
+	if err := h.inventoryService.Release(r.Context(), customer.ID, id); err != nil {
+		h.respondError(w, "Failed to release reservation", customer.ID.String(), err)
+		return
+	}
+
+	h.logger.Info("Stock reservation released", "customer_id", customer.ID, "reservation_id", id)
+	response.Success(w, nil, "Reservation released successfully")
+}
+
+// respondError maps reservation errors to HTTP responses
+func (h *ReservationHandler) respondError(w http.ResponseWriter, message, customerID string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrVariantNotFound):
+		response.ValidationError(w, map[string]string{"items": "items must be available variants of listed products"})
+	case errors.Is(err, domain.ErrInsufficientStock):
+		response.Error(w, http.StatusConflict, "Not enough stock available")
+	case errors.Is(err, domain.ErrReservationNotFound):
+		response.Error(w, http.StatusNotFound, "Reservation not found")
+	case errors.Is(err, domain.ErrReservationInactive):
+		response.Error(w, http.StatusConflict, "Reservation was already released, completed or has expired")
+	case errors.Is(err, domain.ErrNoOnlineLocation):
+		response.Error(w, http.StatusServiceUnavailable, "Online orders cannot be placed at the moment")
+	default:
+		h.logger.Error(message, "customer_id", customerID, "error", err)
+		response.Error(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	storeDomain "github.com/yeftaz/susano.id/api/internal/domain/store"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+	"github.com/yeftaz/susano.id/api/internal/service/store"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+type SessionHandler struct {
+	sessionService *store.SessionService
+	logger         *logger.Logger
+}
+
+func NewSessionHandler(sessionService *store.SessionService, logger *logger.Logger) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+		logger:         logger,
+	}
+}
+
+type SessionResponse struct {
+	*storeDomain.Session
+	Current      bool `json:"current"`
+	Impersonated bool `json:"impersonated"`
+}
+
+type RevokedSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// List handles GET /api/v1/store/auth/sessions
+func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	current, _ := middleware.CustomerSessionFromContext(r.Context())
+
+	sessions, err := h.sessionService.List(r.Context(), customer)
+	if err != nil {
+		h.logger.Error("Failed to list sessions", "customer_id", customer.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve sessions")
+		return
+	}
+
+	// Mark the session used for this request
+	data := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, SessionResponse{
+			Session:      session,
+			Current:      current != nil && session.ID == current.ID,
+			Impersonated: session.IsImpersonated(),
+		})
+	}
+
+	response.Success(w, data, "Sessions retrieved successfully")
+}
+
+// Revoke handles DELETE /api/v1/store/auth/sessions/{id}
+func (h *SessionHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	sessionID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	if err := h.sessionService.Revoke(r.Context(), customer.ID, sessionID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			response.Error(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		h.logger.Error("Failed to revoke session", "customer_id", customer.ID, "session_id", sessionID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+
+	h.logger.Info("Customer session revoked", "customer_id", customer.ID, "session_id", sessionID)
+	response.Success(w, nil, "Session revoked successfully")
+}
+
+// RevokeOthers handles DELETE /api/v1/store/auth/sessions
+func (h *SessionHandler) RevokeOthers(w http.ResponseWriter, r *http.Request) {
+	customer, ok := middleware.CustomerFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	current, ok := middleware.CustomerSessionFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeOthers(r.Context(), customer.ID, current.ID)
+	if err != nil {
+		h.logger.Error("Failed to revoke other sessions", "customer_id", customer.ID, "error", err)
+		response.Error(w, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	h.logger.Info("Customer other sessions revoked", "customer_id", customer.ID, "revoked", revoked)
+	response.Success(w, RevokedSessionsResponse{Revoked: revoked}, "Logged out of all other sessions")
+}
@@ -2,9 +2,11 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
 	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
 	"github.com/yeftaz/susano.id/api/internal/service/admin"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
@@ -15,23 +17,32 @@ import (
 type contextKey string
 
 const (
-	contextKeyAdmin   contextKey = "admin"
-	contextKeyAdminID contextKey = "admin_id"
+	contextKeyAdmin        contextKey = "admin"
+	contextKeyAdminID      contextKey = "admin_id"
+	contextKeyAdminSession contextKey = "admin_session"
+	contextKeyAdminAPIKey  contextKey = "admin_api_key"
 )
 
-// AdminAuth middleware verifies admin session
-func AdminAuth(authService *admin.AuthService, cfg *config.Config, logger *logger.Logger) func(http.Handler) http.Handler {
+// AdminAuth middleware verifies admin session from a bearer token or cookie,
+// or an API key given as a bearer token
+func AdminAuth(authService *admin.AuthService, apiKeyService *admin.APIKeyService, cfg *config.Config, logger *logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Get session token from cookie
-			cookie, err := r.Cookie("session_token")
-			if err != nil {
+			// API keys are always accepted as bearer tokens, integrations cannot keep cookies
+			if token, ok := BearerToken(r); ok && adminDomain.IsAPIKey(token) {
+				authenticateAPIKey(w, r, next, apiKeyService, token, logger)
+				return
+			}
+
+			// Get session token from Authorization header or cookie
+			token, fromCookie, ok := sessionToken(r, "session_token", cfg.AdminBearerTokens)
+			if !ok {
 				response.Error(w, http.StatusUnauthorized, "Unauthorized: No session found")
 				return
 			}
 
 			// Verify session and get admin
-			adminUser, err := authService.VerifySession(r.Context(), cookie.Value, cfg.SessionLifetime)
+			adminUser, session, err := authService.VerifySession(r.Context(), token)
 			if err != nil {
 				logger.Error("Session verification failed", "error", err)
 				response.Error(w, http.StatusUnauthorized, "Unauthorized: Invalid or expired session")
@@ -44,20 +55,92 @@ func AdminAuth(authService *admin.AuthService, cfg *config.Config, logger *logge
 				return
 			}
 
+			// Tell the client when the session expires if it stays idle
+			policy := authService.SessionPolicy(adminUser)
+			setSessionHeaders(w, session.ExpiresAt(policy), policy.IdleTimeout)
+
 			// Add admin to request context
 			ctx := context.WithValue(r.Context(), contextKeyAdmin, adminUser)
 			ctx = context.WithValue(ctx, contextKeyAdminID, adminUser.ID.String())
+			ctx = context.WithValue(ctx, contextKeyAdminSession, session)
+			ctx = withSessionToken(ctx, token, fromCookie)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// authenticateAPIKey verifies an API key and serves the request as the admin owning it
+func authenticateAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, apiKeyService *admin.APIKeyService, token string, logger *logger.Logger) {
+	adminUser, key, err := apiKeyService.Authenticate(r.Context(), token, ClientIP(r))
+	if err != nil {
+		var throttled *domain.ThrottledError
+		if errors.As(err, &throttled) {
+			response.ErrorWithRetryAfter(w, http.StatusTooManyRequests, "API key rate limit exceeded", throttled.RetryAfter)
+			return
+		}
+		logger.Error("API key verification failed", "error", err)
+		response.Error(w, http.StatusUnauthorized, "Unauthorized: Invalid, expired or revoked API key")
+		return
+	}
+
+	// Check if the owning admin is active and not deleted
+	if !adminUser.CanAccessAdminPanel() {
+		response.Error(w, http.StatusForbidden, "Account is inactive or deleted")
+		return
+	}
+
+	// Add admin and key to request context
+	ctx := context.WithValue(r.Context(), contextKeyAdmin, adminUser)
+	ctx = context.WithValue(ctx, contextKeyAdminID, adminUser.ID.String())
+	ctx = context.WithValue(ctx, contextKeyAdminAPIKey, key)
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RequireAdminSession middleware refuses requests made with an API key, for self-service actions
+// such as managing credentials that need a signed-in admin. Use it after AdminAuth.
+func RequireAdminSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := AdminSessionFromContext(r.Context()); !ok {
+			response.Error(w, http.StatusForbidden, "This action requires a signed-in admin and cannot be done with an API key")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AdminFromContext returns the authenticated admin set by AdminAuth
+func AdminFromContext(ctx context.Context) (*adminDomain.Admin, bool) {
+	adminUser, ok := ctx.Value(contextKeyAdmin).(*adminDomain.Admin)
+	return adminUser, ok
+}
+
+// AdminActor returns the authenticated admin set by AdminAuth along with the request origin, for auditing
+func AdminActor(r *http.Request) (adminDomain.Actor, bool) {
+	adminUser, ok := AdminFromContext(r.Context())
+	if !ok {
+		return adminDomain.Actor{}, false
+	}
+
+	actor := adminDomain.Actor{
+		Admin:     adminUser,
+		IPAddress: ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+	if key, ok := AdminAPIKeyFromContext(r.Context()); ok {
+		actor.APIKeyID = &key.ID
+	}
+
+	return actor, true
+}
+
 // RequireRole middleware checks if admin has required role
 func RequireRole(roles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			adminUser, ok := r.Context().Value(contextKeyAdmin).(*adminDomain.Admin)
+			adminUser, ok := AdminFromContext(r.Context())
 			if !ok {
 				response.Error(w, http.StatusUnauthorized, "Unauthorized")
 				return
@@ -81,3 +164,48 @@ func RequireRole(roles ...string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// RequirePermission middleware checks if the admin's role grants the permission,
+// and for requests made with an API key also that the key is scoped for it
+func RequirePermission(permissionService *admin.PermissionService, logger *logger.Logger, permission adminDomain.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			adminUser, ok := AdminFromContext(r.Context())
+			if !ok {
+				response.Error(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			allowed, err := permissionService.HasPermission(r.Context(), adminUser, permission)
+			if err != nil {
+				logger.Error("Permission check failed", "admin_id", adminUser.ID, "permission", permission, "error", err)
+				response.Error(w, http.StatusInternalServerError, "Failed to check permissions")
+				return
+			}
+
+			if !allowed {
+				response.Error(w, http.StatusForbidden, "Insufficient permissions")
+				return
+			}
+
+			if key, ok := AdminAPIKeyFromContext(r.Context()); ok && !key.HasScope(permission) {
+				response.Error(w, http.StatusForbidden, "API key is not scoped for this action")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AdminSessionFromContext returns the session that authenticated the current request
+func AdminSessionFromContext(ctx context.Context) (*adminDomain.Session, bool) {
+	session, ok := ctx.Value(contextKeyAdminSession).(*adminDomain.Session)
+	return session, ok
+}
+
+// AdminAPIKeyFromContext returns the API key that authenticated the current request, if any
+func AdminAPIKeyFromContext(ctx context.Context) (*adminDomain.APIKey, bool) {
+	key, ok := ctx.Value(contextKeyAdminAPIKey).(*adminDomain.APIKey)
+	return key, ok
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+)
+
+const contextKeyClientIP contextKey = "client_ip"
+
+// RealIP middleware resolves the client IP address of a request once, for ClientIP.
+// X-Forwarded-For and X-Real-IP are only honoured when the request comes from a trusted
+// proxy, since anyone else can send them to pose as another address.
+func RealIP(cfg *config.Config) func(http.Handler) http.Handler {
+	trusted := parseTrustedProxies(cfg.TrustedProxies)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trusted)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKeyClientIP, ip)))
+		})
+	}
+}
+
+// ClientIP returns the client IP address of a request without the port
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(contextKeyClientIP).(string); ok {
+		return ip
+	}
+	return remoteHost(r)
+}
+
+// resolveClientIP walks X-Forwarded-For from the nearest hop back and returns the first address
+// that is not a trusted proxy. Without trusted proxies only the peer address counts.
+func resolveClientIP(r *http.Request, trusted []*net.IPNet) string {
+	peer := remoteHost(r)
+	if !isTrustedProxy(peer, trusted) {
+		return peer
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				return peer
+			}
+			if i == 0 || !isTrustedProxy(hop, trusted) {
+				return hop
+			}
+		}
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return peer
+}
+
+// remoteHost returns the address of the peer that sent the request
+func remoteHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// isTrustedProxy checks if an IP address belongs to one of the trusted proxy networks
+func isTrustedProxy(ip string, trusted []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies turns IP addresses and CIDR ranges into networks; config validation
+// rejects invalid entries, so they are skipped here
+func parseTrustedProxies(entries []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range entries {
+		if network := config.ParseProxyNetwork(entry); network != nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
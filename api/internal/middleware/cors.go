@@ -25,7 +25,8 @@ func CORS(cfg *config.Config) func(http.Handler) http.Handler {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+HeaderCSRFToken)
+				w.Header().Set("Access-Control-Expose-Headers", HeaderSessionExpiresAt+", "+HeaderSessionIdleTimeout+", "+HeaderImpersonation)
 				w.Header().Set("Access-Control-Max-Age", "3600")
 			}
 
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/pkg/response"
+)
+
+// HeaderCSRFToken is the request header carrying the CSRF token of the session
+const HeaderCSRFToken = "X-CSRF-Token"
+
+type csrfTokenContextKey struct{}
+
+// CSRF middleware requires state-changing requests authenticated with a session cookie to send
+// the CSRF token of the session in the X-CSRF-Token header. The token is derived from the session,
+// so it stays valid until the session ends. Requests with a bearer token or API key are skipped,
+// browsers never attach those on their own. Use it after AdminAuth or CustomerAuth.
+func CSRF(cfg *config.Config) func(http.Handler) http.Handler {
+	key := []byte(cfg.AppKey)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sessionID, ok := csrfSessionID(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := csrfToken(key, sessionID)
+
+			if isCookieSession(r.Context()) && !isSafeMethod(r.Method) {
+				if !hmac.Equal([]byte(r.Header.Get(HeaderCSRFToken)), []byte(token)) {
+					response.Error(w, http.StatusForbidden, "Invalid or missing CSRF token")
+					return
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), csrfTokenContextKey{}, token)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// CSRFTokenFromContext returns the CSRF token of the current session set by CSRF
+func CSRFTokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(csrfTokenContextKey{}).(string)
+	return token, ok
+}
+
+// csrfSessionID identifies the admin or customer session of the request
+func csrfSessionID(ctx context.Context) (string, bool) {
+	if session, ok := AdminSessionFromContext(ctx); ok {
+		return "admin:" + session.ID.String(), true
+	}
+	if session, ok := CustomerSessionFromContext(ctx); ok {
+		return "customer:" + session.ID.String(), true
+	}
+	return "", false
+}
+
+// csrfToken computes the CSRF token of a session
+func csrfToken(key []byte, sessionID string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("csrf:" + sessionID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// isSafeMethod checks if a request method does not change state
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/yeftaz/susano.id/api/internal/config"
+	storeDomain "github.com/yeftaz/susano.id/api/internal/domain/store"
 	"github.com/yeftaz/susano.id/api/internal/service/store"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
 	"github.com/yeftaz/susano.id/api/pkg/response"
@@ -14,23 +15,24 @@ import (
 type customerContextKey string
 
 const (
-	contextKeyCustomer   customerContextKey = "customer"
-	contextKeyCustomerID customerContextKey = "customer_id"
+	contextKeyCustomer        customerContextKey = "customer"
+	contextKeyCustomerID      customerContextKey = "customer_id"
+	contextKeyCustomerSession customerContextKey = "customer_session"
 )
 
-// CustomerAuth middleware verifies customer session
+// CustomerAuth middleware verifies customer session from a bearer token or cookie
 func CustomerAuth(authService *store.AuthService, cfg *config.Config, logger *logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Get session token from cookie
-			cookie, err := r.Cookie("customer_session_token")
-			if err != nil {
+			// Get session token from Authorization header or cookie
+			token, fromCookie, ok := sessionToken(r, "customer_session_token", cfg.CustomerBearerTokens)
+			if !ok {
 				response.Error(w, http.StatusUnauthorized, "Unauthorized: No session found")
 				return
 			}
 
 			// Verify session and get customer
-			customer, err := authService.VerifySession(r.Context(), cookie.Value, cfg.SessionLifetime)
+			customer, session, err := authService.VerifySession(r.Context(), token)
 			if err != nil {
 				logger.Error("Customer session verification failed", "error", err)
 				response.Error(w, http.StatusUnauthorized, "Unauthorized: Invalid or expired session")
@@ -43,11 +45,72 @@ func CustomerAuth(authService *store.AuthService, cfg *config.Config, logger *lo
 				return
 			}
 
+			// Tell the client when the session expires if it stays idle
+			policy := authService.SessionPolicy(customer, session)
+			setSessionHeaders(w, session.ExpiresAt(policy), policy.IdleTimeout)
+
+			// Flag every response of a session an admin is impersonating
+			if session.IsImpersonated() {
+				w.Header().Set(HeaderImpersonation, "true")
+			}
+
 			// Add customer to request context
 			ctx := context.WithValue(r.Context(), contextKeyCustomer, customer)
 			ctx = context.WithValue(ctx, contextKeyCustomerID, customer.ID.String())
+			ctx = context.WithValue(ctx, contextKeyCustomerSession, session)
+			ctx = withSessionToken(ctx, token, fromCookie)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// CustomerFromContext returns the authenticated customer set by CustomerAuth
+func CustomerFromContext(ctx context.Context) (*storeDomain.Customer, bool) {
+	customer, ok := ctx.Value(contextKeyCustomer).(*storeDomain.Customer)
+	return customer, ok
+}
+
+// RequireVerifiedEmail middleware blocks customers whose email is not verified yet.
+// Use it after CustomerAuth on routes like checkout.
+func RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		customer, ok := CustomerFromContext(r.Context())
+		if !ok {
+			response.Error(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		if !customer.IsEmailVerified() {
+			response.Error(w, http.StatusForbidden, "Please verify your email address first")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// BlockImpersonation middleware refuses sensitive actions, such as changing credentials or paying,
+// while an admin is impersonating the customer. Use it after CustomerAuth.
+func BlockImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, ok := CustomerSessionFromContext(r.Context())
+		if !ok {
+			response.Error(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		if session.IsImpersonated() {
+			response.Error(w, http.StatusForbidden, "This action is not allowed while impersonating a customer")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CustomerSessionFromContext returns the session that authenticated the current request
+func CustomerSessionFromContext(ctx context.Context) (*storeDomain.Session, bool) {
+	session, ok := ctx.Value(contextKeyCustomerSession).(*storeDomain.Session)
+	return session, ok
+}
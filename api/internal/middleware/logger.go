@@ -47,7 +47,7 @@ func Logger(logger *logger.Logger) func(http.Handler) http.Handler {
 				"status", wrapped.status,
 				"duration_ms", duration.Milliseconds(),
 				"size", wrapped.size,
-				"ip", r.RemoteAddr,
+				"ip", ClientIP(r),
 				"user_agent", r.UserAgent(),
 			)
 		})
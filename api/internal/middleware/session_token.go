@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response headers describing the session policy
+const (
+	HeaderSessionExpiresAt   = "X-Session-Expires-At"
+	HeaderSessionIdleTimeout = "X-Session-Idle-Timeout"
+	HeaderImpersonation      = "X-Impersonation"
+)
+
+type sessionTokenContextKey struct{}
+
+type cookieSessionContextKey struct{}
+
+// sessionToken extracts the session token from the Authorization header (when allowed) or the session cookie,
+// and reports whether it came from the cookie
+func sessionToken(r *http.Request, cookieName string, allowBearer bool) (token string, fromCookie bool, ok bool) {
+	if allowBearer {
+		if token, ok := BearerToken(r); ok {
+			return token, false, true
+		}
+	}
+
+	cookie, err := r.Cookie(cookieName)
+	if err != nil || cookie.Value == "" {
+		return "", false, false
+	}
+
+	return cookie.Value, true, true
+}
+
+// BearerToken returns the token of an "Authorization: Bearer <token>" header
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// withSessionToken adds the session token used to authenticate the request to the context
+func withSessionToken(ctx context.Context, token string, fromCookie bool) context.Context {
+	ctx = context.WithValue(ctx, sessionTokenContextKey{}, token)
+	return context.WithValue(ctx, cookieSessionContextKey{}, fromCookie)
+}
+
+// SessionTokenFromContext returns the session token set by AdminAuth or CustomerAuth
+func SessionTokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(sessionTokenContextKey{}).(string)
+	return token, ok
+}
+
+// isCookieSession checks if the request was authenticated with a session cookie, which browsers send on their own
+func isCookieSession(ctx context.Context) bool {
+	fromCookie, _ := ctx.Value(cookieSessionContextKey{}).(bool)
+	return fromCookie
+}
+
+// setSessionHeaders reports the session expiry (RFC 3339) and idle timeout (seconds) to the client
+func setSessionHeaders(w http.ResponseWriter, expiresAt time.Time, idleTimeout time.Duration) {
+	if !expiresAt.IsZero() {
+		w.Header().Set(HeaderSessionExpiresAt, expiresAt.UTC().Format(time.RFC3339))
+	}
+	if idleTimeout > 0 {
+		w.Header().Set(HeaderSessionIdleTimeout, strconv.Itoa(int(idleTimeout.Seconds())))
+	}
+}
@@ -5,16 +5,18 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
 	"github.com/yeftaz/susano.id/api/internal/domain/admin"
 )
 
 type AdminRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 func NewAdminRepository(db *sql.DB) *AdminRepository {
 	return &AdminRepository{
-		db: db,
+		db: database.Wrap(db),
 	}
 }
 
@@ -22,8 +24,8 @@ func NewAdminRepository(db *sql.DB) *AdminRepository {
 func (r *AdminRepository) FindByEmail(ctx context.Context, email string) (*admin.Admin, error) {
 	query := `
         SELECT id, email, password, name, avatar_path, role, is_active,
-               email_verified_at, two_factor_secret, two_factor_recovery_codes,
-               two_factor_confirmed_at, created_at, updated_at, deleted_at
+               email_verified_at, two_factor_secret, two_factor_pending_secret, two_factor_recovery_codes,
+               two_factor_confirmed_at, location_id, created_at, updated_at, deleted_at
         FROM admins
         WHERE email = $1 AND deleted_at IS NULL
     `
@@ -31,8 +33,8 @@ func (r *AdminRepository) FindByEmail(ctx context.Context, email string) (*admin
 	var a admin.Admin
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&a.ID, &a.Email, &a.Password, &a.Name, &a.AvatarPath, &a.Role,
-		&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorRecoveryCodes,
-		&a.TwoFactorConfirmedAt, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+		&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorPendingSecret, &a.TwoFactorRecoveryCodes,
+		&a.TwoFactorConfirmedAt, &a.LocationID, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
 	)
 
 	if err != nil {
@@ -46,8 +48,8 @@ func (r *AdminRepository) FindByEmail(ctx context.Context, email string) (*admin
 func (r *AdminRepository) FindByID(ctx context.Context, id string) (*admin.Admin, error) {
 	query := `
         SELECT id, email, password, name, avatar_path, role, is_active,
-               email_verified_at, two_factor_secret, two_factor_recovery_codes,
-               two_factor_confirmed_at, created_at, updated_at, deleted_at
+               email_verified_at, two_factor_secret, two_factor_pending_secret, two_factor_recovery_codes,
+               two_factor_confirmed_at, location_id, created_at, updated_at, deleted_at
         FROM admins
         WHERE id = $1 AND deleted_at IS NULL
     `
@@ -55,8 +57,8 @@ func (r *AdminRepository) FindByID(ctx context.Context, id string) (*admin.Admin
 	var a admin.Admin
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&a.ID, &a.Email, &a.Password, &a.Name, &a.AvatarPath, &a.Role,
-		&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorRecoveryCodes,
-		&a.TwoFactorConfirmedAt, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+		&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorPendingSecret, &a.TwoFactorRecoveryCodes,
+		&a.TwoFactorConfirmedAt, &a.LocationID, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
 	)
 
 	if err != nil {
@@ -73,8 +75,8 @@ func (r *AdminRepository) GetAll(ctx context.Context, page, limit int, search, r
 	// Build query with filters
 	query := `
         SELECT id, email, password, name, avatar_path, role, is_active,
-               email_verified_at, two_factor_secret, two_factor_recovery_codes,
-               two_factor_confirmed_at, created_at, updated_at, deleted_at
+               email_verified_at, two_factor_secret, two_factor_pending_secret, two_factor_recovery_codes,
+               two_factor_confirmed_at, location_id, created_at, updated_at, deleted_at
         FROM admins
         WHERE deleted_at IS NULL
     `
@@ -123,8 +125,8 @@ func (r *AdminRepository) GetAll(ctx context.Context, page, limit int, search, r
 		var a admin.Admin
 		err := rows.Scan(
 			&a.ID, &a.Email, &a.Password, &a.Name, &a.AvatarPath, &a.Role,
-			&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorRecoveryCodes,
-			&a.TwoFactorConfirmedAt, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+			&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorPendingSecret, &a.TwoFactorRecoveryCodes,
+			&a.TwoFactorConfirmedAt, &a.LocationID, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -141,15 +143,15 @@ func (r *AdminRepository) Create(ctx context.Context, email, passwordHash, name,
         INSERT INTO admins (id, email, password, name, role, is_active, created_at, updated_at)
         VALUES (gen_uuid_v7(), $1, $2, $3, $4, true, NOW(), NOW())
         RETURNING id, email, password, name, avatar_path, role, is_active,
-                  email_verified_at, two_factor_secret, two_factor_recovery_codes,
-                  two_factor_confirmed_at, created_at, updated_at, deleted_at
+                  email_verified_at, two_factor_secret, two_factor_pending_secret, two_factor_recovery_codes,
+                  two_factor_confirmed_at, location_id, created_at, updated_at, deleted_at
     `
 
 	var a admin.Admin
 	err := r.db.QueryRowContext(ctx, query, email, passwordHash, name, role).Scan(
 		&a.ID, &a.Email, &a.Password, &a.Name, &a.AvatarPath, &a.Role,
-		&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorRecoveryCodes,
-		&a.TwoFactorConfirmedAt, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+		&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorPendingSecret, &a.TwoFactorRecoveryCodes,
+		&a.TwoFactorConfirmedAt, &a.LocationID, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
 	)
 
 	if err != nil {
@@ -189,15 +191,15 @@ func (r *AdminRepository) Update(ctx context.Context, id string, email, name, ro
 
 	query += `
         RETURNING id, email, password, name, avatar_path, role, is_active,
-                  email_verified_at, two_factor_secret, two_factor_recovery_codes,
-                  two_factor_confirmed_at, created_at, updated_at, deleted_at
+                  email_verified_at, two_factor_secret, two_factor_pending_secret, two_factor_recovery_codes,
+                  two_factor_confirmed_at, location_id, created_at, updated_at, deleted_at
     `
 
 	var a admin.Admin
 	err := r.db.QueryRowContext(ctx, query, args...).Scan(
 		&a.ID, &a.Email, &a.Password, &a.Name, &a.AvatarPath, &a.Role,
-		&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorRecoveryCodes,
-		&a.TwoFactorConfirmedAt, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+		&a.IsActive, &a.EmailVerifiedAt, &a.TwoFactorSecret, &a.TwoFactorPendingSecret, &a.TwoFactorRecoveryCodes,
+		&a.TwoFactorConfirmedAt, &a.LocationID, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
 	)
 
 	if err != nil {
@@ -232,6 +234,31 @@ func (r *AdminRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// UpdateLocation assigns the home location of an admin, or clears it when locationID is nil
+func (r *AdminRepository) UpdateLocation(ctx context.Context, id string, locationID *uuid.UUID) error {
+	query := `
+        UPDATE admins
+        SET location_id = $1, updated_at = NOW()
+        WHERE id = $2 AND deleted_at IS NULL
+    `
+
+	result, err := r.db.ExecContext(ctx, query, locationID, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 // UpdateAvatarPath updates admin avatar path
 func (r *AdminRepository) UpdateAvatarPath(ctx context.Context, id, avatarPath string) error {
 	query := `
@@ -243,3 +270,121 @@ func (r *AdminRepository) UpdateAvatarPath(ctx context.Context, id, avatarPath s
 	_, err := r.db.ExecContext(ctx, query, avatarPath, id)
 	return err
 }
+
+// UpdatePendingTwoFactorSecret stores a new two-factor secret waiting for confirmation.
+// A confirmed secret stays enforced until the pending one is confirmed.
+func (r *AdminRepository) UpdatePendingTwoFactorSecret(ctx context.Context, id, secret string) error {
+	query := `
+        UPDATE admins
+        SET two_factor_pending_secret = $1, updated_at = NOW()
+        WHERE id = $2 AND deleted_at IS NULL
+    `
+
+	_, err := r.db.ExecContext(ctx, query, secret, id)
+	return err
+}
+
+// ConfirmTwoFactor swaps the pending secret in as the admin two-factor secret and stores a new batch of recovery codes.
+// step is the time step of the code that confirmed it, which cannot be used again.
+// Returns sql.ErrNoRows when the pending secret was replaced concurrently.
+func (r *AdminRepository) ConfirmTwoFactor(ctx context.Context, id, pendingSecret, recoveryCodes string, step int64) error {
+	query := `
+        UPDATE admins
+        SET two_factor_secret = two_factor_pending_secret, two_factor_pending_secret = NULL,
+            two_factor_confirmed_at = NOW(), two_factor_recovery_codes = $1, two_factor_last_step = $4, updated_at = NOW()
+        WHERE id = $2 AND two_factor_pending_secret = $3 AND deleted_at IS NULL
+    `
+
+	result, err := r.db.ExecContext(ctx, query, recoveryCodes, id, pendingSecret, step)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DisableTwoFactor clears all two-factor data of an admin
+func (r *AdminRepository) DisableTwoFactor(ctx context.Context, id string) error {
+	query := `
+        UPDATE admins
+        SET two_factor_secret = NULL, two_factor_pending_secret = NULL, two_factor_recovery_codes = NULL,
+            two_factor_confirmed_at = NULL, two_factor_last_step = NULL, updated_at = NOW()
+        WHERE id = $1 AND deleted_at IS NULL
+    `
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// UseTwoFactorStep records the time step of an accepted two-factor code of the admin.
+// Returns sql.ErrNoRows when a code of the same or a later step was accepted before, so every code works once.
+func (r *AdminRepository) UseTwoFactorStep(ctx context.Context, id string, step int64) error {
+	query := `
+        UPDATE admins
+        SET two_factor_last_step = $1
+        WHERE id = $2 AND (two_factor_last_step IS NULL OR two_factor_last_step < $1) AND deleted_at IS NULL
+    `
+
+	result, err := r.db.ExecContext(ctx, query, step, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateRecoveryCodes replaces the admin recovery codes if they still match current.
+// Returns sql.ErrNoRows when the codes were changed concurrently (e.g. the same code used twice).
+func (r *AdminRepository) UpdateRecoveryCodes(ctx context.Context, id string, current *string, recoveryCodes string) error {
+	query := `
+        UPDATE admins
+        SET two_factor_recovery_codes = $1, updated_at = NOW()
+        WHERE id = $2 AND two_factor_recovery_codes IS NOT DISTINCT FROM $3 AND deleted_at IS NULL
+    `
+
+	result, err := r.db.ExecContext(ctx, query, recoveryCodes, id, current)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdatePassword updates admin password hash
+func (r *AdminRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	query := `
+        UPDATE admins
+        SET password = $1, updated_at = NOW()
+        WHERE id = $2 AND deleted_at IS NULL
+    `
+
+	_, err := r.db.ExecContext(ctx, query, passwordHash, id)
+	return err
+}
@@ -0,0 +1,138 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+)
+
+type APIKeyRepository struct {
+	db *database.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: database.Wrap(db),
+	}
+}
+
+const apiKeySelect = `
+        SELECT id, admin_id, name, prefix, key_hash, scopes, rate_limit, expires_at,
+               last_used_at, last_used_ip, revoked_at, created_at
+        FROM admin_api_keys
+    `
+
+// Create stores a new API key and fills in its ID and creation time
+func (r *APIKeyRepository) Create(ctx context.Context, k *admin.APIKey) error {
+	query := `
+        INSERT INTO admin_api_keys (id, admin_id, name, prefix, key_hash, scopes, rate_limit, expires_at, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, $7, NOW())
+        RETURNING id, created_at
+    `
+
+	return r.db.QueryRowContext(ctx, query,
+		k.AdminID, k.Name, k.Prefix, k.KeyHash, pq.Array(scopeNames(k.Scopes)), k.RateLimit, k.ExpiresAt,
+	).Scan(&k.ID, &k.CreatedAt)
+}
+
+// FindByHash retrieves an API key by the hash of the key
+func (r *APIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*admin.APIKey, error) {
+	query := apiKeySelect + `WHERE key_hash = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, keyHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+
+	return scanAPIKey(rows)
+}
+
+// FindByAdminID retrieves all API keys of an admin, newest first
+func (r *APIKeyRepository) FindByAdminID(ctx context.Context, adminID uuid.UUID) ([]*admin.APIKey, error) {
+	query := apiKeySelect + `WHERE admin_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*admin.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke revokes an API key of an admin
+func (r *APIKeyRepository) Revoke(ctx context.Context, id, adminID uuid.UUID) error {
+	query := `UPDATE admin_api_keys SET revoked_at = NOW() WHERE id = $1 AND admin_id = $2 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, adminID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateLastUsed records when and from where an API key was last used
+func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, ipAddress string) error {
+	query := `UPDATE admin_api_keys SET last_used_at = NOW(), last_used_ip = $2 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, ipAddress)
+	return err
+}
+
+// scanAPIKey scans a row selected with apiKeySelect
+func scanAPIKey(rows *sql.Rows) (*admin.APIKey, error) {
+	var k admin.APIKey
+	var scopes []string
+	if err := rows.Scan(
+		&k.ID, &k.AdminID, &k.Name, &k.Prefix, &k.KeyHash, pq.Array(&scopes), &k.RateLimit, &k.ExpiresAt,
+		&k.LastUsedAt, &k.LastUsedIP, &k.RevokedAt, &k.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	k.Scopes = make([]admin.Permission, len(scopes))
+	for i, scope := range scopes {
+		k.Scopes[i] = admin.Permission(scope)
+	}
+
+	return &k, nil
+}
+
+// scopeNames converts permissions to strings for an array parameter
+func scopeNames(scopes []admin.Permission) []string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+	return names
+}
@@ -0,0 +1,164 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+)
+
+type AuditLogRepository struct {
+	db *database.DB
+}
+
+func NewAuditLogRepository(db *sql.DB) *AuditLogRepository {
+	return &AuditLogRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Transaction runs fn in a transaction that the repository calls made with its context join,
+// so a change and its audit entry are saved together or not at all
+func (r *AuditLogRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.db.Transaction(ctx, fn)
+}
+
+// Create appends an entry to the audit log
+func (r *AuditLogRepository) Create(ctx context.Context, log *admin.AuditLog) error {
+	query := `
+        INSERT INTO audit_logs (id, actor_id, api_key_id, action, entity_type, entity_id, old_values, new_values, ip_address, user_agent, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+        RETURNING id, created_at
+    `
+
+	return r.db.QueryRowContext(ctx, query,
+		log.ActorID, log.APIKeyID, log.Action, log.EntityType, log.EntityID,
+		nullableJSON(log.OldValues), nullableJSON(log.NewValues), log.IPAddress, log.UserAgent,
+	).Scan(&log.ID, &log.CreatedAt)
+}
+
+// GetAll retrieves audit log entries matching the filter with pagination, newest first
+func (r *AuditLogRepository) GetAll(ctx context.Context, filter admin.AuditLogFilter, page, limit int) ([]*admin.AuditLog, int, error) {
+	offset := (page - 1) * limit
+
+	where, args := auditLogWhere(filter)
+	argCount := len(args) + 1
+
+	// Get total count
+	var total int
+	countQuery := `SELECT COUNT(*) FROM audit_logs WHERE ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// Get entries
+	query := auditLogSelect + where + fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argCount, argCount+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	logs := []*admin.AuditLog{}
+	for rows.Next() {
+		log, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, total, rows.Err()
+}
+
+// Each calls fn for every audit log entry matching the filter, oldest first,
+// without loading them all into memory
+func (r *AuditLogRepository) Each(ctx context.Context, filter admin.AuditLogFilter, fn func(*admin.AuditLog) error) error {
+	where, args := auditLogWhere(filter)
+
+	rows, err := r.db.QueryContext(ctx, auditLogSelect+where+" ORDER BY created_at, id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		log, err := scanAuditLog(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+const auditLogSelect = `
+        SELECT id, actor_id, api_key_id, action, entity_type, entity_id, old_values, new_values,
+               ip_address, COALESCE(user_agent, ''), created_at
+        FROM audit_logs
+        WHERE `
+
+// auditLogWhere builds the WHERE conditions and arguments for a filter
+func auditLogWhere(filter admin.AuditLogFilter) (string, []interface{}) {
+	where := "1 = 1"
+	args := []interface{}{}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		where += fmt.Sprintf(" AND "+condition, len(args))
+	}
+
+	if filter.ActorID != "" {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.EntityType != "" {
+		add("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		add("entity_id = $%d", filter.EntityID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	return where, args
+}
+
+// scanAuditLog scans a row selected with auditLogSelect
+func scanAuditLog(rows *sql.Rows) (*admin.AuditLog, error) {
+	var l admin.AuditLog
+	var oldValues, newValues []byte
+	err := rows.Scan(
+		&l.ID, &l.ActorID, &l.APIKeyID, &l.Action, &l.EntityType, &l.EntityID, &oldValues, &newValues,
+		&l.IPAddress, &l.UserAgent, &l.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	l.OldValues = oldValues
+	l.NewValues = newValues
+
+	return &l, nil
+}
+
+// nullableJSON stores empty JSON as NULL
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+)
+
+type PasskeyRepository struct {
+	db *database.DB
+}
+
+func NewPasskeyRepository(db *sql.DB) *PasskeyRepository {
+	return &PasskeyRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create stores a newly registered passkey and fills in its ID and creation time
+func (r *PasskeyRepository) Create(ctx context.Context, p *admin.Passkey) error {
+	query := `
+        INSERT INTO admin_passkeys (id, admin_id, name, credential_id, public_key, attestation_type, transports, aaguid,
+                                    sign_count, backup_eligible, backup_state, user_verified, attachment, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW())
+        RETURNING id, created_at
+    `
+
+	return r.db.QueryRowContext(ctx, query,
+		p.AdminID, p.Name, p.CredentialID, p.PublicKey, p.AttestationType, pq.Array(p.Transports), p.AAGUID,
+		int64(p.SignCount), p.BackupEligible, p.BackupState, p.UserVerified, p.Attachment,
+	).Scan(&p.ID, &p.CreatedAt)
+}
+
+// FindByAdminID retrieves all passkeys of an admin, oldest first
+func (r *PasskeyRepository) FindByAdminID(ctx context.Context, adminID uuid.UUID) ([]*admin.Passkey, error) {
+	query := `
+        SELECT id, admin_id, name, credential_id, public_key, attestation_type, transports, aaguid,
+               sign_count, backup_eligible, backup_state, user_verified, attachment, last_used_at, created_at
+        FROM admin_passkeys
+        WHERE admin_id = $1
+        ORDER BY created_at ASC
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	passkeys := []*admin.Passkey{}
+	for rows.Next() {
+		var p admin.Passkey
+		var signCount int64
+		if err := rows.Scan(
+			&p.ID, &p.AdminID, &p.Name, &p.CredentialID, &p.PublicKey, &p.AttestationType, pq.Array(&p.Transports), &p.AAGUID,
+			&signCount, &p.BackupEligible, &p.BackupState, &p.UserVerified, &p.Attachment, &p.LastUsedAt, &p.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		p.SignCount = uint32(signCount)
+		passkeys = append(passkeys, &p)
+	}
+
+	return passkeys, rows.Err()
+}
+
+// UpdateUsage stores the signature counter and backup state reported by a login and marks the passkey as used
+func (r *PasskeyRepository) UpdateUsage(ctx context.Context, adminID uuid.UUID, credentialID []byte, signCount uint32, backupState bool) error {
+	query := `
+        UPDATE admin_passkeys
+        SET sign_count = $3, backup_state = $4, last_used_at = NOW()
+        WHERE admin_id = $1 AND credential_id = $2
+    `
+
+	_, err := r.db.ExecContext(ctx, query, adminID, credentialID, int64(signCount), backupState)
+	return err
+}
+
+// Delete deletes a passkey of an admin
+func (r *PasskeyRepository) Delete(ctx context.Context, id, adminID uuid.UUID) error {
+	query := `DELETE FROM admin_passkeys WHERE id = $1 AND admin_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, adminID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
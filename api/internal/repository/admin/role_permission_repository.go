@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+)
+
+type RolePermissionRepository struct {
+	db *database.DB
+}
+
+func NewRolePermissionRepository(db *sql.DB) *RolePermissionRepository {
+	return &RolePermissionRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// HasPermission checks if a role has been granted a permission
+func (r *RolePermissionRepository) HasPermission(ctx context.Context, role admin.AdminRole, permission admin.Permission) (bool, error) {
+	query := `
+        SELECT EXISTS (
+            SELECT 1 FROM role_permissions
+            WHERE role = $1 AND permission = $2
+        )
+    `
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, role, permission).Scan(&exists)
+	return exists, err
+}
+
+// FindByRole retrieves the permissions granted to a role
+func (r *RolePermissionRepository) FindByRole(ctx context.Context, role admin.AdminRole) ([]admin.Permission, error) {
+	query := `
+        SELECT permission
+        FROM role_permissions
+        WHERE role = $1
+        ORDER BY permission
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []admin.Permission{}
+	for rows.Next() {
+		var p admin.Permission
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+
+	return permissions, rows.Err()
+}
+
+// Replace sets the permissions of a role, removing any not in the list
+func (r *RolePermissionRepository) Replace(ctx context.Context, role admin.AdminRole, permissions []admin.Permission) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	names := make([]string, len(permissions))
+	for i, p := range permissions {
+		names[i] = string(p)
+	}
+
+	// Remove permissions that are no longer granted
+	deleteQuery := `
+        DELETE FROM role_permissions
+        WHERE role = $1 AND NOT (permission = ANY($2))
+    `
+	if _, err := tx.ExecContext(ctx, deleteQuery, role, pq.Array(names)); err != nil {
+		return err
+	}
+
+	// Add new permissions, keeping existing grants untouched
+	insertQuery := `
+        INSERT INTO role_permissions (role, permission, created_at)
+        SELECT $1, UNNEST($2::varchar[]), NOW()
+        ON CONFLICT (role, permission) DO NOTHING
+    `
+	if _, err := tx.ExecContext(ctx, insertQuery, role, pq.Array(names)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
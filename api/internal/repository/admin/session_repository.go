@@ -3,19 +3,21 @@ package admin
 import (
 	"context"
 	"database/sql"
-	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
 	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
 )
 
 type SessionRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 func NewSessionRepository(db *sql.DB) *SessionRepository {
 	return &SessionRepository{
-		db: db,
+		db: database.Wrap(db),
 	}
 }
 
@@ -39,16 +41,18 @@ func (r *SessionRepository) Create(ctx context.Context, adminID uuid.UUID, token
 	return &s, nil
 }
 
-// FindByToken retrieves a session by token
-func (r *SessionRepository) FindByToken(ctx context.Context, token string) (*admin.Session, error) {
+// FindByToken retrieves a session by token hash, accepting any of the given hashes
+// (one per accepted hashing key)
+func (r *SessionRepository) FindByToken(ctx context.Context, hashes []string) (*admin.Session, error) {
 	query := `
         SELECT id, admin_id, token, ip_address, user_agent, last_activity_at, created_at
         FROM admin_sessions
-        WHERE token = $1
+        WHERE token = ANY($1)
+        LIMIT 1
     `
 
 	var s admin.Session
-	err := r.db.QueryRowContext(ctx, query, token).Scan(
+	err := r.db.QueryRowContext(ctx, query, pq.Array(hashes)).Scan(
 		&s.ID, &s.AdminID, &s.Token, &s.IPAddress, &s.UserAgent, &s.LastActivityAt, &s.CreatedAt,
 	)
 
@@ -60,22 +64,34 @@ func (r *SessionRepository) FindByToken(ctx context.Context, token string) (*adm
 }
 
 // UpdateLastActivity updates the last activity timestamp
-func (r *SessionRepository) UpdateLastActivity(ctx context.Context, token string) error {
+func (r *SessionRepository) UpdateLastActivity(ctx context.Context, id uuid.UUID) error {
 	query := `
         UPDATE admin_sessions
         SET last_activity_at = NOW()
-        WHERE token = $1
+        WHERE id = $1
     `
 
-	_, err := r.db.ExecContext(ctx, query, token)
+	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
-// Delete deletes a session by token
-func (r *SessionRepository) Delete(ctx context.Context, token string) error {
-	query := `DELETE FROM admin_sessions WHERE token = $1`
+// UpdateToken replaces the stored token hash, used to rehash sessions under a new key
+func (r *SessionRepository) UpdateToken(ctx context.Context, id uuid.UUID, token string) error {
+	query := `
+        UPDATE admin_sessions
+        SET token = $1
+        WHERE id = $2
+    `
+
+	_, err := r.db.ExecContext(ctx, query, token, id)
+	return err
+}
 
-	result, err := r.db.ExecContext(ctx, query, token)
+// Delete deletes a session by ID
+func (r *SessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM admin_sessions WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -99,13 +115,76 @@ func (r *SessionRepository) DeleteByAdminID(ctx context.Context, adminID uuid.UU
 	return err
 }
 
-// DeleteExpired deletes expired sessions
-func (r *SessionRepository) DeleteExpired(ctx context.Context, sessionLifetime time.Duration) error {
+// FindByAdminID retrieves all sessions of a admin, most recently active first
+func (r *SessionRepository) FindByAdminID(ctx context.Context, adminID uuid.UUID) ([]*admin.Session, error) {
+	query := `
+        SELECT id, admin_id, token, ip_address, user_agent, last_activity_at, created_at
+        FROM admin_sessions
+        WHERE admin_id = $1
+        ORDER BY last_activity_at DESC
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*admin.Session{}
+	for rows.Next() {
+		var s admin.Session
+		if err := rows.Scan(
+			&s.ID, &s.AdminID, &s.Token, &s.IPAddress, &s.UserAgent, &s.LastActivityAt, &s.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+
+	return sessions, rows.Err()
+}
+
+// DeleteByID deletes a session by ID, only if it belongs to the given admin
+func (r *SessionRepository) DeleteByID(ctx context.Context, id, adminID uuid.UUID) error {
+	query := `DELETE FROM admin_sessions WHERE id = $1 AND admin_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, adminID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteOthers deletes all sessions of a admin except the given one and returns how many were deleted
+func (r *SessionRepository) DeleteOthers(ctx context.Context, adminID, exceptID uuid.UUID) (int64, error) {
+	query := `DELETE FROM admin_sessions WHERE admin_id = $1 AND id <> $2`
+
+	result, err := r.db.ExecContext(ctx, query, adminID, exceptID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// DeleteExpired deletes sessions that are expired under the given policy
+func (r *SessionRepository) DeleteExpired(ctx context.Context, policy shared.SessionPolicy) error {
 	query := `
         DELETE FROM admin_sessions
-        WHERE created_at < NOW() - $1::interval
+        WHERE ($1 > 0 AND created_at < NOW() - $1 * INTERVAL '1 second')
+           OR ($2 > 0 AND last_activity_at < NOW() - $2 * INTERVAL '1 second')
     `
 
-	_, err := r.db.ExecContext(ctx, query, sessionLifetime)
+	_, err := r.db.ExecContext(ctx, query, int64(policy.Lifetime.Seconds()), int64(policy.IdleTimeout.Seconds()))
 	return err
 }
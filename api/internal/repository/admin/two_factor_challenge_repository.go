@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+)
+
+type TwoFactorChallengeRepository struct {
+	db *database.DB
+}
+
+func NewTwoFactorChallengeRepository(db *sql.DB) *TwoFactorChallengeRepository {
+	return &TwoFactorChallengeRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create creates a new two-factor challenge; webAuthnSession is set when it may be completed with a passkey
+func (r *TwoFactorChallengeRepository) Create(ctx context.Context, adminID uuid.UUID, token, ipAddress, userAgent string, webAuthnSession []byte, expiresAt time.Time) (*admin.TwoFactorChallenge, error) {
+	query := `
+        INSERT INTO admin_two_factor_challenges (id, admin_id, token, ip_address, user_agent, attempts, webauthn_session, expires_at, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, 0, $5, $6, NOW())
+        RETURNING id, admin_id, token, ip_address, user_agent, attempts, webauthn_session, expires_at, created_at
+    `
+
+	var c admin.TwoFactorChallenge
+	err := r.db.QueryRowContext(ctx, query, adminID, token, ipAddress, userAgent, nullableJSON(webAuthnSession), expiresAt).Scan(
+		&c.ID, &c.AdminID, &c.Token, &c.IPAddress, &c.UserAgent, &c.Attempts, &c.WebAuthnSession, &c.ExpiresAt, &c.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// FindByToken retrieves a challenge by token
+func (r *TwoFactorChallengeRepository) FindByToken(ctx context.Context, token string) (*admin.TwoFactorChallenge, error) {
+	query := `
+        SELECT id, admin_id, token, ip_address, user_agent, attempts, webauthn_session, expires_at, created_at
+        FROM admin_two_factor_challenges
+        WHERE token = $1
+    `
+
+	var c admin.TwoFactorChallenge
+	err := r.db.QueryRowContext(ctx, query, token).Scan(
+		&c.ID, &c.AdminID, &c.Token, &c.IPAddress, &c.UserAgent, &c.Attempts, &c.WebAuthnSession, &c.ExpiresAt, &c.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// IncrementAttempts records a failed code attempt
+func (r *TwoFactorChallengeRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	query := `
+        UPDATE admin_two_factor_challenges
+        SET attempts = attempts + 1
+        WHERE id = $1
+    `
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// Delete deletes a challenge by ID
+func (r *TwoFactorChallengeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM admin_two_factor_challenges WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteByAdminID deletes all challenges for an admin
+func (r *TwoFactorChallengeRepository) DeleteByAdminID(ctx context.Context, adminID uuid.UUID) error {
+	query := `DELETE FROM admin_two_factor_challenges WHERE admin_id = $1`
+	_, err := r.db.ExecContext(ctx, query, adminID)
+	return err
+}
+
+// DeleteExpired deletes expired challenges
+func (r *TwoFactorChallengeRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM admin_two_factor_challenges WHERE expires_at < NOW()`
+	_, err := r.db.ExecContext(ctx, query)
+	return err
+}
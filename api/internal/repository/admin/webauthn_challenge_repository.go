@@ -0,0 +1,89 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+)
+
+type WebAuthnChallengeRepository struct {
+	db *database.DB
+}
+
+func NewWebAuthnChallengeRepository(db *sql.DB) *WebAuthnChallengeRepository {
+	return &WebAuthnChallengeRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create creates a new WebAuthn challenge; adminID is nil for passwordless logins
+func (r *WebAuthnChallengeRepository) Create(ctx context.Context, adminID *uuid.UUID, purpose admin.WebAuthnPurpose, token string, session []byte, ipAddress, userAgent string, expiresAt time.Time) (*admin.WebAuthnChallenge, error) {
+	query := `
+        INSERT INTO admin_webauthn_challenges (id, admin_id, purpose, token, session, ip_address, user_agent, expires_at, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, $7, NOW())
+        RETURNING id, admin_id, purpose, token, session, ip_address, COALESCE(user_agent, ''), expires_at, created_at
+    `
+
+	var c admin.WebAuthnChallenge
+	err := r.db.QueryRowContext(ctx, query, adminID, purpose, token, string(session), ipAddress, userAgent, expiresAt).Scan(
+		&c.ID, &c.AdminID, &c.Purpose, &c.Token, &c.Session, &c.IPAddress, &c.UserAgent, &c.ExpiresAt, &c.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// FindByToken retrieves a challenge for the given purpose by token
+func (r *WebAuthnChallengeRepository) FindByToken(ctx context.Context, token string, purpose admin.WebAuthnPurpose) (*admin.WebAuthnChallenge, error) {
+	query := `
+        SELECT id, admin_id, purpose, token, session, ip_address, COALESCE(user_agent, ''), expires_at, created_at
+        FROM admin_webauthn_challenges
+        WHERE token = $1 AND purpose = $2
+    `
+
+	var c admin.WebAuthnChallenge
+	err := r.db.QueryRowContext(ctx, query, token, purpose).Scan(
+		&c.ID, &c.AdminID, &c.Purpose, &c.Token, &c.Session, &c.IPAddress, &c.UserAgent, &c.ExpiresAt, &c.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Delete deletes a challenge by ID
+func (r *WebAuthnChallengeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM admin_webauthn_challenges WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes expired challenges
+func (r *WebAuthnChallengeRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM admin_webauthn_challenges WHERE expires_at < NOW()`
+	_, err := r.db.ExecContext(ctx, query)
+	return err
+}
@@ -0,0 +1,256 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+type CategoryRepository struct {
+	db *database.DB
+}
+
+func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+	return &CategoryRepository{
+		db: database.Wrap(db),
+	}
+}
+
+const categorySelect = `
+        SELECT id, parent_id, name, slug, description, path, depth, position, created_at, updated_at
+        FROM categories
+    `
+
+// GetAll retrieves all categories, parents before children and siblings in order
+func (r *CategoryRepository) GetAll(ctx context.Context) ([]*catalog.Category, error) {
+	rows, err := r.db.QueryContext(ctx, categorySelect+`ORDER BY depth, position, name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []*catalog.Category{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, rows.Err()
+}
+
+// FindByID retrieves a category by ID
+func (r *CategoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*catalog.Category, error) {
+	return r.findOne(ctx, categorySelect+`WHERE id = $1`, id)
+}
+
+// FindBySlug retrieves a category by slug
+func (r *CategoryRepository) FindBySlug(ctx context.Context, slug string) (*catalog.Category, error) {
+	return r.findOne(ctx, categorySelect+`WHERE slug = $1`, slug)
+}
+
+// CountByIDs counts how many of the given categories exist
+func (r *CategoryRepository) CountByIDs(ctx context.Context, ids []uuid.UUID) (int, error) {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id.String()
+	}
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM categories WHERE id = ANY($1::uuid[])`, pq.Array(names)).Scan(&count)
+	return count, err
+}
+
+// Create stores a new category as the last child of its parent and fills in the generated fields
+func (r *CategoryRepository) Create(ctx context.Context, c *catalog.Category, parentPath string) error {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+
+	query := `
+        WITH new AS (SELECT gen_uuid_v7() AS id)
+        INSERT INTO categories (id, parent_id, name, slug, description, path, depth, position, created_at, updated_at)
+        SELECT new.id, $1, $2, $3, $4, $5 || new.id || '/', $6,
+               (SELECT COALESCE(MAX(position) + 1, 0) FROM categories WHERE parent_id IS NOT DISTINCT FROM $1),
+               NOW(), NOW()
+        FROM new
+        RETURNING id, path, position, created_at, updated_at
+    `
+
+	return r.db.QueryRowContext(ctx, query, c.ParentID, c.Name, c.Slug, c.Description, parentPath, c.Depth).Scan(
+		&c.ID, &c.Path, &c.Position, &c.CreatedAt, &c.UpdatedAt,
+	)
+}
+
+// Update saves the name, slug and description of a category
+func (r *CategoryRepository) Update(ctx context.Context, c *catalog.Category) error {
+	query := `
+        UPDATE categories
+        SET name = $1, slug = $2, description = $3, updated_at = NOW()
+        WHERE id = $4
+        RETURNING updated_at
+    `
+
+	return r.db.QueryRowContext(ctx, query, c.Name, c.Slug, c.Description, c.ID).Scan(&c.UpdatedAt)
+}
+
+// Move moves a category with its subtree under a new parent (nil for the root) at the given
+// position among its new siblings; the other siblings shift to make room.
+// Returns sql.ErrNoRows when the category or the parent was moved or deleted concurrently.
+func (r *CategoryRepository) Move(ctx context.Context, c *catalog.Category, parent *catalog.Category, position int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize changes of the tree so the paths checked by the caller stay valid
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	checkQuery := `SELECT COUNT(*) FROM categories WHERE (id = $1 AND path = $2) OR (id = $3 AND path = $4)`
+	var parentRef, parentCheckPath interface{}
+	expected := 1
+	if parent != nil {
+		parentRef, parentCheckPath, expected = parent.ID, parent.Path, 2
+	}
+	var found int
+	if err := tx.QueryRowContext(ctx, checkQuery, c.ID, c.Path, parentRef, parentCheckPath).Scan(&found); err != nil {
+		return err
+	}
+	if found != expected {
+		return sql.ErrNoRows
+	}
+
+	var parentID *uuid.UUID
+	parentPath, depth := "", 0
+	if parent != nil {
+		parentID, parentPath, depth = &parent.ID, parent.Path, parent.Depth+1
+	}
+	newPath := catalog.CategoryPath(parentPath, c.ID)
+
+	// Rewrite the paths and depths of the subtree
+	subtreeQuery := `
+        UPDATE categories
+        SET path = $1 || SUBSTRING(path FROM LENGTH($2) + 1), depth = depth + $3, updated_at = NOW()
+        WHERE path LIKE $4
+    `
+	if _, err := tx.ExecContext(ctx, subtreeQuery, newPath, c.Path, depth-c.Depth, escapeLike(c.Path)+"%"); err != nil {
+		return err
+	}
+
+	// Renumber the new siblings with the category inserted at its position
+	siblingsQuery := `
+        SELECT id FROM categories
+        WHERE parent_id IS NOT DISTINCT FROM $1 AND id <> $2
+        ORDER BY position, name
+        FOR UPDATE
+    `
+	rows, err := tx.QueryContext(ctx, siblingsQuery, parentID, c.ID)
+	if err != nil {
+		return err
+	}
+	siblings := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		siblings = append(siblings, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	position = min(max(position, 0), len(siblings))
+	ordered := append(append(append([]uuid.UUID{}, siblings[:position]...), c.ID), siblings[position:]...)
+
+	for i, id := range ordered {
+		if id == c.ID {
+			if _, err := tx.ExecContext(ctx, `UPDATE categories SET parent_id = $1, position = $2 WHERE id = $3`, parentID, i, id); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE categories SET position = $1 WHERE id = $2 AND position <> $1`, i, id); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	c.ParentID, c.Path, c.Depth, c.Position = parentID, newPath, depth, position
+	return nil
+}
+
+// Delete deletes a category without subcategories; its products are unassigned from it
+func (r *CategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+        DELETE FROM categories
+        WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM categories child WHERE child.parent_id = $1)
+    `
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// HasChildren checks if a category has subcategories
+func (r *CategoryRepository) HasChildren(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM categories WHERE parent_id = $1)`, id).Scan(&exists)
+	return exists, err
+}
+
+// findOne retrieves a single category selected with categorySelect
+func (r *CategoryRepository) findOne(ctx context.Context, query string, arg interface{}) (*catalog.Category, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+
+	return scanCategory(rows)
+}
+
+// scanCategory scans a row selected with categorySelect
+func scanCategory(rows *sql.Rows) (*catalog.Category, error) {
+	var c catalog.Category
+	if err := rows.Scan(
+		&c.ID, &c.ParentID, &c.Name, &c.Slug, &c.Description, &c.Path, &c.Depth, &c.Position, &c.CreatedAt, &c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,216 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+type CollectionRepository struct {
+	db *database.DB
+}
+
+func NewCollectionRepository(db *sql.DB) *CollectionRepository {
+	return &CollectionRepository{
+		db: database.Wrap(db),
+	}
+}
+
+const collectionSelect = `
+        SELECT id, name, slug, description, type, match, rules, created_at, updated_at
+        FROM collections
+    `
+
+// GetAll retrieves all collections by name
+func (r *CollectionRepository) GetAll(ctx context.Context) ([]*catalog.Collection, error) {
+	rows, err := r.db.QueryContext(ctx, collectionSelect+`ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := []*catalog.Collection{}
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, c)
+	}
+
+	return collections, rows.Err()
+}
+
+// FindByID retrieves a collection by ID, with the products of a manual collection
+func (r *CollectionRepository) FindByID(ctx context.Context, id uuid.UUID) (*catalog.Collection, error) {
+	return r.findOne(ctx, collectionSelect+`WHERE id = $1`, id)
+}
+
+// FindBySlug retrieves a collection by slug, with the products of a manual collection
+func (r *CollectionRepository) FindBySlug(ctx context.Context, slug string) (*catalog.Collection, error) {
+	return r.findOne(ctx, collectionSelect+`WHERE slug = $1`, slug)
+}
+
+// Create stores a new collection and fills in its ID and timestamps
+func (r *CollectionRepository) Create(ctx context.Context, c *catalog.Collection) error {
+	rules, err := json.Marshal(c.Rules)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO collections (id, name, slug, description, type, match, rules, created_at, updated_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+
+	return r.db.QueryRowContext(ctx, query, c.Name, c.Slug, c.Description, c.Type, c.Match, rules).Scan(
+		&c.ID, &c.CreatedAt, &c.UpdatedAt,
+	)
+}
+
+// Update saves the name, slug, description and rules of a collection
+func (r *CollectionRepository) Update(ctx context.Context, c *catalog.Collection) error {
+	rules, err := json.Marshal(c.Rules)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        UPDATE collections
+        SET name = $1, slug = $2, description = $3, match = $4, rules = $5, updated_at = NOW()
+        WHERE id = $6
+        RETURNING updated_at
+    `
+
+	return r.db.QueryRowContext(ctx, query, c.Name, c.Slug, c.Description, c.Match, rules, c.ID).Scan(&c.UpdatedAt)
+}
+
+// SetProducts replaces the products of a manual collection, in the given order
+func (r *CollectionRepository) SetProducts(ctx context.Context, id uuid.UUID, productIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM collection_products WHERE collection_id = $1`, id); err != nil {
+		return err
+	}
+
+	ids := make([]string, len(productIDs))
+	for i, productID := range productIDs {
+		ids[i] = productID.String()
+	}
+
+	// Positions follow the order of the array
+	insertQuery := `
+        INSERT INTO collection_products (collection_id, product_id, position)
+        SELECT $1, product_id, ordinality - 1
+        FROM UNNEST($2::uuid[]) WITH ORDINALITY AS t(product_id, ordinality)
+    `
+	if _, err := tx.ExecContext(ctx, insertQuery, id, pq.Array(ids)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete deletes a collection
+func (r *CollectionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM collections WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// findOne retrieves a single collection selected with collectionSelect
+func (r *CollectionRepository) findOne(ctx context.Context, query string, arg interface{}) (*catalog.Collection, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+
+	c, err := scanCollection(rows)
+	if err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if !c.IsAutomated() {
+		if err := r.loadProductIDs(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// loadProductIDs fills in the products of a manual collection, in order
+func (r *CollectionRepository) loadProductIDs(ctx context.Context, c *catalog.Collection) error {
+	query := `
+        SELECT cp.product_id
+        FROM collection_products cp
+        JOIN products p ON p.id = cp.product_id AND p.deleted_at IS NULL
+        WHERE cp.collection_id = $1
+        ORDER BY cp.position
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, c.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	c.ProductIDs = []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		c.ProductIDs = append(c.ProductIDs, id)
+	}
+
+	return rows.Err()
+}
+
+// scanCollection scans a row selected with collectionSelect
+func scanCollection(rows *sql.Rows) (*catalog.Collection, error) {
+	var c catalog.Collection
+	var rules []byte
+	if err := rows.Scan(
+		&c.ID, &c.Name, &c.Slug, &c.Description, &c.Type, &c.Match, &rules, &c.CreatedAt, &c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(rules, &c.Rules); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,409 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+type InventoryRepository struct {
+	db *database.DB
+}
+
+func NewInventoryRepository(db *sql.DB) *InventoryRepository {
+	return &InventoryRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Stock of the variant aliased v at the location given by an SQL expression, computed from the
+// ledger, the active reservations and the transfers in transit
+func onHandAt(location string) string {
+	return `(SELECT COALESCE(SUM(m.quantity), 0) FROM stock_movements m WHERE m.variant_id = v.id AND m.location_id = ` + location + `)`
+}
+
+func reservedAt(location string) string {
+	return `(
+            SELECT COALESCE(SUM(ri.quantity), 0)
+            FROM stock_reservation_items ri
+            JOIN stock_reservations sr ON sr.id = ri.reservation_id
+            WHERE ri.variant_id = v.id AND sr.location_id = ` + location + ` AND sr.status = 'active' AND sr.expires_at > NOW()
+        )`
+}
+
+func inTransitTo(location string) string {
+	return `(
+            SELECT COALESCE(SUM(ti.quantity), 0)
+            FROM transfer_order_items ti
+            JOIN transfer_orders t ON t.id = ti.transfer_id
+            WHERE ti.variant_id = v.id AND t.to_location_id = ` + location + ` AND t.status = 'in_transit'
+        )`
+}
+
+// onlineLocationSQL is the location fulfilling online orders, NULL when there is none
+const onlineLocationSQL = `(SELECT id FROM locations WHERE fulfills_online)`
+
+const movementSelect = `
+        SELECT id, variant_id, location_id, sku, type, quantity, reason, reference, actor_id, created_at
+        FROM stock_movements
+    `
+
+const reservationSelect = `
+        SELECT id, customer_id, location_id, status, expires_at, created_at, updated_at
+        FROM stock_reservations
+    `
+
+// Level computes the stock of a variant at a location
+func (r *InventoryRepository) Level(ctx context.Context, variantID, locationID uuid.UUID) (*catalog.StockLevel, error) {
+	query := `
+        SELECT v.id, v.sku, ` + onHandAt("$2::uuid") + `, ` + reservedAt("$2::uuid") + `, ` + inTransitTo("$2::uuid") + `
+        FROM product_variants v
+        WHERE v.id = $1
+    `
+
+	level := &catalog.StockLevel{LocationID: locationID}
+	if err := r.db.QueryRowContext(ctx, query, variantID, locationID).Scan(
+		&level.VariantID, &level.SKU, &level.OnHand, &level.Reserved, &level.InTransit,
+	); err != nil {
+		return nil, err
+	}
+	level.Available = level.OnHand - level.Reserved
+
+	return level, nil
+}
+
+// HasVariant checks if a variant exists
+func (r *InventoryRepository) HasVariant(ctx context.Context, variantID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM product_variants WHERE id = $1)`, variantID).Scan(&exists)
+	return exists, err
+}
+
+// Levels computes the stock of a variant at every location, active locations first.
+// Returns sql.ErrNoRows when the variant does not exist.
+func (r *InventoryRepository) Levels(ctx context.Context, variantID uuid.UUID) ([]*catalog.StockLevel, error) {
+	exists, err := r.HasVariant(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	query := `
+        SELECT v.id, l.id, v.sku, ` + onHandAt("l.id") + `, ` + reservedAt("l.id") + `, ` + inTransitTo("l.id") + `
+        FROM product_variants v
+        CROSS JOIN locations l
+        WHERE v.id = $1
+        ORDER BY l.is_active DESC, l.name
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, variantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	levels := []*catalog.StockLevel{}
+	for rows.Next() {
+		level := &catalog.StockLevel{}
+		if err := rows.Scan(
+			&level.VariantID, &level.LocationID, &level.SKU, &level.OnHand, &level.Reserved, &level.InTransit,
+		); err != nil {
+			return nil, err
+		}
+		level.Available = level.OnHand - level.Reserved
+		levels = append(levels, level)
+	}
+
+	return levels, rows.Err()
+}
+
+// GetMovements retrieves the ledger of a variant with pagination, newest first, optionally only
+// the movements at one location
+func (r *InventoryRepository) GetMovements(ctx context.Context, variantID uuid.UUID, locationID *uuid.UUID, page, limit int) ([]*catalog.StockMovement, int, error) {
+	offset := (page - 1) * limit
+
+	where := ` WHERE variant_id = $1`
+	args := []interface{}{variantID}
+	if locationID != nil {
+		where += ` AND location_id = $2`
+		args = append(args, *locationID)
+	}
+
+	// Get total count
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM stock_movements`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := movementSelect + where + fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := []*catalog.StockMovement{}
+	for rows.Next() {
+		m := &catalog.StockMovement{}
+		if err := rows.Scan(
+			&m.ID, &m.VariantID, &m.LocationID, &m.SKU, &m.Type, &m.Quantity, &m.Reason, &m.Reference, &m.ActorID, &m.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, m)
+	}
+
+	return movements, total, rows.Err()
+}
+
+// Record appends a movement to the ledger and fills in its ID, SKU and creation time. The variant
+// is locked first, so concurrent movements of it are recorded one after another and the check
+// sees the stock left by the previous one. A movement taking stock away must not take more than
+// is available at its location, or when reserved stock may be used, more than is on hand there.
+// Returns sql.ErrNoRows when the variant does not exist or the stock does not suffice.
+func (r *InventoryRepository) Record(ctx context.Context, m *catalog.StockMovement, useReserved bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockVariants(ctx, tx, []uuid.UUID{m.VariantID}); err != nil {
+		return err
+	}
+
+	if err := insertMovement(ctx, tx, m, useReserved); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CountSellable counts how many of the given variants are enabled and belong to an active product
+func (r *InventoryRepository) CountSellable(ctx context.Context, variantIDs []uuid.UUID) (int, error) {
+	query := `
+        SELECT COUNT(*)
+        FROM product_variants v
+        JOIN products p ON p.id = v.product_id
+        WHERE v.id = ANY($1::uuid[]) AND v.is_enabled AND p.status = 'active' AND p.deleted_at IS NULL
+    `
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, pq.Array(uuidStrings(variantIDs))).Scan(&count)
+	return count, err
+}
+
+// Reserve stores a reservation holding its items at its location and fills in its ID and
+// timestamps. Other active reservations of the customer are released first. Every item must be
+// available there after the reservations released; otherwise nothing is reserved and
+// sql.ErrNoRows is returned.
+func (r *InventoryRepository) Reserve(ctx context.Context, res *catalog.Reservation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Reservations are released before variants are locked
+	if res.CustomerID != nil {
+		releaseQuery := `
+            UPDATE stock_reservations
+            SET status = 'released', updated_at = NOW()
+            WHERE customer_id = $1 AND status = 'active'
+        `
+		if _, err := tx.ExecContext(ctx, releaseQuery, res.CustomerID); err != nil {
+			return err
+		}
+	}
+
+	variantIDs := make([]uuid.UUID, len(res.Items))
+	for i, item := range res.Items {
+		variantIDs[i] = item.VariantID
+	}
+	if err := lockVariants(ctx, tx, variantIDs); err != nil {
+		return err
+	}
+
+	insertQuery := `
+        INSERT INTO stock_reservations (id, customer_id, location_id, status, expires_at, created_at, updated_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	if err := tx.QueryRowContext(ctx, insertQuery, res.CustomerID, res.LocationID, res.Status, res.ExpiresAt).Scan(
+		&res.ID, &res.CreatedAt, &res.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	// Each item is only inserted when the variant has enough stock available
+	itemQuery := `
+        INSERT INTO stock_reservation_items (reservation_id, variant_id, quantity)
+        SELECT $1, v.id, $3::integer
+        FROM product_variants v
+        WHERE v.id = $2 AND ` + onHandAt("$4::uuid") + ` - ` + reservedAt("$4::uuid") + ` >= $3::integer
+    `
+	for _, item := range res.Items {
+		result, err := tx.ExecContext(ctx, itemQuery, res.ID, item.VariantID, item.Quantity, res.LocationID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindReservation retrieves a reservation by ID with its items
+func (r *InventoryRepository) FindReservation(ctx context.Context, id uuid.UUID) (*catalog.Reservation, error) {
+	res := &catalog.Reservation{}
+	if err := r.db.QueryRowContext(ctx, reservationSelect+`WHERE id = $1`, id).Scan(
+		&res.ID, &res.CustomerID, &res.LocationID, &res.Status, &res.ExpiresAt, &res.CreatedAt, &res.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	items, err := loadReservationItems(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	res.Items = items
+
+	return res, nil
+}
+
+// ReleaseReservation releases an active reservation so its stock is available again.
+// Returns sql.ErrNoRows when the reservation is not active.
+func (r *InventoryRepository) ReleaseReservation(ctx context.Context, res *catalog.Reservation) error {
+	query := `
+        UPDATE stock_reservations
+        SET status = 'released', updated_at = NOW()
+        WHERE id = $1 AND status = 'active'
+        RETURNING status, updated_at
+    `
+
+	return r.db.QueryRowContext(ctx, query, res.ID).Scan(&res.Status, &res.UpdatedAt)
+}
+
+// variantsHaveStock checks if any variant of the product other than the kept ones has stock on
+// hand at some location, reserved or in transit
+func variantsHaveStock(ctx context.Context, tx *database.Tx, productID uuid.UUID, keep []string) (bool, error) {
+	query := `
+        SELECT EXISTS (
+            SELECT 1 FROM product_variants v
+            WHERE v.product_id = $1 AND NOT (v.id = ANY($2::uuid[]))
+              AND (
+                  EXISTS (SELECT 1 FROM stock_movements m WHERE m.variant_id = v.id GROUP BY m.location_id HAVING SUM(m.quantity) <> 0)
+                  OR EXISTS (
+                      SELECT 1 FROM stock_reservation_items ri
+                      JOIN stock_reservations sr ON sr.id = ri.reservation_id
+                      WHERE ri.variant_id = v.id AND sr.status = 'active' AND sr.expires_at > NOW()
+                  )
+                  OR EXISTS (
+                      SELECT 1 FROM transfer_order_items ti
+                      JOIN transfer_orders t ON t.id = ti.transfer_id
+                      WHERE ti.variant_id = v.id AND t.status = 'in_transit'
+                  )
+              )
+        )
+    `
+
+	var exists bool
+	err := tx.QueryRowContext(ctx, query, productID, pq.Array(keep)).Scan(&exists)
+	return exists, err
+}
+
+// lockVariants locks the rows of the given variants in ID order, so transactions locking several
+// variants cannot deadlock. Returns sql.ErrNoRows when a variant does not exist.
+func lockVariants(ctx context.Context, tx *database.Tx, variantIDs []uuid.UUID) error {
+	ids := uuidStrings(variantIDs)
+	sort.Strings(ids)
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM product_variants WHERE id = ANY($1::uuid[]) ORDER BY id FOR UPDATE`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if locked != len(ids) {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// insertMovement inserts a movement of a locked variant when the stock at its location suffices, see Record
+func insertMovement(ctx context.Context, tx *database.Tx, m *catalog.StockMovement, useReserved bool) error {
+	remaining := onHandAt("$7::uuid") + ` - ` + reservedAt("$7::uuid")
+	if useReserved {
+		remaining = onHandAt("$7::uuid")
+	}
+
+	query := `
+        INSERT INTO stock_movements (id, variant_id, location_id, sku, type, quantity, reason, reference, actor_id, created_at)
+        SELECT gen_uuid_v7(), v.id, $7::uuid, v.sku, $2, $3::integer, $4, $5, $6, NOW()
+        FROM product_variants v
+        WHERE v.id = $1 AND ($3::integer > 0 OR ` + remaining + ` + $3::integer >= 0)
+        RETURNING id, sku, created_at
+    `
+
+	return tx.QueryRowContext(ctx, query, m.VariantID, m.Type, m.Quantity, m.Reason, m.Reference, m.ActorID, m.LocationID).Scan(
+		&m.ID, &m.SKU, &m.CreatedAt,
+	)
+}
+
+// queryer is implemented by both *database.DB and *database.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// loadReservationItems retrieves the items of a reservation
+func loadReservationItems(ctx context.Context, q queryer, reservationID uuid.UUID) ([]catalog.ReservationItem, error) {
+	rows, err := q.QueryContext(ctx, `SELECT variant_id, quantity FROM stock_reservation_items WHERE reservation_id = $1 ORDER BY variant_id`, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []catalog.ReservationItem{}
+	for rows.Next() {
+		var item catalog.ReservationItem
+		if err := rows.Scan(&item.VariantID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// uuidStrings formats IDs for pq.Array
+func uuidStrings(ids []uuid.UUID) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = id.String()
+	}
+	return result
+}
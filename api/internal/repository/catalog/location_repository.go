@@ -0,0 +1,125 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+type LocationRepository struct {
+	db *database.DB
+}
+
+func NewLocationRepository(db *sql.DB) *LocationRepository {
+	return &LocationRepository{
+		db: database.Wrap(db),
+	}
+}
+
+const locationSelect = `
+        SELECT id, name, code, type, address, fulfills_online, is_active, created_at, updated_at
+        FROM locations
+    `
+
+// GetAll retrieves all locations, active ones first
+func (r *LocationRepository) GetAll(ctx context.Context) ([]*catalog.Location, error) {
+	rows, err := r.db.QueryContext(ctx, locationSelect+`ORDER BY is_active DESC, name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []*catalog.Location{}
+	for rows.Next() {
+		l := &catalog.Location{}
+		if err := rows.Scan(
+			&l.ID, &l.Name, &l.Code, &l.Type, &l.Address, &l.FulfillsOnline, &l.IsActive, &l.CreatedAt, &l.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		locations = append(locations, l)
+	}
+
+	return locations, rows.Err()
+}
+
+// FindByID retrieves a location by ID
+func (r *LocationRepository) FindByID(ctx context.Context, id uuid.UUID) (*catalog.Location, error) {
+	return r.findOne(ctx, locationSelect+`WHERE id = $1`, id)
+}
+
+// FindOnline retrieves the location fulfilling online orders
+func (r *LocationRepository) FindOnline(ctx context.Context) (*catalog.Location, error) {
+	return r.findOne(ctx, locationSelect+`WHERE fulfills_online`)
+}
+
+// Create stores a new location and fills in the generated fields. When it fulfils online
+// orders, the location doing so before stops.
+func (r *LocationRepository) Create(ctx context.Context, l *catalog.Location) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if l.FulfillsOnline {
+		if _, err := tx.ExecContext(ctx, `UPDATE locations SET fulfills_online = false, updated_at = NOW() WHERE fulfills_online`); err != nil {
+			return err
+		}
+	}
+
+	query := `
+        INSERT INTO locations (id, name, code, type, address, fulfills_online, is_active, created_at, updated_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	if err := tx.QueryRowContext(ctx, query, l.Name, l.Code, l.Type, l.Address, l.FulfillsOnline, l.IsActive).Scan(
+		&l.ID, &l.CreatedAt, &l.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update saves the fields of a location. When it fulfils online orders, the location doing so
+// before stops. Returns sql.ErrNoRows when the location does not exist.
+func (r *LocationRepository) Update(ctx context.Context, l *catalog.Location) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if l.FulfillsOnline {
+		if _, err := tx.ExecContext(ctx, `UPDATE locations SET fulfills_online = false, updated_at = NOW() WHERE fulfills_online AND id <> $1`, l.ID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+        UPDATE locations
+        SET name = $1, code = $2, type = $3, address = $4, fulfills_online = $5, is_active = $6, updated_at = NOW()
+        WHERE id = $7
+        RETURNING updated_at
+    `
+	if err := tx.QueryRowContext(ctx, query, l.Name, l.Code, l.Type, l.Address, l.FulfillsOnline, l.IsActive, l.ID).Scan(&l.UpdatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *LocationRepository) findOne(ctx context.Context, query string, args ...interface{}) (*catalog.Location, error) {
+	l := &catalog.Location{}
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&l.ID, &l.Name, &l.Code, &l.Type, &l.Address, &l.FulfillsOnline, &l.IsActive, &l.CreatedAt, &l.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
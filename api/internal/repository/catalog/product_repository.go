@@ -0,0 +1,653 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+type ProductRepository struct {
+	db *database.DB
+}
+
+func NewProductRepository(db *sql.DB) *ProductRepository {
+	return &ProductRepository{
+		db: database.Wrap(db),
+	}
+}
+
+const productSelect = `
+        SELECT id, name, slug, description, status, tags, created_at, updated_at, deleted_at
+        FROM products
+    `
+
+// GetAll retrieves products with pagination and filtering, newest first
+func (r *ProductRepository) GetAll(ctx context.Context, page, limit int, filter catalog.ProductFilter) ([]*catalog.Product, int, error) {
+	offset := (page - 1) * limit
+
+	// Build query with filters
+	where := ` WHERE deleted_at IS NULL`
+	args := []interface{}{}
+	argCount := 1
+
+	// Add search filter
+	if filter.Search != "" {
+		where += fmt.Sprintf(" AND (name ILIKE $%d OR slug ILIKE $%d)", argCount, argCount)
+		args = append(args, "%"+filter.Search+"%")
+		argCount++
+	}
+
+	// Add status filter
+	if filter.Status != "" {
+		where += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, filter.Status)
+		argCount++
+	}
+
+	// Add tag filter
+	if filter.Tag != "" {
+		where += fmt.Sprintf(" AND $%d = ANY(tags)", argCount)
+		args = append(args, filter.Tag)
+		argCount++
+	}
+
+	// Add category filter, including the descendants of the category
+	if filter.CategoryPath != "" {
+		where += fmt.Sprintf(` AND EXISTS (
+            SELECT 1 FROM product_categories pc
+            JOIN categories c ON c.id = pc.category_id
+            WHERE pc.product_id = products.id AND c.path LIKE $%d
+        )`, argCount)
+		args = append(args, escapeLike(filter.CategoryPath)+"%")
+		argCount++
+	}
+
+	// Add collection filter
+	if filter.Collection != nil {
+		var condition string
+		condition, args = collectionCondition(filter.Collection, args)
+		where += " AND " + condition
+		argCount = len(args) + 1
+	}
+
+	// Get total count
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// Add pagination
+	query := productSelect + where + fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argCount, argCount+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	products := []*catalog.Product{}
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadDetails(ctx, products); err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+// FindByID retrieves a product by ID, with its images, options and variants
+func (r *ProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*catalog.Product, error) {
+	return r.findOne(ctx, productSelect+`WHERE id = $1 AND deleted_at IS NULL`, id)
+}
+
+// FindBySlug retrieves a product by slug, with its images, options and variants
+func (r *ProductRepository) FindBySlug(ctx context.Context, slug string) (*catalog.Product, error) {
+	return r.findOne(ctx, productSelect+`WHERE slug = $1 AND deleted_at IS NULL`, slug)
+}
+
+// CountByIDs counts how many of the given products exist
+func (r *ProductRepository) CountByIDs(ctx context.Context, ids []uuid.UUID) (int, error) {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id.String()
+	}
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL`, pq.Array(names)).Scan(&count)
+	return count, err
+}
+
+// Create stores a new product with its images, options and variants and fills in the generated IDs and timestamps
+func (r *ProductRepository) Create(ctx context.Context, p *catalog.Product) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO products (id, name, slug, description, status, tags, created_at, updated_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	if err := tx.QueryRowContext(ctx, query, p.Name, p.Slug, p.Description, p.Status, pq.Array(p.Tags)).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return err
+	}
+
+	if err := insertImages(ctx, tx, p); err != nil {
+		return err
+	}
+
+	if err := insertOptions(ctx, tx, p); err != nil {
+		return err
+	}
+
+	if err := saveVariants(ctx, tx, p); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update saves the fields of a product and replaces its images
+func (r *ProductRepository) Update(ctx context.Context, p *catalog.Product) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE products
+        SET name = $1, slug = $2, description = $3, status = $4, tags = $5, updated_at = NOW()
+        WHERE id = $6 AND deleted_at IS NULL
+        RETURNING updated_at
+    `
+	if err := tx.QueryRowContext(ctx, query, p.Name, p.Slug, p.Description, p.Status, pq.Array(p.Tags), p.ID).Scan(&p.UpdatedAt); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_images WHERE product_id = $1`, p.ID); err != nil {
+		return err
+	}
+
+	if err := insertImages(ctx, tx, p); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SaveOptions replaces the options of a product and saves its variant matrix: variants that are no
+// longer part of it are removed and variants without an ID are created. Returns sql.ErrNoRows when
+// the product does not exist or a variant to remove has stock on hand or reserved.
+func (r *ProductRepository) SaveOptions(ctx context.Context, p *catalog.Product) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the product so concurrent changes of the matrix do not interleave
+	var id uuid.UUID
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, p.ID).Scan(&id); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_options WHERE product_id = $1`, p.ID); err != nil {
+		return err
+	}
+
+	if err := insertOptions(ctx, tx, p); err != nil {
+		return err
+	}
+
+	// Remove variants that are no longer part of the matrix, unless they still hold stock
+	kept := []string{}
+	for _, v := range p.Variants {
+		if v.ID != uuid.Nil {
+			kept = append(kept, v.ID.String())
+		}
+	}
+	lockQuery := `SELECT id FROM product_variants WHERE product_id = $1 AND NOT (id = ANY($2::uuid[])) ORDER BY id FOR UPDATE`
+	if _, err := tx.ExecContext(ctx, lockQuery, p.ID, pq.Array(kept)); err != nil {
+		return err
+	}
+	hasStock, err := variantsHaveStock(ctx, tx, p.ID, kept)
+	if err != nil {
+		return err
+	}
+	if hasStock {
+		return sql.ErrNoRows
+	}
+	deleteQuery := `DELETE FROM product_variants WHERE product_id = $1 AND NOT (id = ANY($2::uuid[]))`
+	if _, err := tx.ExecContext(ctx, deleteQuery, p.ID, pq.Array(kept)); err != nil {
+		return err
+	}
+
+	if err := saveVariants(ctx, tx, p); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateVariant saves the SKU, price, barcode, weight and availability of a variant of a product
+func (r *ProductRepository) UpdateVariant(ctx context.Context, productID uuid.UUID, v *catalog.ProductVariant) error {
+	query := `
+        UPDATE product_variants
+        SET sku = $1, price = $2, barcode = $3, weight = $4, is_enabled = $5, updated_at = NOW()
+        WHERE id = $6 AND product_id = $7
+        RETURNING updated_at
+    `
+
+	return r.db.QueryRowContext(ctx, query,
+		v.SKU, v.Price, v.Barcode, v.Weight, v.IsEnabled, v.ID, productID,
+	).Scan(&v.UpdatedAt)
+}
+
+// SetCategories replaces the categories a product is assigned to
+func (r *ProductRepository) SetCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ids := make([]string, len(categoryIDs))
+	for i, id := range categoryIDs {
+		ids[i] = id.String()
+	}
+
+	// Remove categories that are no longer assigned
+	deleteQuery := `
+        DELETE FROM product_categories
+        WHERE product_id = $1 AND NOT (category_id = ANY($2::uuid[]))
+    `
+	if _, err := tx.ExecContext(ctx, deleteQuery, productID, pq.Array(ids)); err != nil {
+		return err
+	}
+
+	// Add new categories, keeping existing assignments untouched
+	insertQuery := `
+        INSERT INTO product_categories (product_id, category_id, created_at)
+        SELECT $1, UNNEST($2::uuid[]), NOW()
+        ON CONFLICT (product_id, category_id) DO NOTHING
+    `
+	if _, err := tx.ExecContext(ctx, insertQuery, productID, pq.Array(ids)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete soft deletes a product
+func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `
+        UPDATE products
+        SET deleted_at = NOW(), updated_at = NOW()
+        WHERE id = $1 AND deleted_at IS NULL
+    `
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// findOne retrieves a single product selected with productSelect, with its images
+func (r *ProductRepository) findOne(ctx context.Context, query string, arg interface{}) (*catalog.Product, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+
+	p, err := scanProduct(rows)
+	if err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := r.loadDetails(ctx, []*catalog.Product{p}); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// loadDetails fills in the images, options, variants and categories of products with a query each
+func (r *ProductRepository) loadDetails(ctx context.Context, products []*catalog.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(products))
+	byID := make(map[uuid.UUID]*catalog.Product, len(products))
+	for i, p := range products {
+		ids[i] = p.ID.String()
+		byID[p.ID] = p
+	}
+
+	if err := r.loadImages(ctx, ids, byID); err != nil {
+		return err
+	}
+	if err := r.loadOptions(ctx, ids, byID); err != nil {
+		return err
+	}
+	if err := r.loadVariants(ctx, ids, byID); err != nil {
+		return err
+	}
+	return r.loadCategoryIDs(ctx, ids, byID)
+}
+
+// loadCategoryIDs fills in the categories products are assigned to by ID
+func (r *ProductRepository) loadCategoryIDs(ctx context.Context, ids []string, byID map[uuid.UUID]*catalog.Product) error {
+	query := `
+        SELECT product_id, category_id
+        FROM product_categories
+        WHERE product_id = ANY($1::uuid[])
+        ORDER BY created_at, category_id
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID, categoryID uuid.UUID
+		if err := rows.Scan(&productID, &categoryID); err != nil {
+			return err
+		}
+		if p, ok := byID[productID]; ok {
+			p.CategoryIDs = append(p.CategoryIDs, categoryID)
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadImages fills in the images of products by ID
+func (r *ProductRepository) loadImages(ctx context.Context, ids []string, byID map[uuid.UUID]*catalog.Product) error {
+	query := `
+        SELECT id, product_id, url, alt_text, position
+        FROM product_images
+        WHERE product_id = ANY($1::uuid[])
+        ORDER BY position, id
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var img catalog.ProductImage
+		var productID uuid.UUID
+		if err := rows.Scan(&img.ID, &productID, &img.URL, &img.AltText, &img.Position); err != nil {
+			return err
+		}
+		if p, ok := byID[productID]; ok {
+			p.Images = append(p.Images, img)
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadOptions fills in the options of products by ID
+func (r *ProductRepository) loadOptions(ctx context.Context, ids []string, byID map[uuid.UUID]*catalog.Product) error {
+	query := `
+        SELECT id, product_id, name, "values", position
+        FROM product_options
+        WHERE product_id = ANY($1::uuid[])
+        ORDER BY position, id
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var option catalog.ProductOption
+		var productID uuid.UUID
+		if err := rows.Scan(&option.ID, &productID, &option.Name, pq.Array(&option.Values), &option.Position); err != nil {
+			return err
+		}
+		if p, ok := byID[productID]; ok {
+			p.Options = append(p.Options, option)
+		}
+	}
+
+	return rows.Err()
+}
+
+// loadVariants fills in the variants of products by ID, in the order of the variant matrix,
+// with their stock available at the location fulfilling online orders
+func (r *ProductRepository) loadVariants(ctx context.Context, ids []string, byID map[uuid.UUID]*catalog.Product) error {
+	query := `
+        SELECT v.id, v.product_id, v.sku, v.options, v.price, v.barcode, v.weight,
+               ` + onHandAt(onlineLocationSQL) + ` - ` + reservedAt(onlineLocationSQL) + `,
+               v.is_enabled, v.created_at, v.updated_at
+        FROM product_variants v
+        WHERE v.product_id = ANY($1::uuid[])
+        ORDER BY v.position, v.id
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v catalog.ProductVariant
+		var productID uuid.UUID
+		var options []byte
+		if err := rows.Scan(
+			&v.ID, &productID, &v.SKU, &options, &v.Price, &v.Barcode, &v.Weight, &v.Stock,
+			&v.IsEnabled, &v.CreatedAt, &v.UpdatedAt,
+		); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(options, &v.Options); err != nil {
+			return err
+		}
+		if p, ok := byID[productID]; ok {
+			p.Variants = append(p.Variants, v)
+		}
+	}
+
+	return rows.Err()
+}
+
+// insertOptions stores the options of a product in order, numbering their positions from zero
+func insertOptions(ctx context.Context, tx *database.Tx, p *catalog.Product) error {
+	query := `
+        INSERT INTO product_options (id, product_id, name, "values", position, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, NOW())
+        RETURNING id
+    `
+
+	for i := range p.Options {
+		option := &p.Options[i]
+		option.Position = i
+		if err := tx.QueryRowContext(ctx, query, p.ID, option.Name, pq.Array(option.Values), option.Position).Scan(&option.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveVariants creates the variants of a product that have no ID yet and stores the matrix order of all of them
+func saveVariants(ctx context.Context, tx *database.Tx, p *catalog.Product) error {
+	insertQuery := `
+        INSERT INTO product_variants (id, product_id, sku, options, price, barcode, weight, is_enabled, position, created_at, updated_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	positionQuery := `UPDATE product_variants SET position = $1 WHERE id = $2 AND position <> $1`
+
+	for i := range p.Variants {
+		v := &p.Variants[i]
+		if v.ID != uuid.Nil {
+			if _, err := tx.ExecContext(ctx, positionQuery, i, v.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		options, err := json.Marshal(v.Options)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.QueryRowContext(ctx, insertQuery,
+			p.ID, v.SKU, options, v.Price, v.Barcode, v.Weight, v.IsEnabled, i,
+		).Scan(&v.ID, &v.CreatedAt, &v.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertImages stores the images of a product in order, numbering their positions from zero
+func insertImages(ctx context.Context, tx *database.Tx, p *catalog.Product) error {
+	query := `
+        INSERT INTO product_images (id, product_id, url, alt_text, position, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, NOW())
+        RETURNING id
+    `
+
+	for i := range p.Images {
+		img := &p.Images[i]
+		img.Position = i
+		if err := tx.QueryRowContext(ctx, query, p.ID, img.URL, img.AltText, img.Position).Scan(&img.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanProduct scans a row selected with productSelect
+func scanProduct(rows *sql.Rows) (*catalog.Product, error) {
+	var p catalog.Product
+	if err := rows.Scan(
+		&p.ID, &p.Name, &p.Slug, &p.Description, &p.Status, pq.Array(&p.Tags), &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	p.Images = []catalog.ProductImage{}
+	p.CategoryIDs = []uuid.UUID{}
+	p.Options = []catalog.ProductOption{}
+	p.Variants = []catalog.ProductVariant{}
+
+	return &p, nil
+}
+
+// ruleComparisons maps collection rule operators to SQL comparison operators
+var ruleComparisons = map[catalog.RuleOperator]string{
+	catalog.RuleOperatorEq:  "=",
+	catalog.RuleOperatorNeq: "<>",
+	catalog.RuleOperatorLt:  "<",
+	catalog.RuleOperatorLte: "<=",
+	catalog.RuleOperatorGt:  ">",
+	catalog.RuleOperatorGte: ">=",
+}
+
+// collectionCondition builds the SQL condition selecting the products of a collection, appending its
+// parameters to args. Rules are expected to be valid; a collection without rules matches nothing.
+func collectionCondition(c *catalog.Collection, args []interface{}) (string, []interface{}) {
+	if !c.IsAutomated() {
+		args = append(args, c.ID)
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM collection_products cp WHERE cp.product_id = products.id AND cp.collection_id = $%d)", len(args)), args
+	}
+
+	if len(c.Rules) == 0 {
+		return "FALSE", args
+	}
+
+	conditions := make([]string, len(c.Rules))
+	for i, rule := range c.Rules {
+		if rule.Operator == catalog.RuleOperatorContains {
+			args = append(args, "%"+escapeLike(rule.Value)+"%")
+		} else {
+			args = append(args, rule.Value)
+		}
+		n := len(args)
+
+		switch rule.Field {
+		case catalog.RuleFieldPrice:
+			conditions[i] = fmt.Sprintf("EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND pv.is_enabled AND pv.price %s $%d::bigint)", ruleComparisons[rule.Operator], n)
+		case catalog.RuleFieldTag:
+			if rule.Operator == catalog.RuleOperatorNeq {
+				conditions[i] = fmt.Sprintf("NOT ($%d = ANY(tags))", n)
+			} else {
+				conditions[i] = fmt.Sprintf("$%d = ANY(tags)", n)
+			}
+		case catalog.RuleFieldName:
+			if rule.Operator == catalog.RuleOperatorContains {
+				conditions[i] = fmt.Sprintf("name ILIKE $%d", n)
+			} else {
+				conditions[i] = fmt.Sprintf("LOWER(name) %s LOWER($%d)", ruleComparisons[rule.Operator], n)
+			}
+		default:
+			conditions[i] = "FALSE"
+		}
+	}
+
+	separator := " AND "
+	if c.Match == catalog.CollectionMatchAny {
+		separator = " OR "
+	}
+	return "(" + strings.Join(conditions, separator) + ")", args
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,275 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+type TransferRepository struct {
+	db *database.DB
+}
+
+func NewTransferRepository(db *sql.DB) *TransferRepository {
+	return &TransferRepository{
+		db: database.Wrap(db),
+	}
+}
+
+const transferSelect = `
+        SELECT id, from_location_id, to_location_id, status, note, created_by,
+               shipped_at, received_at, cancelled_at, created_at, updated_at
+        FROM transfer_orders
+    `
+
+// transferStampColumns are the columns recording when a transfer reached a status
+var transferStampColumns = map[catalog.TransferStatus]string{
+	catalog.TransferStatusInTransit: "shipped_at",
+	catalog.TransferStatusReceived:  "received_at",
+	catalog.TransferStatusCancelled: "cancelled_at",
+}
+
+// GetAll retrieves transfers with their items with pagination and filters, newest first
+func (r *TransferRepository) GetAll(ctx context.Context, page, limit int, filter catalog.TransferFilter) ([]*catalog.TransferOrder, int, error) {
+	offset := (page - 1) * limit
+
+	where := ` WHERE 1=1`
+	args := []interface{}{}
+	argCount := 1
+
+	if filter.Status != "" {
+		where += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, filter.Status)
+		argCount++
+	}
+
+	if filter.LocationID != nil {
+		where += fmt.Sprintf(" AND (from_location_id = $%d OR to_location_id = $%d)", argCount, argCount)
+		args = append(args, *filter.LocationID)
+		argCount++
+	}
+
+	// Get total count
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM transfer_orders`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := transferSelect + where + fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argCount, argCount+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transfers := []*catalog.TransferOrder{}
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		transfers = append(transfers, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadItems(ctx, transfers); err != nil {
+		return nil, 0, err
+	}
+
+	return transfers, total, nil
+}
+
+// FindByID retrieves a transfer by ID with its items
+func (r *TransferRepository) FindByID(ctx context.Context, id uuid.UUID) (*catalog.TransferOrder, error) {
+	t, err := scanTransfer(r.db.QueryRowContext(ctx, transferSelect+`WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, []*catalog.TransferOrder{t}); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Create stores a new draft transfer with its items and fills in the generated fields and the
+// SKUs of the items. Returns sql.ErrNoRows when a variant does not exist.
+func (r *TransferRepository) Create(ctx context.Context, t *catalog.TransferOrder) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO transfer_orders (id, from_location_id, to_location_id, status, note, created_by, created_at, updated_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	if err := tx.QueryRowContext(ctx, query, t.FromLocationID, t.ToLocationID, t.Status, t.Note, t.CreatedBy).Scan(
+		&t.ID, &t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	itemQuery := `
+        INSERT INTO transfer_order_items (transfer_id, variant_id, sku, quantity)
+        SELECT $1, v.id, v.sku, $3
+        FROM product_variants v
+        WHERE v.id = $2
+        RETURNING sku
+    `
+	for i := range t.Items {
+		if err := tx.QueryRowContext(ctx, itemQuery, t.ID, t.Items[i].VariantID, t.Items[i].Quantity).Scan(&t.Items[i].SKU); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Transition moves a transfer in status from to status to and records a transfer movement of
+// every item at locationID, adding the item quantity times sign; no movements are recorded when
+// locationID is nil. Like Record, the variants are locked and a movement must not take more than
+// is available at the location. Returns sql.ErrNoRows when the transfer is no longer in status
+// from or the stock does not suffice.
+func (r *TransferRepository) Transition(ctx context.Context, t *catalog.TransferOrder, from, to catalog.TransferStatus, locationID *uuid.UUID, sign int, reason string, actorID *uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the transfer so it cannot move on twice
+	var id uuid.UUID
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM transfer_orders WHERE id = $1 AND status = $2 FOR UPDATE`, t.ID, from).Scan(&id); err != nil {
+		return err
+	}
+
+	items, err := loadTransferItems(ctx, tx, []uuid.UUID{t.ID})
+	if err != nil {
+		return err
+	}
+	t.Items = items[t.ID]
+
+	if locationID != nil {
+		variantIDs := make([]uuid.UUID, len(t.Items))
+		for i, item := range t.Items {
+			variantIDs[i] = item.VariantID
+		}
+		if err := lockVariants(ctx, tx, variantIDs); err != nil {
+			return err
+		}
+
+		reference := t.ID.String()
+		for _, item := range t.Items {
+			m := &catalog.StockMovement{
+				VariantID:  item.VariantID,
+				LocationID: *locationID,
+				Type:       catalog.MovementTypeTransfer,
+				Quantity:   sign * item.Quantity,
+				Reason:     reason,
+				Reference:  &reference,
+				ActorID:    actorID,
+			}
+			if err := insertMovement(ctx, tx, m, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	updateQuery := fmt.Sprintf(`
+        UPDATE transfer_orders
+        SET status = $1, %s = NOW(), updated_at = NOW()
+        WHERE id = $2
+        RETURNING status, shipped_at, received_at, cancelled_at, updated_at
+    `, transferStampColumns[to])
+	if err := tx.QueryRowContext(ctx, updateQuery, to, t.ID).Scan(
+		&t.Status, &t.ShippedAt, &t.ReceivedAt, &t.CancelledAt, &t.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// loadItems fills in the items of transfers
+func (r *TransferRepository) loadItems(ctx context.Context, transfers []*catalog.TransferOrder) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(transfers))
+	for i, t := range transfers {
+		ids[i] = t.ID
+	}
+
+	items, err := loadTransferItems(ctx, r.db, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range transfers {
+		t.Items = items[t.ID]
+		if t.Items == nil {
+			t.Items = []catalog.TransferItem{}
+		}
+	}
+
+	return nil
+}
+
+// loadTransferItems retrieves the items of transfers by transfer ID
+func loadTransferItems(ctx context.Context, q queryer, transferIDs []uuid.UUID) (map[uuid.UUID][]catalog.TransferItem, error) {
+	query := `
+        SELECT transfer_id, variant_id, sku, quantity
+        FROM transfer_order_items
+        WHERE transfer_id = ANY($1::uuid[])
+        ORDER BY sku, variant_id
+    `
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(uuidStrings(transferIDs)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make(map[uuid.UUID][]catalog.TransferItem, len(transferIDs))
+	for rows.Next() {
+		var transferID uuid.UUID
+		var item catalog.TransferItem
+		if err := rows.Scan(&transferID, &item.VariantID, &item.SKU, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items[transferID] = append(items[transferID], item)
+	}
+
+	return items, rows.Err()
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTransfer(row rowScanner) (*catalog.TransferOrder, error) {
+	t := &catalog.TransferOrder{}
+	if err := row.Scan(
+		&t.ID, &t.FromLocationID, &t.ToLocationID, &t.Status, &t.Note, &t.CreatedBy,
+		&t.ShippedAt, &t.ReceivedAt, &t.CancelledAt, &t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
@@ -0,0 +1,84 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+)
+
+type LoginAttemptRepository struct {
+	db *database.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create records a login attempt in the ledger
+func (r *LoginAttemptRepository) Create(ctx context.Context, userType shared.UserType, email, ipAddress, userAgent string, result shared.LoginAttemptResult) error {
+	query := `
+        INSERT INTO login_attempts (id, user_type, email, ip_address, user_agent, result, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, NOW())
+    `
+
+	_, err := r.db.ExecContext(ctx, query, userType, email, ipAddress, userAgent, result)
+	return err
+}
+
+// FindFailures summarizes failed logins of an email within the window
+// that happened after its last successful login or unlock
+func (r *LoginAttemptRepository) FindFailures(ctx context.Context, userType shared.UserType, email string, window time.Duration) (*shared.LoginFailures, error) {
+	query := `
+        SELECT COUNT(*), COALESCE(MAX(created_at), 'epoch'::timestamp)
+        FROM login_attempts
+        WHERE user_type = $1 AND email = $2 AND result = 'failure'
+          AND created_at > NOW() - $3 * INTERVAL '1 second'
+          AND created_at > COALESCE((
+              SELECT MAX(created_at)
+              FROM login_attempts
+              WHERE user_type = $1 AND email = $2 AND result IN ('success', 'unlock')
+          ), 'epoch'::timestamp)
+    `
+
+	var f shared.LoginFailures
+	err := r.db.QueryRowContext(ctx, query, userType, email, int64(window.Seconds())).Scan(&f.Count, &f.LastAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// CountFailuresByIP counts failed logins from an IP address within the window
+// and returns the time of the oldest one counted
+func (r *LoginAttemptRepository) CountFailuresByIP(ctx context.Context, ipAddress string, window time.Duration) (int, time.Time, error) {
+	query := `
+        SELECT COUNT(*), COALESCE(MIN(created_at), 'epoch'::timestamp)
+        FROM login_attempts
+        WHERE ip_address = $1 AND result = 'failure'
+          AND created_at > NOW() - $2 * INTERVAL '1 second'
+    `
+
+	var count int
+	var oldest time.Time
+	err := r.db.QueryRowContext(ctx, query, ipAddress, int64(window.Seconds())).Scan(&count, &oldest)
+
+	return count, oldest, err
+}
+
+// DeleteOlderThan deletes ledger entries older than the given age
+func (r *LoginAttemptRepository) DeleteOlderThan(ctx context.Context, age time.Duration) error {
+	query := `
+        DELETE FROM login_attempts
+        WHERE created_at < NOW() - $1 * INTERVAL '1 second'
+    `
+
+	_, err := r.db.ExecContext(ctx, query, int64(age.Seconds()))
+	return err
+}
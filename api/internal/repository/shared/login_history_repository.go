@@ -0,0 +1,90 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+)
+
+type LoginHistoryRepository struct {
+	db *database.DB
+}
+
+func NewLoginHistoryRepository(db *sql.DB) *LoginHistoryRepository {
+	return &LoginHistoryRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create appends an entry to the login history
+func (r *LoginHistoryRepository) Create(ctx context.Context, h *shared.LoginHistory) error {
+	query := `
+        INSERT INTO login_history (id, user_type, user_id, result, ip_address, user_agent, browser, os, device, is_new_device, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+        RETURNING id, created_at
+    `
+
+	return r.db.QueryRowContext(ctx, query,
+		h.UserType, h.UserID, h.Result, h.IPAddress, h.UserAgent, h.Browser, h.OS, h.Device, h.IsNewDevice,
+	).Scan(&h.ID, &h.CreatedAt)
+}
+
+// CountSuccesses counts the successful logins of an account, in total,
+// from the given IP address and from the same browser, OS and device
+func (r *LoginHistoryRepository) CountSuccesses(ctx context.Context, userType shared.UserType, userID uuid.UUID, ipAddress, browser, os, device string) (total, fromIP, fromDevice int, err error) {
+	query := `
+        SELECT COUNT(*),
+               COUNT(*) FILTER (WHERE ip_address = $3),
+               COUNT(*) FILTER (WHERE browser = $4 AND os = $5 AND device = $6)
+        FROM login_history
+        WHERE user_type = $1 AND user_id = $2 AND result = 'success'
+    `
+
+	err = r.db.QueryRowContext(ctx, query, userType, userID, ipAddress, browser, os, device).Scan(&total, &fromIP, &fromDevice)
+	return total, fromIP, fromDevice, err
+}
+
+// FindByUser retrieves the login history of an account with pagination, newest first
+func (r *LoginHistoryRepository) FindByUser(ctx context.Context, userType shared.UserType, userID uuid.UUID, page, limit int) ([]*shared.LoginHistory, int, error) {
+	offset := (page - 1) * limit
+
+	// Get total count
+	var total int
+	countQuery := `SELECT COUNT(*) FROM login_history WHERE user_type = $1 AND user_id = $2`
+	if err := r.db.QueryRowContext(ctx, countQuery, userType, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// Get entries
+	query := `
+        SELECT id, user_type, user_id, result, ip_address, COALESCE(user_agent, ''),
+               browser, os, device, is_new_device, created_at
+        FROM login_history
+        WHERE user_type = $1 AND user_id = $2
+        ORDER BY created_at DESC, id DESC
+        LIMIT $3 OFFSET $4
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, userType, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	history := []*shared.LoginHistory{}
+	for rows.Next() {
+		var h shared.LoginHistory
+		if err := rows.Scan(
+			&h.ID, &h.UserType, &h.UserID, &h.Result, &h.IPAddress, &h.UserAgent,
+			&h.Browser, &h.OS, &h.Device, &h.IsNewDevice, &h.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		history = append(history, &h)
+	}
+
+	return history, total, rows.Err()
+}
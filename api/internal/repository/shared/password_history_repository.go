@@ -0,0 +1,73 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+)
+
+type PasswordHistoryRepository struct {
+	db *database.DB
+}
+
+func NewPasswordHistoryRepository(db *sql.DB) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create remembers a password hash of an account and forgets all but the newest keep hashes
+func (r *PasswordHistoryRepository) Create(ctx context.Context, userType shared.UserType, userID uuid.UUID, hashedPassword string, keep int) error {
+	query := `
+        INSERT INTO password_history (id, user_type, user_id, password, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, NOW())
+    `
+
+	if _, err := r.db.ExecContext(ctx, query, userType, userID, hashedPassword); err != nil {
+		return err
+	}
+
+	pruneQuery := `
+        DELETE FROM password_history
+        WHERE user_type = $1 AND user_id = $2 AND id NOT IN (
+            SELECT id FROM password_history
+            WHERE user_type = $1 AND user_id = $2
+            ORDER BY created_at DESC, id DESC
+            LIMIT $3
+        )
+    `
+
+	_, err := r.db.ExecContext(ctx, pruneQuery, userType, userID, keep)
+	return err
+}
+
+// FindRecent retrieves the newest password hashes of an account
+func (r *PasswordHistoryRepository) FindRecent(ctx context.Context, userType shared.UserType, userID uuid.UUID, limit int) ([]string, error) {
+	query := `
+        SELECT password
+        FROM password_history
+        WHERE user_type = $1 AND user_id = $2
+        ORDER BY created_at DESC, id DESC
+        LIMIT $3
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, userType, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, rows.Err()
+}
@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+)
+
+type PasswordResetRepository struct {
+	db *database.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Upsert stores a reset token for an email, replacing any previous one
+func (r *PasswordResetRepository) Upsert(ctx context.Context, email string, userType shared.UserType, token string) error {
+	query := `
+        INSERT INTO password_reset_tokens (email, user_type, token, created_at)
+        VALUES ($1, $2, $3, NOW())
+        ON CONFLICT (email, user_type)
+        DO UPDATE SET token = EXCLUDED.token, created_at = NOW()
+    `
+
+	_, err := r.db.ExecContext(ctx, query, email, userType, token)
+	return err
+}
+
+// FindByEmail retrieves the reset token of an email
+func (r *PasswordResetRepository) FindByEmail(ctx context.Context, email string, userType shared.UserType) (*shared.PasswordResetToken, error) {
+	query := `
+        SELECT email, user_type, token, created_at
+        FROM password_reset_tokens
+        WHERE email = $1 AND user_type = $2
+    `
+
+	var t shared.PasswordResetToken
+	err := r.db.QueryRowContext(ctx, query, email, userType).Scan(
+		&t.Email, &t.UserType, &t.Token, &t.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// Delete deletes the reset token of an email
+func (r *PasswordResetRepository) Delete(ctx context.Context, email string, userType shared.UserType) error {
+	query := `DELETE FROM password_reset_tokens WHERE email = $1 AND user_type = $2`
+	_, err := r.db.ExecContext(ctx, query, email, userType)
+	return err
+}
+
+// DeleteToken deletes the reset token of an email if it is still the given one, so a token can
+// be used only once; sql.ErrNoRows means it was already used or replaced
+func (r *PasswordResetRepository) DeleteToken(ctx context.Context, email string, userType shared.UserType, token string) error {
+	query := `DELETE FROM password_reset_tokens WHERE email = $1 AND user_type = $2 AND token = $3`
+
+	result, err := r.db.ExecContext(ctx, query, email, userType, token)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes reset tokens older than the given lifetime
+func (r *PasswordResetRepository) DeleteExpired(ctx context.Context, lifetime time.Duration) error {
+	query := `
+        DELETE FROM password_reset_tokens
+        WHERE created_at < NOW() - $1 * INTERVAL '1 second'
+    `
+
+	_, err := r.db.ExecContext(ctx, query, int64(lifetime.Seconds()))
+	return err
+}
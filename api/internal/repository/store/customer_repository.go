@@ -5,16 +5,17 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/yeftaz/susano.id/api/internal/database"
 	"github.com/yeftaz/susano.id/api/internal/domain/store"
 )
 
 type CustomerRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 func NewCustomerRepository(db *sql.DB) *CustomerRepository {
 	return &CustomerRepository{
-		db: db,
+		db: database.Wrap(db),
 	}
 }
 
@@ -22,7 +23,7 @@ func NewCustomerRepository(db *sql.DB) *CustomerRepository {
 func (r *CustomerRepository) FindByEmail(ctx context.Context, email string) (*store.Customer, error) {
 	query := `
         SELECT id, email, password, name, avatar_path, is_active,
-               email_verified_at, two_factor_secret, two_factor_recovery_codes,
+               email_verified_at, two_factor_secret, two_factor_pending_secret, two_factor_recovery_codes,
                two_factor_confirmed_at, created_at, updated_at, deleted_at
         FROM customers
         WHERE email = $1 AND deleted_at IS NULL
@@ -31,7 +32,7 @@ func (r *CustomerRepository) FindByEmail(ctx context.Context, email string) (*st
 	var c store.Customer
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&c.ID, &c.Email, &c.Password, &c.Name, &c.AvatarPath,
-		&c.IsActive, &c.EmailVerifiedAt, &c.TwoFactorSecret, &c.TwoFactorRecoveryCodes,
+		&c.IsActive, &c.EmailVerifiedAt, &c.TwoFactorSecret, &c.TwoFactorPendingSecret, &c.TwoFactorRecoveryCodes,
 		&c.TwoFactorConfirmedAt, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt,
 	)
 
@@ -46,7 +47,7 @@ func (r *CustomerRepository) FindByEmail(ctx context.Context, email string) (*st
 func (r *CustomerRepository) FindByID(ctx context.Context, id string) (*store.Customer, error) {
 	query := `
         SELECT id, email, password, name, avatar_path, is_active,
-               email_verified_at, two_factor_secret, two_factor_recovery_codes,
+               email_verified_at, two_factor_secret, two_factor_pending_secret, two_factor_recovery_codes,
                two_factor_confirmed_at, created_at, updated_at, deleted_at
         FROM customers
         WHERE id = $1 AND deleted_at IS NULL
@@ -55,7 +56,7 @@ func (r *CustomerRepository) FindByID(ctx context.Context, id string) (*store.Cu
 	var c store.Customer
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&c.ID, &c.Email, &c.Password, &c.Name, &c.AvatarPath,
-		&c.IsActive, &c.EmailVerifiedAt, &c.TwoFactorSecret, &c.TwoFactorRecoveryCodes,
+		&c.IsActive, &c.EmailVerifiedAt, &c.TwoFactorSecret, &c.TwoFactorPendingSecret, &c.TwoFactorRecoveryCodes,
 		&c.TwoFactorConfirmedAt, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt,
 	)
 
@@ -72,14 +73,14 @@ func (r *CustomerRepository) Create(ctx context.Context, email, passwordHash, na
         INSERT INTO customers (id, email, password, name, is_active, created_at, updated_at)
         VALUES (gen_uuid_v7(), $1, $2, $3, true, NOW(), NOW())
         RETURNING id, email, password, name, avatar_path, is_active,
-                  email_verified_at, two_factor_secret, two_factor_recovery_codes,
+                  email_verified_at, two_factor_secret, two_factor_pending_secret, two_factor_recovery_codes,
                   two_factor_confirmed_at, created_at, updated_at, deleted_at
     `
 
 	var c store.Customer
 	err := r.db.QueryRowContext(ctx, query, email, passwordHash, name).Scan(
 		&c.ID, &c.Email, &c.Password, &c.Name, &c.AvatarPath,
-		&c.IsActive, &c.EmailVerifiedAt, &c.TwoFactorSecret, &c.TwoFactorRecoveryCodes,
+		&c.IsActive, &c.EmailVerifiedAt, &c.TwoFactorSecret, &c.TwoFactorPendingSecret, &c.TwoFactorRecoveryCodes,
 		&c.TwoFactorConfirmedAt, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt,
 	)
 
@@ -98,7 +99,8 @@ func (r *CustomerRepository) Update(ctx context.Context, id string, email, name
 	argCount := 1
 
 	if email != nil {
-		query += fmt.Sprintf(", email = $%d", argCount)
+		// A changed email address has to be verified again
+		query += fmt.Sprintf(", email = $%d, email_verified_at = CASE WHEN email = $%d THEN email_verified_at ELSE NULL END", argCount, argCount)
 		args = append(args, *email)
 		argCount++
 	}
@@ -114,14 +116,14 @@ func (r *CustomerRepository) Update(ctx context.Context, id string, email, name
 
 	query += `
         RETURNING id, email, password, name, avatar_path, is_active,
-                  email_verified_at, two_factor_secret, two_factor_recovery_codes,
+                  email_verified_at, two_factor_secret, two_factor_pending_secret, two_factor_recovery_codes,
                   two_factor_confirmed_at, created_at, updated_at, deleted_at
     `
 
 	var c store.Customer
 	err := r.db.QueryRowContext(ctx, query, args...).Scan(
 		&c.ID, &c.Email, &c.Password, &c.Name, &c.AvatarPath,
-		&c.IsActive, &c.EmailVerifiedAt, &c.TwoFactorSecret, &c.TwoFactorRecoveryCodes,
+		&c.IsActive, &c.EmailVerifiedAt, &c.TwoFactorSecret, &c.TwoFactorPendingSecret, &c.TwoFactorRecoveryCodes,
 		&c.TwoFactorConfirmedAt, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt,
 	)
 
@@ -168,3 +170,133 @@ func (r *CustomerRepository) UpdateAvatarPath(ctx context.Context, id, avatarPat
 	_, err := r.db.ExecContext(ctx, query, avatarPath, id)
 	return err
 }
+
+// UpdatePendingTwoFactorSecret stores a new two-factor secret waiting for confirmation.
+// A confirmed secret stays enforced until the pending one is confirmed.
+func (r *CustomerRepository) UpdatePendingTwoFactorSecret(ctx context.Context, id, secret string) error {
+	query := `
+        UPDATE customers
+        SET two_factor_pending_secret = $1, updated_at = NOW()
+        WHERE id = $2 AND deleted_at IS NULL
+    `
+
+	_, err := r.db.ExecContext(ctx, query, secret, id)
+	return err
+}
+
+// ConfirmTwoFactor swaps the pending secret in as the customer two-factor secret and stores a new batch of recovery codes.
+// step is the time step of the code that confirmed it, which cannot be used again.
+// Returns sql.ErrNoRows when the pending secret was replaced concurrently.
+func (r *CustomerRepository) ConfirmTwoFactor(ctx context.Context, id, pendingSecret, recoveryCodes string, step int64) error {
+	query := `
+        UPDATE customers
+        SET two_factor_secret = two_factor_pending_secret, two_factor_pending_secret = NULL,
+            two_factor_confirmed_at = NOW(), two_factor_recovery_codes = $1, two_factor_last_step = $4, updated_at = NOW()
+        WHERE id = $2 AND two_factor_pending_secret = $3 AND deleted_at IS NULL
+    `
+
+	result, err := r.db.ExecContext(ctx, query, recoveryCodes, id, pendingSecret, step)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DisableTwoFactor clears all two-factor data of a customer
+func (r *CustomerRepository) DisableTwoFactor(ctx context.Context, id string) error {
+	query := `
+        UPDATE customers
+        SET two_factor_secret = NULL, two_factor_pending_secret = NULL, two_factor_recovery_codes = NULL,
+            two_factor_confirmed_at = NULL, two_factor_last_step = NULL, updated_at = NOW()
+        WHERE id = $1 AND deleted_at IS NULL
+    `
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// UseTwoFactorStep records the time step of an accepted two-factor code of the customer.
+// Returns sql.ErrNoRows when a code of the same or a later step was accepted before, so every code works once.
+func (r *CustomerRepository) UseTwoFactorStep(ctx context.Context, id string, step int64) error {
+	query := `
+        UPDATE customers
+        SET two_factor_last_step = $1
+        WHERE id = $2 AND (two_factor_last_step IS NULL OR two_factor_last_step < $1) AND deleted_at IS NULL
+    `
+
+	result, err := r.db.ExecContext(ctx, query, step, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateRecoveryCodes replaces the customer recovery codes if they still match current.
+// Returns sql.ErrNoRows when the codes were changed concurrently (e.g. the same code used twice).
+func (r *CustomerRepository) UpdateRecoveryCodes(ctx context.Context, id string, current *string, recoveryCodes string) error {
+	query := `
+        UPDATE customers
+        SET two_factor_recovery_codes = $1, updated_at = NOW()
+        WHERE id = $2 AND two_factor_recovery_codes IS NOT DISTINCT FROM $3 AND deleted_at IS NULL
+    `
+
+	result, err := r.db.ExecContext(ctx, query, recoveryCodes, id, current)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdatePassword updates customer password hash
+func (r *CustomerRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	query := `
+        UPDATE customers
+        SET password = $1, updated_at = NOW()
+        WHERE id = $2 AND deleted_at IS NULL
+    `
+
+	_, err := r.db.ExecContext(ctx, query, passwordHash, id)
+	return err
+}
+
+// MarkEmailAsVerified sets the email verification timestamp
+func (r *CustomerRepository) MarkEmailAsVerified(ctx context.Context, id string) error {
+	query := `
+        UPDATE customers
+        SET email_verified_at = NOW(), updated_at = NOW()
+        WHERE id = $1 AND email_verified_at IS NULL AND deleted_at IS NULL
+    `
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+)
+
+type IdentityRepository struct {
+	db *database.DB
+}
+
+func NewIdentityRepository(db *sql.DB) *IdentityRepository {
+	return &IdentityRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create links an identity to a customer
+func (r *IdentityRepository) Create(ctx context.Context, customerID uuid.UUID, provider, subject string, email *string) (*store.Identity, error) {
+	query := `
+        INSERT INTO customer_identities (id, customer_id, provider, subject, email, last_used_at, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, NOW(), NOW())
+        RETURNING id, customer_id, provider, subject, email, last_used_at, created_at
+    `
+
+	var i store.Identity
+	err := r.db.QueryRowContext(ctx, query, customerID, provider, subject, email).Scan(
+		&i.ID, &i.CustomerID, &i.Provider, &i.Subject, &i.Email, &i.LastUsedAt, &i.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+// FindBySubject retrieves an identity by its provider and account ID at the provider
+func (r *IdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*store.Identity, error) {
+	query := `
+        SELECT id, customer_id, provider, subject, email, last_used_at, created_at
+        FROM customer_identities
+        WHERE provider = $1 AND subject = $2
+    `
+
+	var i store.Identity
+	err := r.db.QueryRowContext(ctx, query, provider, subject).Scan(
+		&i.ID, &i.CustomerID, &i.Provider, &i.Subject, &i.Email, &i.LastUsedAt, &i.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+// FindByCustomerID retrieves all identities linked to a customer
+func (r *IdentityRepository) FindByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*store.Identity, error) {
+	query := `
+        SELECT id, customer_id, provider, subject, email, last_used_at, created_at
+        FROM customer_identities
+        WHERE customer_id = $1
+        ORDER BY created_at ASC
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []*store.Identity{}
+	for rows.Next() {
+		var i store.Identity
+		if err := rows.Scan(&i.ID, &i.CustomerID, &i.Provider, &i.Subject, &i.Email, &i.LastUsedAt, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		identities = append(identities, &i)
+	}
+
+	return identities, rows.Err()
+}
+
+// UpdateLastUsed records a login with an identity and the email the provider reported for it
+func (r *IdentityRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, email *string) error {
+	query := `UPDATE customer_identities SET last_used_at = NOW(), email = $2 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, email)
+	return err
+}
+
+// Delete unlinks an identity of a customer
+func (r *IdentityRepository) Delete(ctx context.Context, id, customerID uuid.UUID) error {
+	query := `DELETE FROM customer_identities WHERE id = $1 AND customer_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, customerID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+)
+
+type MagicLinkRepository struct {
+	db *database.DB
+}
+
+func NewMagicLinkRepository(db *sql.DB) *MagicLinkRepository {
+	return &MagicLinkRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create creates a new magic link
+func (r *MagicLinkRepository) Create(ctx context.Context, customerID uuid.UUID, token string, browserBinding *string, ipAddress, userAgent string, expiresAt time.Time) (*store.MagicLink, error) {
+	query := `
+        INSERT INTO customer_magic_links (id, customer_id, token, browser_binding, ip_address, user_agent, expires_at, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, NOW())
+        RETURNING id, customer_id, token, browser_binding, ip_address, COALESCE(user_agent, ''), expires_at, created_at
+    `
+
+	var l store.MagicLink
+	err := r.db.QueryRowContext(ctx, query, customerID, token, browserBinding, ipAddress, userAgent, expiresAt).Scan(
+		&l.ID, &l.CustomerID, &l.Token, &l.BrowserBinding, &l.IPAddress, &l.UserAgent, &l.ExpiresAt, &l.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// FindByToken retrieves a magic link by token
+func (r *MagicLinkRepository) FindByToken(ctx context.Context, token string) (*store.MagicLink, error) {
+	query := `
+        SELECT id, customer_id, token, browser_binding, ip_address, COALESCE(user_agent, ''), expires_at, created_at
+        FROM customer_magic_links
+        WHERE token = $1
+    `
+
+	var l store.MagicLink
+	err := r.db.QueryRowContext(ctx, query, token).Scan(
+		&l.ID, &l.CustomerID, &l.Token, &l.BrowserBinding, &l.IPAddress, &l.UserAgent, &l.ExpiresAt, &l.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// Delete deletes a magic link by ID
+func (r *MagicLinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM customer_magic_links WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteByCustomerID deletes all magic links for a customer
+func (r *MagicLinkRepository) DeleteByCustomerID(ctx context.Context, customerID uuid.UUID) error {
+	query := `DELETE FROM customer_magic_links WHERE customer_id = $1`
+	_, err := r.db.ExecContext(ctx, query, customerID)
+	return err
+}
+
+// DeleteExpired deletes expired magic links
+func (r *MagicLinkRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM customer_magic_links WHERE expires_at < NOW()`
+	_, err := r.db.ExecContext(ctx, query)
+	return err
+}
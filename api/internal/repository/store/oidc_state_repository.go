@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+)
+
+type OIDCStateRepository struct {
+	db *database.DB
+}
+
+func NewOIDCStateRepository(db *sql.DB) *OIDCStateRepository {
+	return &OIDCStateRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create creates a new pending provider login
+func (r *OIDCStateRepository) Create(ctx context.Context, provider, state, nonce, codeVerifier, ipAddress, userAgent string, expiresAt time.Time) (*store.OIDCState, error) {
+	query := `
+        INSERT INTO customer_oidc_states (id, provider, state, nonce, code_verifier, ip_address, user_agent, expires_at, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, $6, $7, NOW())
+        RETURNING id, provider, state, nonce, code_verifier, ip_address, COALESCE(user_agent, ''), expires_at, created_at
+    `
+
+	var s store.OIDCState
+	err := r.db.QueryRowContext(ctx, query, provider, state, nonce, codeVerifier, ipAddress, userAgent, expiresAt).Scan(
+		&s.ID, &s.Provider, &s.State, &s.Nonce, &s.CodeVerifier, &s.IPAddress, &s.UserAgent, &s.ExpiresAt, &s.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// FindByState retrieves a pending login at a provider by state
+func (r *OIDCStateRepository) FindByState(ctx context.Context, provider, state string) (*store.OIDCState, error) {
+	query := `
+        SELECT id, provider, state, nonce, code_verifier, ip_address, COALESCE(user_agent, ''), expires_at, created_at
+        FROM customer_oidc_states
+        WHERE provider = $1 AND state = $2
+    `
+
+	var s store.OIDCState
+	err := r.db.QueryRowContext(ctx, query, provider, state).Scan(
+		&s.ID, &s.Provider, &s.State, &s.Nonce, &s.CodeVerifier, &s.IPAddress, &s.UserAgent, &s.ExpiresAt, &s.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Delete deletes a pending login by ID
+func (r *OIDCStateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM customer_oidc_states WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes expired pending logins
+func (r *OIDCStateRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM customer_oidc_states WHERE expires_at < NOW()`
+	_, err := r.db.ExecContext(ctx, query)
+	return err
+}
@@ -3,19 +3,21 @@ package store
 import (
 	"context"
 	"database/sql"
-	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
 	"github.com/yeftaz/susano.id/api/internal/domain/store"
 )
 
 type SessionRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 func NewSessionRepository(db *sql.DB) *SessionRepository {
 	return &SessionRepository{
-		db: db,
+		db: database.Wrap(db),
 	}
 }
 
@@ -24,12 +26,12 @@ func (r *SessionRepository) Create(ctx context.Context, customerID uuid.UUID, to
 	query := `
         INSERT INTO customer_sessions (id, customer_id, token, ip_address, user_agent, last_activity_at, created_at)
         VALUES (gen_uuid_v7(), $1, $2, $3, $4, NOW(), NOW())
-        RETURNING id, customer_id, token, ip_address, user_agent, last_activity_at, created_at
+        RETURNING id, customer_id, token, ip_address, user_agent, impersonator_id, last_activity_at, created_at
     `
 
 	var s store.Session
 	err := r.db.QueryRowContext(ctx, query, customerID, token, ipAddress, userAgent).Scan(
-		&s.ID, &s.CustomerID, &s.Token, &s.IPAddress, &s.UserAgent, &s.LastActivityAt, &s.CreatedAt,
+		&s.ID, &s.CustomerID, &s.Token, &s.IPAddress, &s.UserAgent, &s.ImpersonatorID, &s.LastActivityAt, &s.CreatedAt,
 	)
 
 	if err != nil {
@@ -39,17 +41,39 @@ func (r *SessionRepository) Create(ctx context.Context, customerID uuid.UUID, to
 	return &s, nil
 }
 
-// FindByToken retrieves a session by token
-func (r *SessionRepository) FindByToken(ctx context.Context, token string) (*store.Session, error) {
+// CreateImpersonation creates a customer session on behalf of an impersonating admin
+func (r *SessionRepository) CreateImpersonation(ctx context.Context, customerID, impersonatorID uuid.UUID, token, ipAddress, userAgent string) (*store.Session, error) {
 	query := `
-        SELECT id, customer_id, token, ip_address, user_agent, last_activity_at, created_at
+        INSERT INTO customer_sessions (id, customer_id, token, ip_address, user_agent, impersonator_id, last_activity_at, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, $5, NOW(), NOW())
+        RETURNING id, customer_id, token, ip_address, user_agent, impersonator_id, last_activity_at, created_at
+    `
+
+	var s store.Session
+	err := r.db.QueryRowContext(ctx, query, customerID, token, ipAddress, userAgent, impersonatorID).Scan(
+		&s.ID, &s.CustomerID, &s.Token, &s.IPAddress, &s.UserAgent, &s.ImpersonatorID, &s.LastActivityAt, &s.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// FindByToken retrieves a session by token hash, accepting any of the given hashes
+// (one per accepted hashing key)
+func (r *SessionRepository) FindByToken(ctx context.Context, hashes []string) (*store.Session, error) {
+	query := `
+        SELECT id, customer_id, token, ip_address, user_agent, impersonator_id, last_activity_at, created_at
         FROM customer_sessions
-        WHERE token = $1
+        WHERE token = ANY($1)
+        LIMIT 1
     `
 
 	var s store.Session
-	err := r.db.QueryRowContext(ctx, query, token).Scan(
-		&s.ID, &s.CustomerID, &s.Token, &s.IPAddress, &s.UserAgent, &s.LastActivityAt, &s.CreatedAt,
+	err := r.db.QueryRowContext(ctx, query, pq.Array(hashes)).Scan(
+		&s.ID, &s.CustomerID, &s.Token, &s.IPAddress, &s.UserAgent, &s.ImpersonatorID, &s.LastActivityAt, &s.CreatedAt,
 	)
 
 	if err != nil {
@@ -60,22 +84,34 @@ func (r *SessionRepository) FindByToken(ctx context.Context, token string) (*sto
 }
 
 // UpdateLastActivity updates the last activity timestamp
-func (r *SessionRepository) UpdateLastActivity(ctx context.Context, token string) error {
+func (r *SessionRepository) UpdateLastActivity(ctx context.Context, id uuid.UUID) error {
 	query := `
         UPDATE customer_sessions
         SET last_activity_at = NOW()
-        WHERE token = $1
+        WHERE id = $1
+    `
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// UpdateToken replaces the stored token hash, used to rehash sessions under a new key
+func (r *SessionRepository) UpdateToken(ctx context.Context, id uuid.UUID, token string) error {
+	query := `
+        UPDATE customer_sessions
+        SET token = $1
+        WHERE id = $2
     `
 
-	_, err := r.db.ExecContext(ctx, query, token)
+	_, err := r.db.ExecContext(ctx, query, token, id)
 	return err
 }
 
-// Delete deletes a session by token
-func (r *SessionRepository) Delete(ctx context.Context, token string) error {
-	query := `DELETE FROM customer_sessions WHERE token = $1`
+// Delete deletes a session by ID
+func (r *SessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM customer_sessions WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, token)
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -99,13 +135,96 @@ func (r *SessionRepository) DeleteByCustomerID(ctx context.Context, customerID u
 	return err
 }
 
-// DeleteExpired deletes expired sessions
-func (r *SessionRepository) DeleteExpired(ctx context.Context, sessionLifetime time.Duration) error {
+// FindByCustomerID retrieves all sessions of a customer, most recently active first
+func (r *SessionRepository) FindByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*store.Session, error) {
+	query := `
+        SELECT id, customer_id, token, ip_address, user_agent, impersonator_id, last_activity_at, created_at
+        FROM customer_sessions
+        WHERE customer_id = $1
+        ORDER BY last_activity_at DESC
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*store.Session{}
+	for rows.Next() {
+		var s store.Session
+		if err := rows.Scan(
+			&s.ID, &s.CustomerID, &s.Token, &s.IPAddress, &s.UserAgent, &s.ImpersonatorID, &s.LastActivityAt, &s.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+
+	return sessions, rows.Err()
+}
+
+// DeleteByID deletes a session by ID, only if it belongs to the given customer
+func (r *SessionRepository) DeleteByID(ctx context.Context, id, customerID uuid.UUID) error {
+	query := `DELETE FROM customer_sessions WHERE id = $1 AND customer_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, customerID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteImpersonation deletes an impersonation session, only if it was started by the given admin
+func (r *SessionRepository) DeleteImpersonation(ctx context.Context, id, impersonatorID uuid.UUID) (*store.Session, error) {
+	query := `
+        DELETE FROM customer_sessions
+        WHERE id = $1 AND impersonator_id = $2
+        RETURNING id, customer_id, token, ip_address, user_agent, impersonator_id, last_activity_at, created_at
+    `
+
+	var s store.Session
+	err := r.db.QueryRowContext(ctx, query, id, impersonatorID).Scan(
+		&s.ID, &s.CustomerID, &s.Token, &s.IPAddress, &s.UserAgent, &s.ImpersonatorID, &s.LastActivityAt, &s.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// DeleteOthers deletes all sessions of a customer except the given one and returns how many were deleted
+func (r *SessionRepository) DeleteOthers(ctx context.Context, customerID, exceptID uuid.UUID) (int64, error) {
+	query := `DELETE FROM customer_sessions WHERE customer_id = $1 AND id <> $2`
+
+	result, err := r.db.ExecContext(ctx, query, customerID, exceptID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// DeleteExpired deletes sessions that are expired under the given policy
+func (r *SessionRepository) DeleteExpired(ctx context.Context, policy shared.SessionPolicy) error {
 	query := `
         DELETE FROM customer_sessions
-        WHERE created_at < NOW() - $1::interval
+        WHERE ($1 > 0 AND created_at < NOW() - $1 * INTERVAL '1 second')
+           OR ($2 > 0 AND last_activity_at < NOW() - $2 * INTERVAL '1 second')
     `
 
-	_, err := r.db.ExecContext(ctx, query, sessionLifetime)
+	_, err := r.db.ExecContext(ctx, query, int64(policy.Lifetime.Seconds()), int64(policy.IdleTimeout.Seconds()))
 	return err
 }
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+)
+
+type TwoFactorChallengeRepository struct {
+	db *database.DB
+}
+
+func NewTwoFactorChallengeRepository(db *sql.DB) *TwoFactorChallengeRepository {
+	return &TwoFactorChallengeRepository{
+		db: database.Wrap(db),
+	}
+}
+
+// Create creates a new two-factor challenge
+func (r *TwoFactorChallengeRepository) Create(ctx context.Context, customerID uuid.UUID, token, ipAddress, userAgent string, expiresAt time.Time) (*store.TwoFactorChallenge, error) {
+	query := `
+        INSERT INTO customer_two_factor_challenges (id, customer_id, token, ip_address, user_agent, attempts, expires_at, created_at)
+        VALUES (gen_uuid_v7(), $1, $2, $3, $4, 0, $5, NOW())
+        RETURNING id, customer_id, token, ip_address, user_agent, attempts, expires_at, created_at
+    `
+
+	var c store.TwoFactorChallenge
+	err := r.db.QueryRowContext(ctx, query, customerID, token, ipAddress, userAgent, expiresAt).Scan(
+		&c.ID, &c.CustomerID, &c.Token, &c.IPAddress, &c.UserAgent, &c.Attempts, &c.ExpiresAt, &c.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// FindByToken retrieves a challenge by token
+func (r *TwoFactorChallengeRepository) FindByToken(ctx context.Context, token string) (*store.TwoFactorChallenge, error) {
+	query := `
+        SELECT id, customer_id, token, ip_address, user_agent, attempts, expires_at, created_at
+        FROM customer_two_factor_challenges
+        WHERE token = $1
+    `
+
+	var c store.TwoFactorChallenge
+	err := r.db.QueryRowContext(ctx, query, token).Scan(
+		&c.ID, &c.CustomerID, &c.Token, &c.IPAddress, &c.UserAgent, &c.Attempts, &c.ExpiresAt, &c.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// IncrementAttempts records a failed code attempt
+func (r *TwoFactorChallengeRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	query := `
+        UPDATE customer_two_factor_challenges
+        SET attempts = attempts + 1
+        WHERE id = $1
+    `
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// Delete deletes a challenge by ID
+func (r *TwoFactorChallengeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM customer_two_factor_challenges WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteByCustomerID deletes all challenges for a customer
+func (r *TwoFactorChallengeRepository) DeleteByCustomerID(ctx context.Context, customerID uuid.UUID) error {
+	query := `DELETE FROM customer_two_factor_challenges WHERE customer_id = $1`
+	_, err := r.db.ExecContext(ctx, query, customerID)
+	return err
+}
+
+// DeleteExpired deletes expired challenges
+func (r *TwoFactorChallengeRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM customer_two_factor_challenges WHERE expires_at < NOW()`
+	_, err := r.db.ExecContext(ctx, query)
+	return err
+}
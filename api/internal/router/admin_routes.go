@@ -6,55 +6,237 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/yeftaz/susano.id/api/internal/config"
+	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
+	sharedDomain "github.com/yeftaz/susano.id/api/internal/domain/shared"
 	adminHandler "github.com/yeftaz/susano.id/api/internal/handler/admin"
+	sharedHandler "github.com/yeftaz/susano.id/api/internal/handler/shared"
 	"github.com/yeftaz/susano.id/api/internal/middleware"
 	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	catalogRepo "github.com/yeftaz/susano.id/api/internal/repository/catalog"
+	sharedRepo "github.com/yeftaz/susano.id/api/internal/repository/shared"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
 	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+	catalogService "github.com/yeftaz/susano.id/api/internal/service/catalog"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
 )
 
 // RegisterAdminRoutes registers all admin routes
-func RegisterAdminRoutes(r *mux.Router, cfg *config.Config, db *sql.DB, logger *logger.Logger) {
+func RegisterAdminRoutes(r *mux.Router, cfg *config.Config, db *sql.DB, logger *logger.Logger) error {
 	// Initialize repositories
 	adminRepository := adminRepo.NewAdminRepository(db)
 	sessionRepository := adminRepo.NewSessionRepository(db)
+	challengeRepository := adminRepo.NewTwoFactorChallengeRepository(db)
+	passwordResetRepository := sharedRepo.NewPasswordResetRepository(db)
+	loginAttemptRepository := sharedRepo.NewLoginAttemptRepository(db)
+	loginHistoryRepository := sharedRepo.NewLoginHistoryRepository(db)
+	passwordHistoryRepository := sharedRepo.NewPasswordHistoryRepository(db)
+	customerRepository := storeRepo.NewCustomerRepository(db)
+	customerSessionRepository := storeRepo.NewSessionRepository(db)
+	rolePermissionRepository := adminRepo.NewRolePermissionRepository(db)
+	auditLogRepository := adminRepo.NewAuditLogRepository(db)
+	passkeyRepository := adminRepo.NewPasskeyRepository(db)
+	webAuthnChallengeRepository := adminRepo.NewWebAuthnChallengeRepository(db)
+	apiKeyRepository := adminRepo.NewAPIKeyRepository(db)
+	productRepository := catalogRepo.NewProductRepository(db)
+	categoryRepository := catalogRepo.NewCategoryRepository(db)
+	collectionRepository := catalogRepo.NewCollectionRepository(db)
+	inventoryRepository := catalogRepo.NewInventoryRepository(db)
+	locationRepository := catalogRepo.NewLocationRepository(db)
+	transferRepository := catalogRepo.NewTransferRepository(db)
+
+	// Initialize mailer
+	mail := newMailer(cfg, logger)
 
 	// Initialize services
-	authService := adminService.NewAuthService(adminRepository, sessionRepository)
-	adminSvc := adminService.NewAdminService(adminRepository)
+	auditService := adminService.NewAuditService(auditLogRepository)
+	loginThrottleService := sharedService.NewLoginThrottleService(loginAttemptRepository, sharedService.NewMailLockoutNotifier(mail, logger), logger, cfg)
+	loginHistoryService := sharedService.NewLoginHistoryService(loginHistoryRepository, sharedService.NewMailNewDeviceNotifier(mail, logger), logger)
+	passwordPolicyService, err := sharedService.NewPasswordPolicyService(passwordHistoryRepository, logger, cfg)
+	if err != nil {
+		return err
+	}
+	passkeyService := adminService.NewPasskeyService(adminRepository, passkeyRepository, webAuthnChallengeRepository, cfg)
+	authService := adminService.NewAuthService(adminRepository, sessionRepository, challengeRepository, loginThrottleService, loginHistoryService, passkeyService, passwordPolicyService, auditService, cfg)
+	twoFactorService := sharedService.NewTwoFactorService(adminService.NewTwoFactorAccounts(adminRepository, challengeRepository), cfg)
+	passwordResetService := adminService.NewPasswordResetService(adminRepository, sessionRepository, passwordResetRepository, passwordPolicyService, mail, logger, cfg)
+	sessionService := adminService.NewSessionService(adminRepository, sessionRepository, authService, auditService)
+	lockoutService := adminService.NewLockoutService(adminRepository, customerRepository, loginThrottleService, auditService)
+	impersonationService := adminService.NewImpersonationService(customerRepository, customerSessionRepository, auditService, cfg)
+	permissionService := adminService.NewPermissionService(rolePermissionRepository, auditService)
+	apiKeyService := adminService.NewAPIKeyService(apiKeyRepository, adminRepository, permissionService, auditService, cfg)
+	adminSvc := adminService.NewAdminService(adminRepository, passwordPolicyService, auditService)
 	uploadService := adminService.NewUploadService()
+	productService := catalogService.NewProductService(productRepository, categoryRepository, auditService)
+	categoryService := catalogService.NewCategoryService(categoryRepository, productRepository, auditService)
+	collectionService := catalogService.NewCollectionService(collectionRepository, productRepository, auditService)
+	inventoryService := catalogService.NewInventoryService(inventoryRepository, locationRepository, auditService, cfg)
+	locationService := catalogService.NewLocationService(locationRepository, adminRepository, auditService)
+	transferService := catalogService.NewTransferService(transferRepository, locationRepository, auditService)
 
 	// Initialize handlers
 	authHandler := adminHandler.NewAuthHandler(authService, logger, cfg)
+	twoFactorHandler := sharedHandler.NewTwoFactorHandler(twoFactorService, sharedDomain.UserTypeAdmin, logger)
+	passkeyHandler := adminHandler.NewPasskeyHandler(passkeyService, logger)
+	apiKeyHandler := adminHandler.NewAPIKeyHandler(apiKeyService, logger)
+	passwordResetHandler := adminHandler.NewPasswordResetHandler(passwordResetService, logger)
+	sessionHandler := adminHandler.NewSessionHandler(sessionService, logger)
+	loginHistoryHandler := adminHandler.NewLoginHistoryHandler(loginHistoryService, logger)
+	lockoutHandler := adminHandler.NewLockoutHandler(lockoutService, logger)
+	impersonationHandler := adminHandler.NewImpersonationHandler(impersonationService, logger)
+	roleHandler := adminHandler.NewRoleHandler(permissionService, logger)
+	auditLogHandler := adminHandler.NewAuditLogHandler(auditService, logger)
 	adminHdlr := adminHandler.NewAdminHandler(adminSvc, logger)
 	dashboardHandler := adminHandler.NewDashboardHandler(logger)
 	uploadHandler := adminHandler.NewUploadHandler(uploadService, logger)
+	productHandler := adminHandler.NewProductHandler(productService, logger)
+	categoryHandler := adminHandler.NewCategoryHandler(categoryService, logger)
+	collectionHandler := adminHandler.NewCollectionHandler(collectionService, logger)
+	inventoryHandler := adminHandler.NewInventoryHandler(inventoryService, logger)
+	locationHandler := adminHandler.NewLocationHandler(locationService, logger)
+	transferHandler := adminHandler.NewTransferHandler(transferService, logger)
+	csrfHandler := sharedHandler.NewCSRFHandler()
+
+	// Auth middleware; state-changing requests of cookie sessions must also pass the CSRF check
+	authenticate := middleware.AdminAuth(authService, apiKeyService, cfg, logger)
+	csrf := middleware.CSRF(cfg)
+	adminAuth := func(h http.Handler) http.Handler {
+		return authenticate(csrf(h))
+	}
+
+	// can guards a route with adminAuth and a permission, which an API key must also be scoped for
+	can := func(permission adminDomain.Permission, h http.HandlerFunc) http.Handler {
+		return adminAuth(middleware.RequirePermission(permissionService, logger, permission)(h))
+	}
 
-	// Auth middleware
-	adminAuth := middleware.AdminAuth(authService, cfg, logger)
+	// self guards self-service /auth routes, which need a signed-in admin and are not open to API keys
+	self := func(h http.HandlerFunc) http.Handler {
+		return adminAuth(middleware.RequireAdminSession(h))
+	}
 
 	// Admin routes
 	admin := r.PathPrefix("/admin").Subrouter()
 
 	// Auth routes (public)
 	admin.HandleFunc("/auth/login", authHandler.Login).Methods("POST")
+	admin.HandleFunc("/auth/2fa/verify", authHandler.VerifyTwoFactor).Methods("POST")
+	admin.HandleFunc("/auth/2fa/passkey", authHandler.VerifyTwoFactorPasskey).Methods("POST")
+	admin.HandleFunc("/auth/passkey/options", passkeyHandler.LoginOptions).Methods("POST")
+	admin.HandleFunc("/auth/passkey/login", authHandler.LoginWithPasskey).Methods("POST")
+	admin.HandleFunc("/auth/forgot-password", passwordResetHandler.ForgotPassword).Methods("POST")
+	admin.HandleFunc("/auth/reset-password", passwordResetHandler.ResetPassword).Methods("POST")
 
 	// Auth routes (protected)
-	admin.Handle("/auth/logout", adminAuth(http.HandlerFunc(authHandler.Logout))).Methods("POST")
+	admin.Handle("/auth/logout", self(authHandler.Logout)).Methods("POST")
 	admin.Handle("/auth/me", adminAuth(http.HandlerFunc(authHandler.GetCurrentUser))).Methods("GET")
-	admin.Handle("/auth/refresh", adminAuth(http.HandlerFunc(authHandler.RefreshSession))).Methods("POST")
+	admin.Handle("/auth/refresh", self(authHandler.RefreshSession)).Methods("POST")
+	admin.Handle("/auth/csrf", self(csrfHandler.Token)).Methods("GET")
+	admin.Handle("/auth/password", self(authHandler.ChangePassword)).Methods("POST")
+
+	// Session routes (protected)
+	admin.Handle("/auth/sessions", self(sessionHandler.List)).Methods("GET")
+	admin.Handle("/auth/sessions", self(sessionHandler.RevokeOthers)).Methods("DELETE")
+	admin.Handle("/auth/sessions/{id}", self(sessionHandler.Revoke)).Methods("DELETE")
+	admin.Handle("/auth/login-history", self(loginHistoryHandler.List)).Methods("GET")
+
+	// Two-factor routes (protected)
+	admin.Handle("/auth/2fa/setup", self(twoFactorHandler.Setup)).Methods("POST")
+	admin.Handle("/auth/2fa/confirm", self(twoFactorHandler.Confirm)).Methods("POST")
+	admin.Handle("/auth/2fa/disable", self(twoFactorHandler.Disable)).Methods("POST")
+	admin.Handle("/auth/2fa/regenerate", self(twoFactorHandler.Regenerate)).Methods("POST")
+	admin.Handle("/auth/2fa/recovery-codes", self(twoFactorHandler.RegenerateRecoveryCodes)).Methods("POST")
+
+	// Passkey routes (protected)
+	admin.Handle("/auth/passkeys", self(passkeyHandler.List)).Methods("GET")
+	admin.Handle("/auth/passkeys/options", self(passkeyHandler.RegistrationOptions)).Methods("POST")
+	admin.Handle("/auth/passkeys", self(passkeyHandler.Register)).Methods("POST")
+	admin.Handle("/auth/passkeys/{id}", self(passkeyHandler.Delete)).Methods("DELETE")
+
+	// API key routes (protected)
+	// Creating keys needs a permission, listing and revoking own keys stays possible without it
+	createAPIKey := middleware.RequirePermission(permissionService, logger, adminDomain.PermissionAPIKeysManage)(http.HandlerFunc(apiKeyHandler.Create))
+	admin.Handle("/auth/api-keys", self(apiKeyHandler.List)).Methods("GET")
+	admin.Handle("/auth/api-keys", adminAuth(middleware.RequireAdminSession(createAPIKey))).Methods("POST")
+	admin.Handle("/auth/api-keys/{id}", self(apiKeyHandler.Revoke)).Methods("DELETE")
 
 	// Admin CRUD routes (protected)
-	admin.Handle("/admins", adminAuth(http.HandlerFunc(adminHdlr.GetAll))).Methods("GET")
-	admin.Handle("/admins", adminAuth(http.HandlerFunc(adminHdlr.Create))).Methods("POST")
-	admin.Handle("/admins/{id}", adminAuth(http.HandlerFunc(adminHdlr.GetByID))).Methods("GET")
-	admin.Handle("/admins/{id}", adminAuth(http.HandlerFunc(adminHdlr.Update))).Methods("PATCH")
-	admin.Handle("/admins/{id}", adminAuth(http.HandlerFunc(adminHdlr.Delete))).Methods("DELETE")
+	admin.Handle("/admins", can(adminDomain.PermissionAdminsView, adminHdlr.GetAll)).Methods("GET")
+	admin.Handle("/admins", can(adminDomain.PermissionAdminsCreate, adminHdlr.Create)).Methods("POST")
+	admin.Handle("/admins/{id}", can(adminDomain.PermissionAdminsView, adminHdlr.GetByID)).Methods("GET")
+	admin.Handle("/admins/{id}", can(adminDomain.PermissionAdminsUpdate, adminHdlr.Update)).Methods("PATCH")
+	admin.Handle("/admins/{id}", can(adminDomain.PermissionAdminsDelete, adminHdlr.Delete)).Methods("DELETE")
+	admin.Handle("/admins/{id}/login-history", can(adminDomain.PermissionAdminsView, loginHistoryHandler.ListForAdmin)).Methods("GET")
+	admin.Handle("/admins/{id}/sessions", can(adminDomain.PermissionAdminsRevokeSessions, sessionHandler.RevokeAll)).Methods("DELETE")
+	admin.Handle("/admins/{id}/unlock", can(adminDomain.PermissionAdminsUnlock, lockoutHandler.UnlockAdmin)).Methods("POST")
+	admin.Handle("/admins/{id}/location", can(adminDomain.PermissionLocationsManage, locationHandler.AssignAdmin)).Methods("PUT")
+
+	// Role and permission routes (protected)
+	admin.Handle("/permissions", can(adminDomain.PermissionRolesView, roleHandler.ListPermissions)).Methods("GET")
+	admin.Handle("/roles", can(adminDomain.PermissionRolesView, roleHandler.ListRoles)).Methods("GET")
+	admin.Handle("/roles/{role}/permissions", can(adminDomain.PermissionRolesUpdate, roleHandler.UpdatePermissions)).Methods("PUT")
+
+	// Audit log routes (protected)
+	admin.Handle("/audit-logs", can(adminDomain.PermissionAuditLogsView, auditLogHandler.GetAll)).Methods("GET")
+	admin.Handle("/audit-logs/export", can(adminDomain.PermissionAuditLogsView, auditLogHandler.Export)).Methods("GET")
+
+	// Customer management routes (protected)
+	admin.Handle("/customers/{id}/unlock", can(adminDomain.PermissionCustomersUnlock, lockoutHandler.UnlockCustomer)).Methods("POST")
+	admin.Handle("/customers/{id}/impersonate", can(adminDomain.PermissionCustomersImpersonate, impersonationHandler.Start)).Methods("POST")
+	admin.Handle("/impersonations/{id}", can(adminDomain.PermissionCustomersImpersonate, impersonationHandler.End)).Methods("DELETE")
+
+	// Product catalog routes (protected)
+	admin.Handle("/products", can(adminDomain.PermissionProductsView, productHandler.GetAll)).Methods("GET")
+	admin.Handle("/products", can(adminDomain.PermissionProductsCreate, productHandler.Create)).Methods("POST")
+	admin.Handle("/products/{id}", can(adminDomain.PermissionProductsView, productHandler.GetByID)).Methods("GET")
+	admin.Handle("/products/{id}", can(adminDomain.PermissionProductsUpdate, productHandler.Update)).Methods("PATCH")
+	admin.Handle("/products/{id}", can(adminDomain.PermissionProductsDelete, productHandler.Delete)).Methods("DELETE")
+	admin.Handle("/products/{id}/options", can(adminDomain.PermissionProductsUpdate, productHandler.SetOptions)).Methods("PUT")
+	admin.Handle("/products/{id}/variants/{variantId}", can(adminDomain.PermissionProductsUpdate, productHandler.UpdateVariant)).Methods("PATCH")
+	admin.Handle("/products/{id}/categories", can(adminDomain.PermissionProductsUpdate, productHandler.SetCategories)).Methods("PUT")
+
+	// Category routes (protected)
+	admin.Handle("/categories", can(adminDomain.PermissionProductsView, categoryHandler.Tree)).Methods("GET")
+	admin.Handle("/categories", can(adminDomain.PermissionCatalogManage, categoryHandler.Create)).Methods("POST")
+	admin.Handle("/categories/{id}", can(adminDomain.PermissionProductsView, categoryHandler.GetByID)).Methods("GET")
+	admin.Handle("/categories/{id}", can(adminDomain.PermissionCatalogManage, categoryHandler.Update)).Methods("PATCH")
+	admin.Handle("/categories/{id}", can(adminDomain.PermissionCatalogManage, categoryHandler.Delete)).Methods("DELETE")
+	admin.Handle("/categories/{id}/move", can(adminDomain.PermissionCatalogManage, categoryHandler.Move)).Methods("POST")
+
+	// Collection routes (protected)
+	admin.Handle("/collections", can(adminDomain.PermissionProductsView, collectionHandler.GetAll)).Methods("GET")
+	admin.Handle("/collections", can(adminDomain.PermissionCatalogManage, collectionHandler.Create)).Methods("POST")
+	admin.Handle("/collections/{id}", can(adminDomain.PermissionProductsView, collectionHandler.GetByID)).Methods("GET")
+	admin.Handle("/collections/{id}", can(adminDomain.PermissionCatalogManage, collectionHandler.Update)).Methods("PATCH")
+	admin.Handle("/collections/{id}", can(adminDomain.PermissionCatalogManage, collectionHandler.Delete)).Methods("DELETE")
+	admin.Handle("/collections/{id}/products", can(adminDomain.PermissionProductsView, collectionHandler.ListProducts)).Methods("GET")
+	admin.Handle("/collections/{id}/products", can(adminDomain.PermissionCatalogManage, collectionHandler.SetProducts)).Methods("PUT")
+
+	// Inventory routes (protected)
+	admin.Handle("/inventory/variants/{variantId}", can(adminDomain.PermissionInventoryView, inventoryHandler.Levels)).Methods("GET")
+	admin.Handle("/inventory/variants/{variantId}/movements", can(adminDomain.PermissionInventoryView, inventoryHandler.Movements)).Methods("GET")
+	admin.Handle("/inventory/variants/{variantId}/adjustments", can(adminDomain.PermissionInventoryAdjust, inventoryHandler.Adjust)).Methods("POST")
+	admin.Handle("/inventory/variants/{variantId}/sales", can(adminDomain.PermissionInventorySell, inventoryHandler.Sell)).Methods("POST")
+
+	// Location routes (protected)
+	admin.Handle("/locations", can(adminDomain.PermissionInventoryView, locationHandler.GetAll)).Methods("GET")
+	admin.Handle("/locations", can(adminDomain.PermissionLocationsManage, locationHandler.Create)).Methods("POST")
+	admin.Handle("/locations/{id}", can(adminDomain.PermissionInventoryView, locationHandler.GetByID)).Methods("GET")
+	admin.Handle("/locations/{id}", can(adminDomain.PermissionLocationsManage, locationHandler.Update)).Methods("PATCH")
+
+	// Stock transfer routes (protected)
+	admin.Handle("/transfers", can(adminDomain.PermissionInventoryView, transferHandler.GetAll)).Methods("GET")
+	admin.Handle("/transfers", can(adminDomain.PermissionInventoryTransfer, transferHandler.Create)).Methods("POST")
+	admin.Handle("/transfers/{id}", can(adminDomain.PermissionInventoryView, transferHandler.GetByID)).Methods("GET")
+	admin.Handle("/transfers/{id}/ship", can(adminDomain.PermissionInventoryTransfer, transferHandler.Ship)).Methods("POST")
+	admin.Handle("/transfers/{id}/receive", can(adminDomain.PermissionInventoryTransfer, transferHandler.Receive)).Methods("POST")
+	admin.Handle("/transfers/{id}/cancel", can(adminDomain.PermissionInventoryTransfer, transferHandler.Cancel)).Methods("POST")
 
 	// Dashboard routes (protected)
-	admin.Handle("/dashboard/stats", adminAuth(http.HandlerFunc(dashboardHandler.GetStats))).Methods("GET")
+	admin.Handle("/dashboard/stats", can(adminDomain.PermissionDashboardView, dashboardHandler.GetStats)).Methods("GET")
 
 	// Upload routes (protected)
-	admin.Handle("/upload/avatar", adminAuth(http.HandlerFunc(uploadHandler.UploadAvatar))).Methods("POST")
-	admin.Handle("/upload/avatar/{id}", adminAuth(http.HandlerFunc(uploadHandler.DeleteAvatar))).Methods("DELETE")
+	admin.Handle("/upload/avatar", can(adminDomain.PermissionUploadsManage, uploadHandler.UploadAvatar)).Methods("POST")
+	admin.Handle("/upload/avatar/{id}", can(adminDomain.PermissionUploadsManage, uploadHandler.DeleteAvatar)).Methods("DELETE")
+
+	return nil
 }
@@ -8,13 +8,15 @@ import (
 	"github.com/yeftaz/susano.id/api/internal/handler/shared"
 	"github.com/yeftaz/susano.id/api/internal/middleware"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/mailer"
 )
 
-// New creates and configures the main router
-func New(cfg *config.Config, db *sql.DB, logger *logger.Logger) *mux.Router {
+// New creates and configures the main router; it fails when a service cannot be set up
+func New(cfg *config.Config, db *sql.DB, logger *logger.Logger) (*mux.Router, error) {
 	r := mux.NewRouter()
 
 	// Apply global middleware
+	r.Use(middleware.RealIP(cfg))
 	r.Use(middleware.Recovery(logger))
 	r.Use(middleware.Logger(logger))
 	r.Use(middleware.CORS(cfg))
@@ -28,12 +30,26 @@ func New(cfg *config.Config, db *sql.DB, logger *logger.Logger) *mux.Router {
 	api.HandleFunc("/health", healthHandler.HealthCheck).Methods("GET")
 
 	// Admin routes
-	RegisterAdminRoutes(api, cfg, db, logger)
+	if err := RegisterAdminRoutes(api, cfg, db, logger); err != nil {
+		return nil, err
+	}
 
 	// Store routes
-	RegisterStoreRoutes(api, cfg, db, logger)
+	if err := RegisterStoreRoutes(api, cfg, db, logger); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
 
-	return r
+// newMailer creates the mailer selected by MAIL_DRIVER
+func newMailer(cfg *config.Config, logger *logger.Logger) mailer.Mailer {
+	return mailer.New(mailer.Config{
+		Driver:      cfg.MailDriver,
+		FromAddress: cfg.MailFromAddress,
+		FromName:    cfg.MailFromName,
+		FilePath:    cfg.MailFilePath,
+	}, logger)
 }
 
 // ShowRoutes displays all registered routes (for make routes command)
@@ -56,7 +72,7 @@ func ShowRoutes(r *mux.Router) {
 		middleware := "-"
 		if pathTemplate != "/api/v1/health" {
 			middleware = "RateLimit"
-			if pathTemplate != "/api/v1/admin/auth/login" && pathTemplate != "/api/v1/store/auth/login" && pathTemplate != "/api/v1/store/auth/register" {
+			if !isPublicRoute(pathTemplate) {
 				if len(pathTemplate) > 15 && pathTemplate[:15] == "/api/v1/admin/" {
 					middleware = "RateLimit, AdminAuth"
 				} else if len(pathTemplate) > 15 && pathTemplate[:15] == "/api/v1/store/" {
@@ -75,22 +91,134 @@ func ShowRoutes(r *mux.Router) {
 	println("╚════════╧═══════════════════════════════════════════════════╧═══════════════════════════════╧═══════════════════════╝")
 }
 
+// publicRoutes lists the routes that are not protected by an auth middleware
+var publicRoutes = map[string]bool{
+	"/api/v1/admin/auth/login":                    true,
+	"/api/v1/admin/auth/2fa/verify":               true,
+	"/api/v1/admin/auth/2fa/passkey":              true,
+	"/api/v1/admin/auth/passkey/options":          true,
+	"/api/v1/admin/auth/passkey/login":            true,
+	"/api/v1/admin/auth/forgot-password":          true,
+	"/api/v1/admin/auth/reset-password":           true,
+	"/api/v1/store/auth/login":                    true,
+	"/api/v1/store/auth/register":                 true,
+	"/api/v1/store/auth/2fa/verify":               true,
+	"/api/v1/store/auth/forgot-password":          true,
+	"/api/v1/store/auth/reset-password":           true,
+	"/api/v1/store/auth/email/verify":             true,
+	"/api/v1/store/auth/magic-link":               true,
+	"/api/v1/store/auth/magic-link/verify":        true,
+	"/api/v1/store/auth/oidc/providers":           true,
+	"/api/v1/store/auth/oidc/{provider}":          true,
+	"/api/v1/store/auth/oidc/{provider}/callback": true,
+	"/api/v1/store/products":                      true,
+	"/api/v1/store/products/{slug}":               true,
+	"/api/v1/store/categories":                    true,
+	"/api/v1/store/categories/{slug}/products":    true,
+	"/api/v1/store/collections/{slug}/products":   true,
+}
+
+func isPublicRoute(path string) bool {
+	return publicRoutes[path]
+}
+
 func getHandlerName(path string) string {
 	handlers := map[string]string{
-		"/api/v1/health":                   "HealthCheck",
-		"/api/v1/admin/auth/login":         "Login",
-		"/api/v1/admin/auth/logout":        "Logout",
-		"/api/v1/admin/auth/me":            "GetCurrentUser",
-		"/api/v1/admin/auth/refresh":       "RefreshSession",
-		"/api/v1/admin/admins":             "GetAll/Create",
-		"/api/v1/admin/admins/{id}":        "GetByID/Update/Delete",
-		"/api/v1/admin/dashboard/stats":    "GetStats",
-		"/api/v1/admin/upload/avatar":      "UploadAvatar",
-		"/api/v1/admin/upload/avatar/{id}": "DeleteAvatar",
-		"/api/v1/store/auth/login":         "Login",
-		"/api/v1/store/auth/register":      "Register",
-		"/api/v1/store/auth/logout":        "Logout",
-		"/api/v1/store/profile":            "GetProfile/UpdateProfile",
+		"/api/v1/health":                                           "HealthCheck",
+		"/api/v1/admin/auth/login":                                 "Login",
+		"/api/v1/admin/auth/logout":                                "Logout",
+		"/api/v1/admin/auth/me":                                    "GetCurrentUser",
+		"/api/v1/admin/auth/refresh":                               "RefreshSession",
+		"/api/v1/admin/auth/csrf":                                  "Token",
+		"/api/v1/admin/auth/password":                              "ChangePassword",
+		"/api/v1/admin/auth/forgot-password":                       "ForgotPassword",
+		"/api/v1/admin/auth/reset-password":                        "ResetPassword",
+		"/api/v1/admin/auth/sessions":                              "List/RevokeOthers",
+		"/api/v1/admin/auth/sessions/{id}":                         "Revoke",
+		"/api/v1/admin/auth/2fa/verify":                            "VerifyTwoFactor",
+		"/api/v1/admin/auth/2fa/setup":                             "Setup",
+		"/api/v1/admin/auth/2fa/confirm":                           "Confirm",
+		"/api/v1/admin/auth/2fa/disable":                           "Disable",
+		"/api/v1/admin/auth/2fa/regenerate":                        "Regenerate",
+		"/api/v1/admin/auth/2fa/recovery-codes":                    "RegenerateRecoveryCodes",
+		"/api/v1/admin/auth/2fa/passkey":                           "VerifyTwoFactorPasskey",
+		"/api/v1/admin/auth/passkey/options":                       "LoginOptions",
+		"/api/v1/admin/auth/passkey/login":                         "LoginWithPasskey",
+		"/api/v1/admin/auth/passkeys":                              "List/Register",
+		"/api/v1/admin/auth/passkeys/options":                      "RegistrationOptions",
+		"/api/v1/admin/auth/passkeys/{id}":                         "Delete",
+		"/api/v1/admin/auth/api-keys":                              "List/Create",
+		"/api/v1/admin/auth/api-keys/{id}":                         "Revoke",
+		"/api/v1/admin/admins":                                     "GetAll/Create",
+		"/api/v1/admin/admins/{id}":                                "GetByID/Update/Delete",
+		"/api/v1/admin/admins/{id}/login-history":                  "ListForAdmin",
+		"/api/v1/admin/admins/{id}/sessions":                       "RevokeAll",
+		"/api/v1/admin/admins/{id}/unlock":                         "UnlockAdmin",
+		"/api/v1/admin/admins/{id}/location":                       "AssignAdmin",
+		"/api/v1/admin/permissions":                                "ListPermissions",
+		"/api/v1/admin/roles":                                      "ListRoles",
+		"/api/v1/admin/roles/{role}/permissions":                   "UpdatePermissions",
+		"/api/v1/admin/audit-logs":                                 "GetAll",
+		"/api/v1/admin/audit-logs/export":                          "Export",
+		"/api/v1/admin/customers/{id}/unlock":                      "UnlockCustomer",
+		"/api/v1/admin/products":                                   "GetAll/Create",
+		"/api/v1/admin/products/{id}":                              "GetByID/Update/Delete",
+		"/api/v1/admin/products/{id}/options":                      "SetOptions",
+		"/api/v1/admin/products/{id}/variants/{variantId}":         "UpdateVariant",
+		"/api/v1/admin/products/{id}/categories":                   "SetCategories",
+		"/api/v1/admin/categories":                                 "Tree/Create",
+		"/api/v1/admin/categories/{id}":                            "GetByID/Update/Delete",
+		"/api/v1/admin/categories/{id}/move":                       "Move",
+		"/api/v1/admin/collections":                                "GetAll/Create",
+		"/api/v1/admin/collections/{id}":                           "GetByID/Update/Delete",
+		"/api/v1/admin/collections/{id}/products":                  "ListProducts/SetProducts",
+		"/api/v1/admin/inventory/variants/{variantId}":             "Levels",
+		"/api/v1/admin/inventory/variants/{variantId}/movements":   "Movements",
+		"/api/v1/admin/inventory/variants/{variantId}/adjustments": "Adjust",
+		"/api/v1/admin/inventory/variants/{variantId}/sales":       "Sell",
+		"/api/v1/admin/locations":                                  "GetAll/Create",
+		"/api/v1/admin/locations/{id}":                             "GetByID/Update",
+		"/api/v1/admin/transfers":                                  "GetAll/Create",
+		"/api/v1/admin/transfers/{id}":                             "GetByID",
+		"/api/v1/admin/transfers/{id}/ship":                        "Ship",
+		"/api/v1/admin/transfers/{id}/receive":                     "Receive",
+		"/api/v1/admin/transfers/{id}/cancel":                      "Cancel",
+		"/api/v1/admin/dashboard/stats":                            "GetStats",
+		"/api/v1/admin/upload/avatar":                              "UploadAvatar",
+		"/api/v1/admin/upload/avatar/{id}":                         "DeleteAvatar",
+		"/api/v1/store/auth/login":                                 "Login",
+		"/api/v1/store/auth/register":                              "Register",
+		"/api/v1/store/auth/forgot-password":                       "ForgotPassword",
+		"/api/v1/store/auth/reset-password":                        "ResetPassword",
+		"/api/v1/store/auth/logout":                                "Logout",
+		"/api/v1/store/auth/refresh":                               "RefreshSession",
+		"/api/v1/store/auth/csrf":                                  "Token",
+		"/api/v1/store/auth/password":                              "ChangePassword",
+		"/api/v1/store/auth/sessions":                              "List/RevokeOthers",
+		"/api/v1/store/auth/sessions/{id}":                         "Revoke",
+		"/api/v1/store/auth/2fa/verify":                            "VerifyTwoFactor",
+		"/api/v1/store/auth/2fa/setup":                             "Setup",
+		"/api/v1/store/auth/2fa/confirm":                           "Confirm",
+		"/api/v1/store/auth/2fa/disable":                           "Disable",
+		"/api/v1/store/auth/2fa/regenerate":                        "Regenerate",
+		"/api/v1/store/auth/2fa/recovery-codes":                    "RegenerateRecoveryCodes",
+		"/api/v1/store/auth/email/verify":                          "Verify",
+		"/api/v1/store/auth/email/resend":                          "Resend",
+		"/api/v1/store/auth/magic-link":                            "RequestMagicLink",
+		"/api/v1/store/auth/magic-link/verify":                     "VerifyMagicLink",
+		"/api/v1/store/auth/oidc/providers":                        "OIDCProviders",
+		"/api/v1/store/auth/oidc/{provider}":                       "AuthorizeOIDC",
+		"/api/v1/store/auth/oidc/{provider}/callback":              "OIDCCallback",
+		"/api/v1/store/auth/identities":                            "List",
+		"/api/v1/store/auth/identities/{id}":                       "Unlink",
+		"/api/v1/store/products":                                   "List",
+		"/api/v1/store/products/{slug}":                            "GetBySlug",
+		"/api/v1/store/categories":                                 "Tree",
+		"/api/v1/store/categories/{slug}/products":                 "Products",
+		"/api/v1/store/collections/{slug}/products":                "Products",
+		"/api/v1/store/checkout/reservations":                      "Reserve",
+		"/api/v1/store/checkout/reservations/{id}":                 "Get/Release",
+		"/api/v1/store/profile":                                    "GetProfile/UpdateProfile",
 	}
 
 	if handler, ok := handlers[path]; ok {
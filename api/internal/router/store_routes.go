@@ -6,29 +6,96 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/yeftaz/susano.id/api/internal/config"
+	sharedDomain "github.com/yeftaz/susano.id/api/internal/domain/shared"
+	sharedHandler "github.com/yeftaz/susano.id/api/internal/handler/shared"
 	storeHandler "github.com/yeftaz/susano.id/api/internal/handler/store"
 	"github.com/yeftaz/susano.id/api/internal/middleware"
+	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	catalogRepo "github.com/yeftaz/susano.id/api/internal/repository/catalog"
+	sharedRepo "github.com/yeftaz/susano.id/api/internal/repository/shared"
 	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+	catalogService "github.com/yeftaz/susano.id/api/internal/service/catalog"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
 	storeService "github.com/yeftaz/susano.id/api/internal/service/store"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
 )
 
 // RegisterStoreRoutes registers all store (customer) routes
-func RegisterStoreRoutes(r *mux.Router, cfg *config.Config, db *sql.DB, logger *logger.Logger) {
+func RegisterStoreRoutes(r *mux.Router, cfg *config.Config, db *sql.DB, logger *logger.Logger) error {
 	// Initialize repositories
 	customerRepository := storeRepo.NewCustomerRepository(db)
 	sessionRepository := storeRepo.NewSessionRepository(db)
+	challengeRepository := storeRepo.NewTwoFactorChallengeRepository(db)
+	passwordResetRepository := sharedRepo.NewPasswordResetRepository(db)
+	loginAttemptRepository := sharedRepo.NewLoginAttemptRepository(db)
+	loginHistoryRepository := sharedRepo.NewLoginHistoryRepository(db)
+	passwordHistoryRepository := sharedRepo.NewPasswordHistoryRepository(db)
+	magicLinkRepository := storeRepo.NewMagicLinkRepository(db)
+	identityRepository := storeRepo.NewIdentityRepository(db)
+	oidcStateRepository := storeRepo.NewOIDCStateRepository(db)
+	productRepository := catalogRepo.NewProductRepository(db)
+	categoryRepository := catalogRepo.NewCategoryRepository(db)
+	collectionRepository := catalogRepo.NewCollectionRepository(db)
+	inventoryRepository := catalogRepo.NewInventoryRepository(db)
+	locationRepository := catalogRepo.NewLocationRepository(db)
+	auditLogRepository := adminRepo.NewAuditLogRepository(db)
+
+	// Initialize mailer
+	mail := newMailer(cfg, logger)
 
 	// Initialize services
-	authService := storeService.NewAuthService(customerRepository, sessionRepository)
-	customerService := storeService.NewCustomerService(customerRepository)
+	loginThrottleService := sharedService.NewLoginThrottleService(loginAttemptRepository, sharedService.NewMailLockoutNotifier(mail, logger), logger, cfg)
+	loginHistoryService := sharedService.NewLoginHistoryService(loginHistoryRepository, sharedService.NewMailNewDeviceNotifier(mail, logger), logger)
+	passwordPolicyService, err := sharedService.NewPasswordPolicyService(passwordHistoryRepository, logger, cfg)
+	if err != nil {
+		return err
+	}
+	emailVerificationService := storeService.NewEmailVerificationService(customerRepository, mail, logger, cfg)
+	authService := storeService.NewAuthService(customerRepository, sessionRepository, challengeRepository, loginThrottleService, loginHistoryService, emailVerificationService, passwordPolicyService, logger, cfg)
+	magicLinkService := storeService.NewMagicLinkService(customerRepository, magicLinkRepository, authService, mail, logger, cfg)
+	oidcService := storeService.NewOIDCService(customerRepository, sessionRepository, identityRepository, oidcStateRepository, authService, emailVerificationService, cfg)
+	twoFactorService := sharedService.NewTwoFactorService(storeService.NewTwoFactorAccounts(customerRepository, challengeRepository), cfg)
+	passwordResetService := storeService.NewPasswordResetService(customerRepository, sessionRepository, passwordResetRepository, passwordPolicyService, mail, logger, cfg)
+	sessionService := storeService.NewSessionService(sessionRepository, authService)
+	customerService := storeService.NewCustomerService(customerRepository, emailVerificationService)
+	auditService := adminService.NewAuditService(auditLogRepository)
+	productService := catalogService.NewProductService(productRepository, categoryRepository, auditService)
+	categoryService := catalogService.NewCategoryService(categoryRepository, productRepository, auditService)
+	collectionService := catalogService.NewCollectionService(collectionRepository, productRepository, auditService)
+	inventoryService := catalogService.NewInventoryService(inventoryRepository, locationRepository, auditService, cfg)
 
 	// Initialize handlers
-	authHandler := storeHandler.NewAuthHandler(logger)
-	customerHandler := storeHandler.NewCustomerHandler(logger)
+	authHandler := storeHandler.NewAuthHandler(authService, magicLinkService, oidcService, logger, cfg)
+	customerHandler := storeHandler.NewCustomerHandler(customerService, logger)
+	twoFactorHandler := sharedHandler.NewTwoFactorHandler(twoFactorService, sharedDomain.UserTypeCustomer, logger)
+	passwordResetHandler := storeHandler.NewPasswordResetHandler(passwordResetService, logger)
+	emailVerificationHandler := storeHandler.NewEmailVerificationHandler(emailVerificationService, logger)
+	sessionHandler := storeHandler.NewSessionHandler(sessionService, logger)
+	loginHistoryHandler := storeHandler.NewLoginHistoryHandler(loginHistoryService, logger)
+	identityHandler := storeHandler.NewIdentityHandler(oidcService, logger)
+	productHandler := storeHandler.NewProductHandler(productService, logger)
+	categoryHandler := storeHandler.NewCategoryHandler(categoryService, logger)
+	collectionHandler := storeHandler.NewCollectionHandler(collectionService, logger)
+	reservationHandler := storeHandler.NewReservationHandler(inventoryService, logger)
+	csrfHandler := sharedHandler.NewCSRFHandler()
+
+	// Auth middleware; state-changing requests of cookie sessions must also pass the CSRF check
+	authenticate := middleware.CustomerAuth(authService, cfg, logger)
+	csrf := middleware.CSRF(cfg)
+	customerAuth := func(h http.Handler) http.Handler {
+		return authenticate(csrf(h))
+	}
 
-	// Auth middleware
-	customerAuth := middleware.CustomerAuth(authService, cfg, logger)
+	// sensitive guards routes an impersonating admin must not use, such as credential changes and payments
+	sensitive := func(h http.HandlerFunc) http.Handler {
+		return customerAuth(middleware.BlockImpersonation(h))
+	}
+
+	// checkout guards routes that buy on behalf of the customer, which also needs a verified email
+	checkout := func(h http.HandlerFunc) http.Handler {
+		return customerAuth(middleware.BlockImpersonation(middleware.RequireVerifiedEmail(h)))
+	}
 
 	// Store routes
 	store := r.PathPrefix("/store").Subrouter()
@@ -36,15 +103,55 @@ func RegisterStoreRoutes(r *mux.Router, cfg *config.Config, db *sql.DB, logger *
 	// Auth routes (public)
 	store.HandleFunc("/auth/login", authHandler.Login).Methods("POST")
 	store.HandleFunc("/auth/register", authHandler.Register).Methods("POST")
+	store.HandleFunc("/auth/2fa/verify", authHandler.VerifyTwoFactor).Methods("POST")
+	store.HandleFunc("/auth/magic-link", authHandler.RequestMagicLink).Methods("POST")
+	store.HandleFunc("/auth/magic-link/verify", authHandler.VerifyMagicLink).Methods("POST")
+	store.HandleFunc("/auth/oidc/providers", authHandler.OIDCProviders).Methods("GET")
+	store.HandleFunc("/auth/oidc/{provider}", authHandler.AuthorizeOIDC).Methods("POST")
+	store.HandleFunc("/auth/oidc/{provider}/callback", authHandler.OIDCCallback).Methods("POST")
+	store.HandleFunc("/auth/forgot-password", passwordResetHandler.ForgotPassword).Methods("POST")
+	store.HandleFunc("/auth/reset-password", passwordResetHandler.ResetPassword).Methods("POST")
+	store.HandleFunc("/auth/email/verify", emailVerificationHandler.Verify).Methods("POST")
+
+	// Product catalog routes (public, only active products are listed)
+	store.HandleFunc("/products", productHandler.List).Methods("GET")
+	store.HandleFunc("/products/{slug}", productHandler.GetBySlug).Methods("GET")
+	store.HandleFunc("/categories", categoryHandler.Tree).Methods("GET")
+	store.HandleFunc("/categories/{slug}/products", categoryHandler.Products).Methods("GET")
+	store.HandleFunc("/collections/{slug}/products", collectionHandler.Products).Methods("GET")
 
 	// Auth routes (protected)
 	store.Handle("/auth/logout", customerAuth(http.HandlerFunc(authHandler.Logout))).Methods("POST")
+	store.Handle("/auth/refresh", customerAuth(http.HandlerFunc(authHandler.RefreshSession))).Methods("POST")
+	store.Handle("/auth/csrf", customerAuth(http.HandlerFunc(csrfHandler.Token))).Methods("GET")
+	store.Handle("/auth/password", sensitive(authHandler.ChangePassword)).Methods("POST")
+	store.Handle("/auth/email/resend", sensitive(emailVerificationHandler.Resend)).Methods("POST")
+
+	// Session routes (protected)
+	store.Handle("/auth/sessions", customerAuth(http.HandlerFunc(sessionHandler.List))).Methods("GET")
+	store.Handle("/auth/sessions", sensitive(sessionHandler.RevokeOthers)).Methods("DELETE")
+	store.Handle("/auth/sessions/{id}", sensitive(sessionHandler.Revoke)).Methods("DELETE")
+	store.Handle("/auth/login-history", customerAuth(http.HandlerFunc(loginHistoryHandler.List))).Methods("GET")
+
+	// Linked identity routes (protected)
+	store.Handle("/auth/identities", customerAuth(http.HandlerFunc(identityHandler.List))).Methods("GET")
+	store.Handle("/auth/identities/{id}", sensitive(identityHandler.Unlink)).Methods("DELETE")
+
+	// Two-factor routes (protected)
+	store.Handle("/auth/2fa/setup", sensitive(twoFactorHandler.Setup)).Methods("POST")
+	store.Handle("/auth/2fa/confirm", sensitive(twoFactorHandler.Confirm)).Methods("POST")
+	store.Handle("/auth/2fa/disable", sensitive(twoFactorHandler.Disable)).Methods("POST")
+	store.Handle("/auth/2fa/regenerate", sensitive(twoFactorHandler.Regenerate)).Methods("POST")
+	store.Handle("/auth/2fa/recovery-codes", sensitive(twoFactorHandler.RegenerateRecoveryCodes)).Methods("POST")
+
+	// Checkout reservation routes (protected)
+	store.Handle("/checkout/reservations", checkout(reservationHandler.Reserve)).Methods("POST")
+	store.Handle("/checkout/reservations/{id}", customerAuth(http.HandlerFunc(reservationHandler.Get))).Methods("GET")
+	store.Handle("/checkout/reservations/{id}", customerAuth(http.HandlerFunc(reservationHandler.Release))).Methods("DELETE")
 
 	// Customer profile routes (protected)
 	store.Handle("/profile", customerAuth(http.HandlerFunc(customerHandler.GetProfile))).Methods("GET")
-	store.Handle("/profile", customerAuth(http.HandlerFunc(customerHandler.UpdateProfile))).Methods("PATCH")
+	store.Handle("/profile", sensitive(customerHandler.UpdateProfile)).Methods("PATCH")
 
-	// Suppress unused variable warnings for now
-	_ = authService
-	_ = customerService
+	return nil
 }
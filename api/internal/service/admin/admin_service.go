@@ -3,19 +3,24 @@ package admin
 import (
 	"context"
 
-	"golang.org/x/crypto/bcrypt"
-
+	"github.com/yeftaz/susano.id/api/internal/domain"
 	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
 	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
 )
 
 type AdminService struct {
 	adminRepo *adminRepo.AdminRepository
+	passwords *sharedService.PasswordPolicyService
+	audit     *AuditService
 }
 
-func NewAdminService(adminRepo *adminRepo.AdminRepository) *AdminService {
+func NewAdminService(adminRepo *adminRepo.AdminRepository, passwords *sharedService.PasswordPolicyService, audit *AuditService) *AdminService {
 	return &AdminService{
 		adminRepo: adminRepo,
+		passwords: passwords,
+		audit:     audit,
 	}
 }
 
@@ -47,28 +52,58 @@ func (s *AdminService) GetByID(ctx context.Context, id string) (*admin.Admin, er
 	return admin, nil
 }
 
-// Create creates a new admin
-func (s *AdminService) Create(ctx context.Context, email, password, name, role string) (*admin.Admin, error) {
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// Create creates a new admin with a role no higher than the actor's own
+func (s *AdminService) Create(ctx context.Context, actor admin.Actor, email, password, name, role string) (*admin.Admin, error) {
+	if !admin.AdminRole(role).IsValid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	if !actor.Admin.CanGrant(admin.AdminRole(role)) {
+		return nil, domain.ErrRoleNotGrantable
+	}
+
+	// Check password against the policy and hash it
+	hashedPassword, err := s.passwords.Hash(ctx, shared.UserTypeAdmin, nil, "", password, email, name)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create admin
-	admin, err := s.adminRepo.Create(ctx, email, string(hashedPassword), name, role)
+	// Create admin together with its audit entry
+	var created *admin.Admin
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.adminRepo.Create(ctx, email, hashedPassword, name, role); err != nil {
+			return err
+		}
+
+		// Clear password before recording and returning
+		created.Password = ""
+
+		return s.audit.Record(ctx, actor, admin.AuditActionAdminCreated, admin.AuditEntityAdmin, created.ID.String(), nil, created)
+	})
 	if err != nil {
 		return nil, err
 	}
+	s.passwords.Remember(ctx, shared.UserTypeAdmin, created.ID, hashedPassword)
 
-	// Clear password before returning
-	admin.Password = ""
-
-	return admin, nil
+	return created, nil
 }
 
-// Update updates an admin
-func (s *AdminService) Update(ctx context.Context, id string, email, name, role string) (*admin.Admin, error) {
+// Update updates an admin the actor does not rank below, granting at most the actor's own role
+func (s *AdminService) Update(ctx context.Context, actor admin.Actor, id string, email, name, role string) (*admin.Admin, error) {
+	before, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != "" && !admin.AdminRole(role).IsValid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	if role != "" && !actor.Admin.CanGrant(admin.AdminRole(role)) {
+		return nil, domain.ErrRoleNotGrantable
+	}
+
 	var emailPtr, namePtr, rolePtr *string
 
 	if email != "" {
@@ -81,23 +116,59 @@ func (s *AdminService) Update(ctx context.Context, id string, email, name, role
 		rolePtr = &role
 	}
 
-	admin, err := s.adminRepo.Update(ctx, id, emailPtr, namePtr, rolePtr)
+	var updated *admin.Admin
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.adminRepo.Update(ctx, id, emailPtr, namePtr, rolePtr); err != nil {
+			return err
+		}
+
+		// Clear password before recording and returning
+		updated.Password = ""
+
+		return s.audit.Record(ctx, actor, admin.AuditActionAdminUpdated, admin.AuditEntityAdmin, id, before, updated)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Clear password before returning
-	admin.Password = ""
-
-	return admin, nil
+	return updated, nil
 }
 
-// Delete soft deletes an admin
-func (s *AdminService) Delete(ctx context.Context, id string) error {
-	return s.adminRepo.Delete(ctx, id)
+// Delete soft deletes an admin the actor does not rank below
+func (s *AdminService) Delete(ctx context.Context, actor admin.Actor, id string) error {
+	before, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.adminRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionAdminDeleted, admin.AuditEntityAdmin, id, before, nil)
+	})
 }
 
 // UpdateAvatar updates admin avatar
 func (s *AdminService) UpdateAvatar(ctx context.Context, id, avatarPath string) error {
 	return s.adminRepo.UpdateAvatarPath(ctx, id, avatarPath)
 }
+
+// authorize checks that the target admin does not outrank the actor and returns it
+func (s *AdminService) authorize(ctx context.Context, actor admin.Actor, id string) (*admin.Admin, error) {
+	target, err := s.adminRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !actor.Admin.CanManage(target) {
+		return nil, domain.ErrRoleNotGrantable
+	}
+
+	// Clear password before returning
+	target.Password = ""
+
+	return target, nil
+}
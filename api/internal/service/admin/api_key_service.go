@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	"github.com/yeftaz/susano.id/api/pkg/throttle"
+)
+
+// apiKeyPrefixLength is how much of a key is stored in plain text to tell keys apart
+const apiKeyPrefixLength = 12
+
+type APIKeyService struct {
+	apiKeyRepo  *adminRepo.APIKeyRepository
+	adminRepo   *adminRepo.AdminRepository
+	permissions *PermissionService
+	audit       *AuditService
+	limiter     *throttle.Limiter
+	config      *config.Config
+}
+
+func NewAPIKeyService(apiKeyRepo *adminRepo.APIKeyRepository, adminRepo *adminRepo.AdminRepository, permissions *PermissionService, audit *AuditService, cfg *config.Config) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:  apiKeyRepo,
+		adminRepo:   adminRepo,
+		permissions: permissions,
+		audit:       audit,
+		limiter:     throttle.NewLimiter(time.Minute),
+		config:      cfg,
+	}
+}
+
+// CreatedAPIKey is a new API key along with the key itself, which is only shown once
+type CreatedAPIKey struct {
+	*admin.APIKey
+	Key string `json:"key"`
+}
+
+// List returns the API keys of an admin
+func (s *APIKeyService) List(ctx context.Context, adminID uuid.UUID) ([]*admin.APIKey, error) {
+	return s.apiKeyRepo.FindByAdminID(ctx, adminID)
+}
+
+// Create creates an API key for the acting admin. Scopes are limited to the permissions of the admin's role;
+// a rate limit of zero uses the default.
+func (s *APIKeyService) Create(ctx context.Context, actor admin.Actor, name string, scopes []admin.Permission, expiresAt time.Time, rateLimit int) (*CreatedAPIKey, error) {
+	if !expiresAt.After(time.Now()) || expiresAt.After(time.Now().Add(s.config.APIKeyMaxLifetime)) {
+		return nil, domain.ErrInvalidAPIKeyExpiry
+	}
+
+	// A key can do at most what its owner can do
+	unique := make([]admin.Permission, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, domain.ErrInvalidPermission
+		}
+
+		allowed, err := s.permissions.HasPermission(ctx, actor.Admin, scope)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, domain.ErrAPIKeyScopeDenied
+		}
+
+		if !slices.Contains(unique, scope) {
+			unique = append(unique, scope)
+		}
+	}
+
+	if rateLimit == 0 {
+		rateLimit = s.config.APIKeyRateLimit
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	key := admin.APIKeyPrefix + strings.TrimRight(secret, "=")
+
+	// Only the hash is stored, the plain key is handed to the admin once
+	k := &admin.APIKey{
+		AdminID:   actor.Admin.ID,
+		Name:      name,
+		Prefix:    key[:apiKeyPrefixLength],
+		KeyHash:   hashToken(key),
+		Scopes:    unique,
+		RateLimit: rateLimit,
+		ExpiresAt: expiresAt,
+	}
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.apiKeyRepo.Create(ctx, k); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionAPIKeyCreated, admin.AuditEntityAPIKey, k.ID.String(), nil, k)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreatedAPIKey{APIKey: k, Key: key}, nil
+}
+
+// Revoke revokes an API key of the acting admin
+func (s *APIKeyService) Revoke(ctx context.Context, actor admin.Actor, id uuid.UUID) error {
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.apiKeyRepo.Revoke(ctx, id, actor.Admin.ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrAPIKeyNotFound
+			}
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionAPIKeyRevoked, admin.AuditEntityAPIKey, id.String(), nil, nil)
+	})
+}
+
+// Authenticate verifies an API key, applies its rate limit and returns the owning admin and the key
+func (s *APIKeyService) Authenticate(ctx context.Context, key, ipAddress string) (*admin.Admin, *admin.APIKey, error) {
+	k, err := s.apiKeyRepo.FindByHash(ctx, hashToken(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	if !k.IsUsable() {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+
+	if ok, wait := s.limiter.Allow(k.ID.String(), k.RateLimit); !ok {
+		return nil, nil, &domain.ThrottledError{RetryAfter: wait}
+	}
+
+	a, err := s.adminRepo.FindByID(ctx, k.AdminID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	// Last use is written at most once a minute per key
+	if k.ShouldTouch() {
+		if err := s.apiKeyRepo.UpdateLastUsed(ctx, k.ID, ipAddress); err == nil {
+			now := time.Now()
+			k.LastUsedAt = &now
+			k.LastUsedIP = &ipAddress
+		}
+	}
+
+	// Clear password before returning
+	a.Password = ""
+
+	return a, k, nil
+}
@@ -0,0 +1,198 @@
+package admin
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+)
+
+// auditIgnoredFields are not worth recording as changes since they change on every write
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+type AuditService struct {
+	auditLogRepo *adminRepo.AuditLogRepository
+}
+
+func NewAuditService(auditLogRepo *adminRepo.AuditLogRepository) *AuditService {
+	return &AuditService{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// Transaction runs an audited change in one transaction with its Record call, so the change is
+// rolled back if its entry cannot be written
+func (s *AuditService) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.auditLogRepo.Transaction(ctx, fn)
+}
+
+// Record writes an action by an actor to the audit log. before and after are snapshots of the
+// entity (nil when it did not exist); only the fields that differ between them are stored.
+// Call it inside Transaction together with the change it records.
+func (s *AuditService) Record(ctx context.Context, actor admin.Actor, action admin.AuditAction, entityType, entityID string, before, after interface{}) error {
+	entry := &admin.AuditLog{
+		Action:     action,
+		EntityType: entityType,
+		APIKeyID:   actor.APIKeyID,
+		IPAddress:  actor.IPAddress,
+		UserAgent:  actor.UserAgent,
+	}
+
+	if actor.Admin != nil {
+		entry.ActorID = &actor.Admin.ID
+	}
+	if entityID != "" {
+		entry.EntityID = &entityID
+	}
+
+	oldValues, newValues, err := diff(before, after)
+	if err == nil {
+		entry.OldValues, err = marshalValues(oldValues)
+	}
+	if err == nil {
+		entry.NewValues, err = marshalValues(newValues)
+	}
+	if err == nil {
+		err = s.auditLogRepo.Create(ctx, entry)
+	}
+
+	if err != nil {
+		return fmt.Errorf("write audit log %s of %s %s: %w", action, entityType, entityID, err)
+	}
+	return nil
+}
+
+// GetAll retrieves audit log entries matching the filter with pagination
+func (s *AuditService) GetAll(ctx context.Context, filter admin.AuditLogFilter, page, limit int) ([]*admin.AuditLog, int, error) {
+	return s.auditLogRepo.GetAll(ctx, filter, page, limit)
+}
+
+// ExportCSV writes every audit log entry matching the filter to w as CSV
+func (s *AuditService) ExportCSV(ctx context.Context, filter admin.AuditLogFilter, w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"id", "created_at", "actor_id", "api_key_id", "action", "entity_type", "entity_id", "old_values", "new_values", "ip_address", "user_agent"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	err := s.auditLogRepo.Each(ctx, filter, func(l *admin.AuditLog) error {
+		actorID := ""
+		if l.ActorID != nil {
+			actorID = l.ActorID.String()
+		}
+		apiKeyID := ""
+		if l.APIKeyID != nil {
+			apiKeyID = l.APIKeyID.String()
+		}
+		entityID := ""
+		if l.EntityID != nil {
+			entityID = *l.EntityID
+		}
+
+		return cw.Write([]string{
+			l.ID.String(),
+			l.CreatedAt.UTC().Format(time.RFC3339),
+			actorID,
+			apiKeyID,
+			string(l.Action),
+			l.EntityType,
+			csvSafe(entityID),
+			csvSafe(string(l.OldValues)),
+			csvSafe(string(l.NewValues)),
+			csvSafe(l.IPAddress),
+			csvSafe(l.UserAgent),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// diff returns the fields that differ between two snapshots, as they were before and after
+func diff(before, after interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	oldValues, err := toValues(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	newValues, err := toValues(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Creations and deletions keep the full snapshot
+	if oldValues == nil || newValues == nil {
+		return oldValues, newValues, nil
+	}
+
+	changedOld := map[string]interface{}{}
+	changedNew := map[string]interface{}{}
+	for key := range union(oldValues, newValues) {
+		if auditIgnoredFields[key] || reflect.DeepEqual(oldValues[key], newValues[key]) {
+			continue
+		}
+		changedOld[key] = oldValues[key]
+		changedNew[key] = newValues[key]
+	}
+
+	return changedOld, changedNew, nil
+}
+
+// toValues converts a snapshot to a map of its JSON fields, so hidden fields such as passwords are never recorded
+func toValues(snapshot interface{}) (map[string]interface{}, error) {
+	if snapshot == nil || reflect.ValueOf(snapshot).Kind() == reflect.Ptr && reflect.ValueOf(snapshot).IsNil() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// marshalValues encodes changed values, leaving empty changes out
+func marshalValues(values map[string]interface{}) (json.RawMessage, error) {
+	if values == nil {
+		return nil, nil
+	}
+	return json.Marshal(values)
+}
+
+// union returns the keys of both maps
+func union(a, b map[string]interface{}) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+// csvSafe prevents spreadsheet applications from evaluating user-controlled values as formulas
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -3,103 +3,527 @@ package admin
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
 	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
 	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+	"github.com/yeftaz/susano.id/api/pkg/passkey"
+	"github.com/yeftaz/susano.id/api/pkg/tokenhash"
+	"github.com/yeftaz/susano.id/api/pkg/twofactor"
 )
 
+// maxChallengeAttempts is the number of wrong codes allowed before a challenge is discarded
+const maxChallengeAttempts = 5
+
 type AuthService struct {
-	adminRepo   *adminRepo.AdminRepository
-	sessionRepo *adminRepo.SessionRepository
+	adminRepo     *adminRepo.AdminRepository
+	sessionRepo   *adminRepo.SessionRepository
+	challengeRepo *adminRepo.TwoFactorChallengeRepository
+	throttle      *sharedService.LoginThrottleService
+	history       *sharedService.LoginHistoryService
+	passkeys      *PasskeyService
+	passwords     *sharedService.PasswordPolicyService
+	audit         *AuditService
+	tokens        *tokenhash.Hasher
+	config        *config.Config
 }
 
-func NewAuthService(adminRepo *adminRepo.AdminRepository, sessionRepo *adminRepo.SessionRepository) *AuthService {
+func NewAuthService(adminRepo *adminRepo.AdminRepository, sessionRepo *adminRepo.SessionRepository, challengeRepo *adminRepo.TwoFactorChallengeRepository, throttle *sharedService.LoginThrottleService, history *sharedService.LoginHistoryService, passkeys *PasskeyService, passwords *sharedService.PasswordPolicyService, audit *AuditService, cfg *config.Config) *AuthService {
 	return &AuthService{
-		adminRepo:   adminRepo,
-		sessionRepo: sessionRepo,
+		adminRepo:     adminRepo,
+		sessionRepo:   sessionRepo,
+		challengeRepo: challengeRepo,
+		throttle:      throttle,
+		history:       history,
+		passkeys:      passkeys,
+		passwords:     passwords,
+		audit:         audit,
+		tokens:        tokenhash.New(cfg.SessionTokenKeys),
+		config:        cfg,
 	}
 }
 
-// Login authenticates an admin and creates a session
-func (s *AuthService) Login(ctx context.Context, email, password, ipAddress, userAgent string) (*admin.Admin, *admin.Session, error) {
+// LoginResult is the outcome of a password login.
+// When the admin has 2FA enabled or a passkey registered, Session is nil and Challenge must be completed first,
+// with a code or with one of the passkeys PasskeyOptions asks for.
+type LoginResult struct {
+	Admin          *admin.Admin
+	Session        *admin.Session
+	Challenge      *admin.TwoFactorChallenge
+	PasskeyOptions *passkey.RequestOptions
+}
+
+// RequiresTwoFactor checks if the login must be completed with a two-factor code
+func (r *LoginResult) RequiresTwoFactor() bool {
+	return r.Challenge != nil
+}
+
+// Login authenticates an admin and creates a session, or a two-factor challenge if 2FA is enabled
+func (s *AuthService) Login(ctx context.Context, email, password, ipAddress, userAgent string) (*LoginResult, error) {
+	// Refuse early while the email or IP address is throttled or locked out
+	if err := s.throttle.Check(ctx, shared.UserTypeAdmin, email, ipAddress); err != nil {
+		return nil, err
+	}
+
 	// Find admin by email
 	admin, err := s.adminRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, nil, err
+		if errors.Is(err, sql.ErrNoRows) {
+			// Unknown emails count too, so lockouts do not reveal which accounts exist
+			return nil, s.recordFailure(ctx, email, ipAddress, userAgent)
+		}
+		return nil, err
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)); err != nil {
-		return nil, nil, err
+		s.history.RecordFailure(ctx, shared.UserTypeAdmin, admin.ID, ipAddress, userAgent)
+		return nil, s.recordFailure(ctx, email, ipAddress, userAgent)
 	}
 
 	// Check if admin can access admin panel
 	if !admin.CanAccessAdminPanel() {
-		return nil, nil, err
+		return nil, domain.ErrUserInactive
 	}
 
-	// Generate session token
-	token, err := generateToken()
+	// Clear password before returning
+	admin.Password = ""
+
+	// Registered passkeys act as a second factor next to the authenticator app
+	passkeyOptions, passkeySession, err := s.passkeys.BeginSecondFactor(ctx, admin)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admins with 2FA enabled or a passkey get a challenge instead of a session
+	if admin.HasTwoFactor() || passkeyOptions != nil {
+		challenge, err := s.createChallenge(ctx, admin, ipAddress, userAgent, passkeySession)
+		if err != nil {
+			return nil, err
+		}
+
+		return &LoginResult{Admin: admin, Challenge: challenge, PasskeyOptions: passkeyOptions}, nil
+	}
+
+	session, err := s.createSession(ctx, admin, ipAddress, userAgent)
 	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Admin: admin, Session: session}, nil
+}
+
+// VerifyTwoFactor completes a pending two-factor challenge with either a TOTP code or a recovery code and creates a session
+func (s *AuthService) VerifyTwoFactor(ctx context.Context, challengeToken, code, recoveryCode, ipAddress, userAgent string) (*admin.Admin, *admin.Session, error) {
+	// Find challenge by token hash
+	challenge, err := s.challengeRepo.FindByToken(ctx, hashToken(challengeToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrChallengeNotFound
+		}
 		return nil, nil, err
 	}
 
-	// Create session
-	session, err := s.sessionRepo.Create(ctx, admin.ID, token, ipAddress, userAgent)
+	// Discard challenges that expired or were guessed at too often
+	if challenge.IsExpired() || !challenge.HasAttemptsRemaining(maxChallengeAttempts) {
+		_ = s.challengeRepo.Delete(ctx, challenge.ID)
+		return nil, nil, domain.ErrChallengeExpired
+	}
+
+	// Get admin
+	admin, err := s.adminRepo.FindByID(ctx, challenge.AdminID.String())
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if !admin.CanAccessAdminPanel() {
+		return nil, nil, domain.ErrUserInactive
+	}
+
+	if !admin.HasTwoFactor() {
+		return nil, nil, domain.ErrTwoFactorNotEnabled
+	}
+
+	// A lockout also stops logins that are past the password
+	if err := s.throttle.Check(ctx, shared.UserTypeAdmin, admin.Email, ipAddress); err != nil {
+		return nil, nil, err
+	}
+
+	// Verify code, or burn a recovery code when the authenticator is unavailable
+	if err := s.verifySecondFactor(ctx, admin, code, recoveryCode); err != nil {
+		if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			if err := s.recordSecondFactorFailure(ctx, challenge, admin, ipAddress, userAgent); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, err
+	}
+
+	// Consume challenge (fails if it was used concurrently)
+	if err := s.challengeRepo.Delete(ctx, challenge.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrChallengeNotFound
+		}
+		return nil, nil, err
+	}
+
 	// Clear password before returning
 	admin.Password = ""
 
+	session, err := s.createSession(ctx, admin, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	return admin, session, nil
 }
 
+// VerifyTwoFactorPasskey completes a pending two-factor challenge with a passkey and creates a session
+func (s *AuthService) VerifyTwoFactorPasskey(ctx context.Context, challengeToken string, credential []byte, ipAddress, userAgent string) (*admin.Admin, *admin.Session, error) {
+	// Find challenge by token hash
+	challenge, err := s.challengeRepo.FindByToken(ctx, hashToken(challengeToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrChallengeNotFound
+		}
+		return nil, nil, err
+	}
+
+	// Discard challenges that expired or were guessed at too often
+	if challenge.IsExpired() || !challenge.HasAttemptsRemaining(maxChallengeAttempts) {
+		_ = s.challengeRepo.Delete(ctx, challenge.ID)
+		return nil, nil, domain.ErrChallengeExpired
+	}
+
+	// Challenges issued before a passkey was registered only accept codes
+	if !challenge.AcceptsPasskey() {
+		return nil, nil, domain.ErrInvalidPasskey
+	}
+
+	// Get admin
+	a, err := s.adminRepo.FindByID(ctx, challenge.AdminID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !a.CanAccessAdminPanel() {
+		return nil, nil, domain.ErrUserInactive
+	}
+
+	// A lockout also stops logins that are past the password
+	if err := s.throttle.Check(ctx, shared.UserTypeAdmin, a.Email, ipAddress); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.passkeys.FinishSecondFactor(ctx, a, challenge.WebAuthnSession, credential); err != nil {
+		if errors.Is(err, domain.ErrInvalidPasskey) {
+			if err := s.recordSecondFactorFailure(ctx, challenge, a, ipAddress, userAgent); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, err
+	}
+
+	// Consume challenge (fails if it was used concurrently)
+	if err := s.challengeRepo.Delete(ctx, challenge.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrChallengeNotFound
+		}
+		return nil, nil, err
+	}
+
+	// Clear password before returning
+	a.Password = ""
+
+	session, err := s.createSession(ctx, a, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a, session, nil
+}
+
+// LoginWithPasskey completes a passwordless login with a user-verified passkey and creates a session.
+// The passkey counts as both factors, so no two-factor challenge follows.
+func (s *AuthService) LoginWithPasskey(ctx context.Context, challengeToken string, credential []byte, ipAddress, userAgent string) (*admin.Admin, *admin.Session, error) {
+	a, err := s.passkeys.FinishPasswordless(ctx, challengeToken, credential)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Locked accounts stay locked for passkeys too
+	if err := s.throttle.Check(ctx, shared.UserTypeAdmin, a.Email, ipAddress); err != nil {
+		return nil, nil, err
+	}
+
+	if !a.CanAccessAdminPanel() {
+		return nil, nil, domain.ErrUserInactive
+	}
+
+	// Clear password before returning
+	a.Password = ""
+
+	session, err := s.createSession(ctx, a, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a, session, nil
+}
+
 // Logout deletes an admin session
-func (s *AuthService) Logout(ctx context.Context, token string) error {
-	return s.sessionRepo.Delete(ctx, token)
+func (s *AuthService) Logout(ctx context.Context, actor admin.Actor, token string) error {
+	session, err := s.findSession(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.sessionRepo.Delete(ctx, session.ID); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionLogout, admin.AuditEntityAdmin, session.AdminID.String(), nil, nil)
+	})
 }
 
-// VerifySession verifies a session token and returns the admin
-func (s *AuthService) VerifySession(ctx context.Context, token string, sessionLifetime time.Duration) (*admin.Admin, error) {
-	// Find session by token
-	session, err := s.sessionRepo.FindByToken(ctx, token)
+// ChangePassword sets a new password for the acting admin after checking the current one,
+// and signs out all sessions except the current one
+func (s *AuthService) ChangePassword(ctx context.Context, actor admin.Actor, sessionID uuid.UUID, currentPassword, newPassword string) error {
+	a, err := s.adminRepo.FindByID(ctx, actor.Admin.ID.String())
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Check if session is expired
-	if session.IsExpired(sessionLifetime) {
-		// Delete expired session
-		_ = s.sessionRepo.Delete(ctx, token)
-		return nil, err
+	if err := bcrypt.CompareHashAndPassword([]byte(a.Password), []byte(currentPassword)); err != nil {
+		return domain.ErrInvalidCredentials
+	}
+
+	// Check password against the policy and hash it
+	hashedPassword, err := s.passwords.Hash(ctx, shared.UserTypeAdmin, &a.ID, a.Password, newPassword, a.Email, a.Name)
+	if err != nil {
+		return err
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.adminRepo.UpdatePassword(ctx, a.ID.String(), hashedPassword); err != nil {
+			return err
+		}
+
+		if _, err := s.sessionRepo.DeleteOthers(ctx, a.ID, sessionID); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionPasswordChanged, admin.AuditEntityAdmin, a.ID.String(), nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+	s.passwords.Remember(ctx, shared.UserTypeAdmin, a.ID, hashedPassword)
+
+	return nil
+}
+
+// VerifySession verifies a session token against the session policy and returns the admin and the session
+func (s *AuthService) VerifySession(ctx context.Context, token string) (*admin.Admin, *admin.Session, error) {
+	// Find session by token hash
+	session, err := s.findSession(ctx, token)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Get admin
 	admin, err := s.adminRepo.FindByID(ctx, session.AdminID.String())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Check if session is expired (idle too long or past its lifetime)
+	policy := s.SessionPolicy(admin)
+	if session.IsExpired(policy) {
+		// Delete expired session
+		_ = s.sessionRepo.Delete(ctx, session.ID)
+		return nil, nil, domain.ErrSessionExpired
 	}
 
 	// Update last activity if needed
-	if session.ShouldRefresh() {
-		_ = s.sessionRepo.UpdateLastActivity(ctx, token)
+	if session.ShouldRefresh(policy) {
+		if err := s.sessionRepo.UpdateLastActivity(ctx, session.ID); err == nil {
+			session.LastActivityAt = time.Now()
+		}
 	}
 
 	// Clear password before returning
 	admin.Password = ""
 
-	return admin, nil
+	return admin, session, nil
 }
 
 // RefreshSession updates the last activity timestamp
 func (s *AuthService) RefreshSession(ctx context.Context, token string) error {
-	return s.sessionRepo.UpdateLastActivity(ctx, token)
+	session, err := s.findSession(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return s.sessionRepo.UpdateLastActivity(ctx, session.ID)
+}
+
+// SessionPolicy returns the session policy for an admin; cashiers on shared POS devices idle out sooner
+func (s *AuthService) SessionPolicy(admin *admin.Admin) shared.SessionPolicy {
+	if admin.IsCashier() {
+		return shared.SessionPolicy{
+			IdleTimeout: s.config.CashierSessionIdleTimeout,
+			Lifetime:    s.config.CashierSessionLifetime,
+		}
+	}
+
+	return shared.SessionPolicy{
+		IdleTimeout: s.config.AdminSessionIdleTimeout,
+		Lifetime:    s.config.AdminSessionLifetime,
+	}
+}
+
+// verifySecondFactor checks a TOTP code, or consumes a single-use recovery code if one is given
+func (s *AuthService) verifySecondFactor(ctx context.Context, admin *admin.Admin, code, recoveryCode string) error {
+	if recoveryCode == "" {
+		step, ok := twofactor.Match(code, *admin.TwoFactorSecret)
+		if !ok {
+			return domain.ErrInvalidTwoFactorCode
+		}
+
+		// Each code works once, so an observed code cannot be replayed while it is still valid
+		if err := s.adminRepo.UseTwoFactorStep(ctx, admin.ID.String(), step); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrInvalidTwoFactorCode
+			}
+			return err
+		}
+		return nil
+	}
+
+	remaining, ok := twofactor.ConsumeRecoveryCode(twofactor.DecodeRecoveryCodes(admin.TwoFactorRecoveryCodes), recoveryCode)
+	if !ok {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	encoded, err := twofactor.EncodeRecoveryCodes(remaining)
+	if err != nil {
+		return err
+	}
+
+	// Compare-and-swap so the same code cannot be used by two concurrent logins
+	if err := s.adminRepo.UpdateRecoveryCodes(ctx, admin.ID.String(), admin.TwoFactorRecoveryCodes, encoded); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	admin.TwoFactorRecoveryCodes = &encoded
+
+	return nil
+}
+
+// recordFailure adds a failed login to the ledger and returns the error to report
+func (s *AuthService) recordFailure(ctx context.Context, email, ipAddress, userAgent string) error {
+	if err := s.throttle.RecordFailure(ctx, shared.UserTypeAdmin, email, ipAddress, userAgent); err != nil {
+		return err
+	}
+	return domain.ErrInvalidCredentials
+}
+
+// recordSecondFactorFailure counts a wrong code or passkey against the challenge and, like a wrong
+// password, against the account, so fresh challenges cannot be used to keep guessing
+func (s *AuthService) recordSecondFactorFailure(ctx context.Context, challenge *admin.TwoFactorChallenge, a *admin.Admin, ipAddress, userAgent string) error {
+	_ = s.challengeRepo.IncrementAttempts(ctx, challenge.ID)
+	s.history.RecordFailure(ctx, shared.UserTypeAdmin, a.ID, ipAddress, userAgent)
+	return s.throttle.RecordFailure(ctx, shared.UserTypeAdmin, a.Email, ipAddress, userAgent)
+}
+
+// createSession generates a session token and stores a new session; every new session is an audited login kept in the history.
+// Only a completed login resets the failure count, a correct password alone does not.
+func (s *AuthService) createSession(ctx context.Context, a *admin.Admin, ipAddress, userAgent string) (*admin.Session, error) {
+	if err := s.throttle.RecordSuccess(ctx, shared.UserTypeAdmin, a.Email, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+
+	// Generate session token
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the hash is stored, the plain token is handed to the client once
+	var session *admin.Session
+	actor := admin.Actor{Admin: a, IPAddress: ipAddress, UserAgent: userAgent}
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		session, err = s.sessionRepo.Create(ctx, a.ID, s.tokens.Hash(token), ipAddress, userAgent)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionLogin, admin.AuditEntityAdmin, a.ID.String(), nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	session.Token = token
+	s.history.RecordSuccess(ctx, shared.UserTypeAdmin, a.ID, a.Email, ipAddress, userAgent)
+
+	return session, nil
+}
+
+// findSession looks up a session by the hash of its token under any accepted key
+// and rehashes it with the current key if it was made with an older one
+func (s *AuthService) findSession(ctx context.Context, token string) (*admin.Session, error) {
+	session, err := s.sessionRepo.FindByToken(ctx, s.tokens.Candidates(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.tokens.IsCurrent(token, session.Token) {
+		if err := s.sessionRepo.UpdateToken(ctx, session.ID, s.tokens.Hash(token)); err != nil {
+			return nil, err
+		}
+	}
+
+	return session, nil
+}
+
+// createChallenge generates a challenge token and stores a new two-factor challenge,
+// which also accepts a passkey if the WebAuthn session is set
+func (s *AuthService) createChallenge(ctx context.Context, admin *admin.Admin, ipAddress, userAgent string, webAuthnSession []byte) (*admin.TwoFactorChallenge, error) {
+	// Generate challenge token
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the hash is stored, the plain token is handed to the client once
+	expiresAt := time.Now().Add(s.config.TwoFactorChallengeLifetime)
+	challenge, err := s.challengeRepo.Create(ctx, admin.ID, hashToken(token), ipAddress, userAgent, webAuthnSession, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge.Token = token
+
+	return challenge, nil
 }
 
 // generateToken generates a secure random token
@@ -110,3 +534,9 @@ func generateToken() (string, error) {
 	}
 	return base64.URLEncoding.EncodeToString(b), nil
 }
+
+// hashToken returns the SHA-256 hex digest of a token for storage
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
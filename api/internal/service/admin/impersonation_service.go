@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+	"github.com/yeftaz/susano.id/api/pkg/tokenhash"
+)
+
+type ImpersonationService struct {
+	customerRepo        *storeRepo.CustomerRepository
+	customerSessionRepo *storeRepo.SessionRepository
+	audit               *AuditService
+	tokens              *tokenhash.Hasher
+	config              *config.Config
+}
+
+func NewImpersonationService(customerRepo *storeRepo.CustomerRepository, customerSessionRepo *storeRepo.SessionRepository, audit *AuditService, cfg *config.Config) *ImpersonationService {
+	return &ImpersonationService{
+		customerRepo:        customerRepo,
+		customerSessionRepo: customerSessionRepo,
+		audit:               audit,
+		tokens:              tokenhash.New(cfg.SessionTokenKeys),
+		config:              cfg,
+	}
+}
+
+// Impersonation is a customer session started by an admin, with the plain token to use on the storefront
+type Impersonation struct {
+	Session   *store.Session  `json:"session"`
+	Customer  *store.Customer `json:"customer"`
+	Token     string          `json:"token"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// Start mints a short-lived customer session flagged with the impersonating admin
+func (s *ImpersonationService) Start(ctx context.Context, actor admin.Actor, customerID string) (*Impersonation, error) {
+	// Find customer by ID
+	customer, err := s.customerRepo.FindByID(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if !customer.CanPurchase() {
+		return nil, domain.ErrUserInactive
+	}
+
+	// Generate session token
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the hash is stored, the plain token is handed to the admin once
+	var session *store.Session
+	var expiresAt time.Time
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		session, err = s.customerSessionRepo.CreateImpersonation(ctx, customer.ID, actor.Admin.ID, s.tokens.Hash(token), actor.IPAddress, actor.UserAgent)
+		if err != nil {
+			return err
+		}
+
+		expiresAt = session.CreatedAt.Add(s.config.ImpersonationLifetime)
+		return s.audit.Record(ctx, actor, admin.AuditActionImpersonationStarted, admin.AuditEntityCustomer, customer.ID.String(), nil, map[string]interface{}{
+			"session_id": session.ID,
+			"expires_at": expiresAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Clear password before returning
+	customer.Password = ""
+
+	return &Impersonation{
+		Session:   session,
+		Customer:  customer,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// End deletes an impersonation session the actor started
+func (s *ImpersonationService) End(ctx context.Context, actor admin.Actor, sessionID uuid.UUID) error {
+	err := s.audit.Transaction(ctx, func(ctx context.Context) error {
+		session, err := s.customerSessionRepo.DeleteImpersonation(ctx, sessionID, actor.Admin.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrSessionNotFound
+			}
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionImpersonationEnded, admin.AuditEntityCustomer, session.CustomerID.String(), map[string]interface{}{
+			"session_id": session.ID,
+		}, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+)
+
+type LockoutService struct {
+	adminRepo    *adminRepo.AdminRepository
+	customerRepo *storeRepo.CustomerRepository
+	throttle     *sharedService.LoginThrottleService
+	audit        *AuditService
+}
+
+func NewLockoutService(adminRepo *adminRepo.AdminRepository, customerRepo *storeRepo.CustomerRepository, throttle *sharedService.LoginThrottleService, audit *AuditService) *LockoutService {
+	return &LockoutService{
+		adminRepo:    adminRepo,
+		customerRepo: customerRepo,
+		throttle:     throttle,
+		audit:        audit,
+	}
+}
+
+// UnlockAdmin lifts a login lockout of an admin account
+func (s *LockoutService) UnlockAdmin(ctx context.Context, actor admin.Actor, id string) error {
+	target, err := s.adminRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.throttle.Unlock(ctx, shared.UserTypeAdmin, target.Email, actor.IPAddress, actor.UserAgent); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionAdminUnlocked, admin.AuditEntityAdmin, id, nil, nil)
+	})
+}
+
+// UnlockCustomer lifts a login lockout of a customer account
+func (s *LockoutService) UnlockCustomer(ctx context.Context, actor admin.Actor, id string) error {
+	customer, err := s.customerRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.throttle.Unlock(ctx, shared.UserTypeCustomer, customer.Email, actor.IPAddress, actor.UserAgent); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionCustomerUnlocked, admin.AuditEntityCustomer, id, nil, nil)
+	})
+}
@@ -0,0 +1,324 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	"github.com/yeftaz/susano.id/api/pkg/passkey"
+)
+
+// defaultPasskeyName is used when an admin does not name a new passkey
+const defaultPasskeyName = "Passkey"
+
+type PasskeyService struct {
+	adminRepo     *adminRepo.AdminRepository
+	passkeyRepo   *adminRepo.PasskeyRepository
+	challengeRepo *adminRepo.WebAuthnChallengeRepository
+	relyingParty  *passkey.RelyingParty
+	config        *config.Config
+}
+
+func NewPasskeyService(adminRepo *adminRepo.AdminRepository, passkeyRepo *adminRepo.PasskeyRepository, challengeRepo *adminRepo.WebAuthnChallengeRepository, cfg *config.Config) *PasskeyService {
+	return &PasskeyService{
+		adminRepo:     adminRepo,
+		passkeyRepo:   passkeyRepo,
+		challengeRepo: challengeRepo,
+		relyingParty:  passkey.New(cfg.WebAuthnRPID, cfg.WebAuthnRPName, cfg.WebAuthnOrigins, cfg.WebAuthnChallengeLifetime),
+		config:        cfg,
+	}
+}
+
+// PasskeyRegistration holds the options for navigator.credentials.create() and the token to finish the registration with
+type PasskeyRegistration struct {
+	ChallengeToken string                   `json:"challenge_token"`
+	Options        *passkey.CreationOptions `json:"options"`
+	ExpiresAt      time.Time                `json:"expires_at"`
+}
+
+// PasskeyLogin holds the options for navigator.credentials.get() and the token to finish the login with
+type PasskeyLogin struct {
+	ChallengeToken string                  `json:"challenge_token"`
+	Options        *passkey.RequestOptions `json:"options"`
+	ExpiresAt      time.Time               `json:"expires_at"`
+}
+
+// List returns the passkeys of an admin
+func (s *PasskeyService) List(ctx context.Context, adminID uuid.UUID) ([]*admin.Passkey, error) {
+	return s.passkeyRepo.FindByAdminID(ctx, adminID)
+}
+
+// BeginRegistration starts registering a new passkey after re-verifying the password
+func (s *PasskeyService) BeginRegistration(ctx context.Context, adminID, password, ipAddress, userAgent string) (*PasskeyRegistration, error) {
+	a, err := s.adminRepo.FindByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify password
+	if err := bcrypt.CompareHashAndPassword([]byte(a.Password), []byte(password)); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	user, err := s.user(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	options, session, err := s.relyingParty.BeginRegistration(user)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, token, err := s.createChallenge(ctx, &a.ID, admin.WebAuthnPurposeRegistration, session, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PasskeyRegistration{ChallengeToken: token, Options: options, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// FinishRegistration verifies the browser response to a registration and stores the new passkey
+func (s *PasskeyService) FinishRegistration(ctx context.Context, adminID, challengeToken, name string, response []byte) (*admin.Passkey, error) {
+	a, err := s.adminRepo.FindByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.consumeChallenge(ctx, challengeToken, admin.WebAuthnPurposeRegistration)
+	if err != nil {
+		return nil, err
+	}
+
+	// The challenge must have been issued to this admin
+	if challenge.AdminID == nil || *challenge.AdminID != a.ID {
+		return nil, domain.ErrPasskeyChallengeNotFound
+	}
+
+	user, err := s.user(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.relyingParty.FinishRegistration(user, challenge.Session, response)
+	if err != nil {
+		if errors.Is(err, passkey.ErrInvalidResponse) {
+			return nil, domain.ErrInvalidPasskey
+		}
+		return nil, err
+	}
+
+	if name == "" {
+		name = defaultPasskeyName
+	}
+
+	p := &admin.Passkey{
+		AdminID:         a.ID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      credential.Transports,
+		AAGUID:          credential.AAGUID,
+		SignCount:       credential.SignCount,
+		BackupEligible:  credential.BackupEligible,
+		BackupState:     credential.BackupState,
+		UserVerified:    credential.UserVerified,
+		Attachment:      credential.Attachment,
+	}
+
+	if err := s.passkeyRepo.Create(ctx, p); err != nil {
+		// The same authenticator credential cannot be registered twice
+		if database.IsUniqueViolation(err) {
+			return nil, domain.ErrInvalidPasskey
+		}
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Delete removes a passkey of an admin
+func (s *PasskeyService) Delete(ctx context.Context, adminID, id uuid.UUID) error {
+	if err := s.passkeyRepo.Delete(ctx, id, adminID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrPasskeyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// BeginSecondFactor starts a passkey login for an admin who already entered the password.
+// Returns no options if the admin has no passkeys.
+func (s *PasskeyService) BeginSecondFactor(ctx context.Context, a *admin.Admin) (*passkey.RequestOptions, []byte, error) {
+	user, err := s.user(ctx, a)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(user.Credentials) == 0 {
+		return nil, nil, nil
+	}
+
+	return s.relyingParty.BeginLogin(user)
+}
+
+// FinishSecondFactor verifies the browser response to a second factor passkey login
+func (s *PasskeyService) FinishSecondFactor(ctx context.Context, a *admin.Admin, session, response []byte) error {
+	user, err := s.user(ctx, a)
+	if err != nil {
+		return err
+	}
+
+	credential, err := s.relyingParty.FinishLogin(user, session, response)
+	if err != nil {
+		if errors.Is(err, passkey.ErrInvalidResponse) || errors.Is(err, passkey.ErrCloned) {
+			return domain.ErrInvalidPasskey
+		}
+		return err
+	}
+
+	return s.passkeyRepo.UpdateUsage(ctx, a.ID, credential.ID, credential.SignCount, credential.BackupState)
+}
+
+// BeginPasswordless starts a login with any passkey the browser holds for the admin panel
+func (s *PasskeyService) BeginPasswordless(ctx context.Context, ipAddress, userAgent string) (*PasskeyLogin, error) {
+	options, session, err := s.relyingParty.BeginPasswordlessLogin()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, token, err := s.createChallenge(ctx, nil, admin.WebAuthnPurposeLogin, session, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PasskeyLogin{ChallengeToken: token, Options: options, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// FinishPasswordless verifies the browser response to a passwordless login and returns the admin the passkey belongs to
+func (s *PasskeyService) FinishPasswordless(ctx context.Context, challengeToken string, response []byte) (*admin.Admin, error) {
+	challenge, err := s.consumeChallenge(ctx, challengeToken, admin.WebAuthnPurposeLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	// The passkey carries the admin ID as its user handle
+	var found *admin.Admin
+	var lookupErr error
+	lookup := func(userHandle []byte) (*passkey.User, error) {
+		id, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, err
+		}
+
+		found, lookupErr = s.adminRepo.FindByID(ctx, id.String())
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+
+		user, err := s.user(ctx, found)
+		lookupErr = err
+		return user, err
+	}
+
+	_, credential, err := s.relyingParty.FinishPasswordlessLogin(challenge.Session, response, lookup)
+	if err != nil {
+		if lookupErr != nil && !errors.Is(lookupErr, sql.ErrNoRows) {
+			return nil, lookupErr
+		}
+		if errors.Is(err, passkey.ErrInvalidResponse) || errors.Is(err, passkey.ErrCloned) {
+			return nil, domain.ErrInvalidPasskey
+		}
+		return nil, err
+	}
+
+	if err := s.passkeyRepo.UpdateUsage(ctx, found.ID, credential.ID, credential.SignCount, credential.BackupState); err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// user loads the passkeys of an admin into a WebAuthn user
+func (s *PasskeyService) user(ctx context.Context, a *admin.Admin) (*passkey.User, error) {
+	passkeys, err := s.passkeyRepo.FindByAdminID(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make([]passkey.Credential, len(passkeys))
+	for i, p := range passkeys {
+		credentials[i] = passkey.Credential{
+			ID:              p.CredentialID,
+			PublicKey:       p.PublicKey,
+			AttestationType: p.AttestationType,
+			Transports:      p.Transports,
+			AAGUID:          p.AAGUID,
+			SignCount:       p.SignCount,
+			BackupEligible:  p.BackupEligible,
+			BackupState:     p.BackupState,
+			UserVerified:    p.UserVerified,
+			Attachment:      p.Attachment,
+		}
+	}
+
+	return &passkey.User{
+		ID:          a.ID[:],
+		Name:        a.Email,
+		DisplayName: a.Name,
+		Credentials: credentials,
+	}, nil
+}
+
+// createChallenge stores the ceremony state under the hash of a new token and returns the plain token
+func (s *PasskeyService) createChallenge(ctx context.Context, adminID *uuid.UUID, purpose admin.WebAuthnPurpose, session []byte, ipAddress, userAgent string) (*admin.WebAuthnChallenge, string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	expiresAt := time.Now().Add(s.config.WebAuthnChallengeLifetime)
+	challenge, err := s.challengeRepo.Create(ctx, adminID, purpose, hashToken(token), session, ipAddress, userAgent, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return challenge, token, nil
+}
+
+// consumeChallenge looks up and deletes a challenge, so every ceremony can be finished only once
+func (s *PasskeyService) consumeChallenge(ctx context.Context, token string, purpose admin.WebAuthnPurpose) (*admin.WebAuthnChallenge, error) {
+	challenge, err := s.challengeRepo.FindByToken(ctx, hashToken(token), purpose)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPasskeyChallengeNotFound
+		}
+		return nil, err
+	}
+
+	// Fails if the challenge was used concurrently
+	if err := s.challengeRepo.Delete(ctx, challenge.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPasskeyChallengeNotFound
+		}
+		return nil, err
+	}
+
+	if challenge.IsExpired() {
+		return nil, domain.ErrPasskeyChallengeNotFound
+	}
+
+	return challenge, nil
+}
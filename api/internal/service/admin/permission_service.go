@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"context"
+	"slices"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+)
+
+type PermissionService struct {
+	rolePermissionRepo *adminRepo.RolePermissionRepository
+	audit              *AuditService
+}
+
+func NewPermissionService(rolePermissionRepo *adminRepo.RolePermissionRepository, audit *AuditService) *PermissionService {
+	return &PermissionService{
+		rolePermissionRepo: rolePermissionRepo,
+		audit:              audit,
+	}
+}
+
+// HasPermission checks if an admin's role grants a permission; super admins hold every permission
+func (s *PermissionService) HasPermission(ctx context.Context, a *admin.Admin, permission admin.Permission) (bool, error) {
+	if a.IsSuperAdmin() {
+		return true, nil
+	}
+
+	return s.rolePermissionRepo.HasPermission(ctx, a.Role, permission)
+}
+
+// Registry returns every permission that can be granted
+func (s *PermissionService) Registry() []admin.PermissionDefinition {
+	return admin.Permissions
+}
+
+// ListRoles returns every role with the permissions it is granted
+func (s *PermissionService) ListRoles(ctx context.Context) ([]admin.RolePermissions, error) {
+	roles := make([]admin.RolePermissions, 0, len(admin.Roles))
+	for _, role := range admin.Roles {
+		permissions, err := s.permissionsOf(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, admin.RolePermissions{Role: role, Permissions: permissions})
+	}
+
+	return roles, nil
+}
+
+// UpdateRole replaces the permissions of a role. Access is granted by the roles.update permission,
+// but admins can only edit roles they may grant and only add permissions they hold themselves.
+// The super admin role always keeps every permission.
+func (s *PermissionService) UpdateRole(ctx context.Context, actor admin.Actor, role admin.AdminRole, permissions []admin.Permission) (*admin.RolePermissions, error) {
+	if !role.IsValid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	if role == admin.RoleSuperAdmin {
+		return nil, domain.ErrRoleNotEditable
+	}
+
+	if !actor.Admin.CanGrant(role) {
+		return nil, domain.ErrRoleNotGrantable
+	}
+
+	// Only registered permissions can be granted
+	for _, p := range permissions {
+		if !p.IsValid() {
+			return nil, domain.ErrInvalidPermission
+		}
+	}
+
+	previous, err := s.permissionsOf(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Permissions the actor lacks cannot be added, so nobody grants more than they hold
+	for _, p := range permissions {
+		if slices.Contains(previous, p) {
+			continue
+		}
+		held, err := s.HasPermission(ctx, actor.Admin, p)
+		if err != nil {
+			return nil, err
+		}
+		if !held {
+			return nil, domain.ErrPermissionDenied
+		}
+	}
+
+	before := &admin.RolePermissions{Role: role, Permissions: previous}
+	after := &admin.RolePermissions{Role: role}
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.rolePermissionRepo.Replace(ctx, role, permissions); err != nil {
+			return err
+		}
+
+		granted, err := s.permissionsOf(ctx, role)
+		if err != nil {
+			return err
+		}
+		after.Permissions = granted
+
+		return s.audit.Record(ctx, actor, admin.AuditActionRolePermissionsUpdated, admin.AuditEntityRole, string(role), before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// permissionsOf returns the permissions granted to a role
+func (s *PermissionService) permissionsOf(ctx context.Context, role admin.AdminRole) ([]admin.Permission, error) {
+	if role == admin.RoleSuperAdmin {
+		permissions := make([]admin.Permission, len(admin.Permissions))
+		for i, def := range admin.Permissions {
+			permissions[i] = def.Name
+		}
+		return permissions, nil
+	}
+
+	return s.rolePermissionRepo.FindByRole(ctx, role)
+}
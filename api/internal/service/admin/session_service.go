@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+)
+
+type SessionService struct {
+	adminRepo   *adminRepo.AdminRepository
+	sessionRepo *adminRepo.SessionRepository
+	auth        *AuthService
+	audit       *AuditService
+}
+
+func NewSessionService(adminRepo *adminRepo.AdminRepository, sessionRepo *adminRepo.SessionRepository, auth *AuthService, audit *AuditService) *SessionService {
+	return &SessionService{
+		adminRepo:   adminRepo,
+		sessionRepo: sessionRepo,
+		auth:        auth,
+		audit:       audit,
+	}
+}
+
+// List returns all active sessions of an admin; sessions past the idle timeout or lifetime of
+// the admin's session policy are left out until they are cleaned up
+func (s *SessionService) List(ctx context.Context, a *admin.Admin) ([]*admin.Session, error) {
+	sessions, err := s.sessionRepo.FindByAdminID(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := s.auth.SessionPolicy(a)
+	active := make([]*admin.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if !session.IsExpired(policy) {
+			active = append(active, session)
+		}
+	}
+
+	return active, nil
+}
+
+// Revoke deletes one session of an admin
+func (s *SessionService) Revoke(ctx context.Context, adminID, sessionID uuid.UUID) error {
+	if err := s.sessionRepo.DeleteByID(ctx, sessionID, adminID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrSessionNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RevokeOthers deletes all sessions of an admin except the current one and returns how many were revoked
+func (s *SessionService) RevokeOthers(ctx context.Context, adminID, currentSessionID uuid.UUID) (int64, error) {
+	return s.sessionRepo.DeleteOthers(ctx, adminID, currentSessionID)
+}
+
+// RevokeAll deletes every session of an admin the actor does not rank below, signing them out
+// on all devices
+func (s *SessionService) RevokeAll(ctx context.Context, actor admin.Actor, adminID string) error {
+	// Find admin by ID
+	target, err := s.adminRepo.FindByID(ctx, adminID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	if !actor.Admin.CanManage(target) {
+		return domain.ErrRoleNotGrantable
+	}
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.sessionRepo.DeleteByAdminID(ctx, target.ID); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionAdminSessionsRevoked, admin.AuditEntityAdmin, adminID, nil, nil)
+	})
+}
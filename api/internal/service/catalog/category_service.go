@@ -0,0 +1,220 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	catalogRepo "github.com/yeftaz/susano.id/api/internal/repository/catalog"
+	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+)
+
+// CategoryInput holds the fields of a category to create or update; nil fields are left unchanged on update
+type CategoryInput struct {
+	Name        *string
+	Slug        *string
+	Description *string
+	ParentID    *uuid.UUID // Only used on create; Move changes the parent later
+}
+
+type CategoryService struct {
+	categoryRepo *catalogRepo.CategoryRepository
+	productRepo  *catalogRepo.ProductRepository
+	audit        *adminService.AuditService
+}
+
+func NewCategoryService(categoryRepo *catalogRepo.CategoryRepository, productRepo *catalogRepo.ProductRepository, audit *adminService.AuditService) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		audit:        audit,
+	}
+}
+
+// Tree retrieves all categories nested under their parents
+func (s *CategoryService) Tree(ctx context.Context) ([]*catalog.Category, error) {
+	categories, err := s.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return catalog.BuildCategoryTree(categories), nil
+}
+
+// GetByID retrieves a category
+func (s *CategoryService) GetByID(ctx context.Context, id uuid.UUID) (*catalog.Category, error) {
+	c, err := s.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Create creates a category as the last child of its parent, or as a root without one;
+// the slug is derived from the name when not given
+func (s *CategoryService) Create(ctx context.Context, actor admin.Actor, input CategoryInput) (*catalog.Category, error) {
+	c := &catalog.Category{}
+
+	var parentPath string
+	if input.ParentID != nil {
+		parent, err := s.GetByID(ctx, *input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		c.ParentID, c.Depth, parentPath = &parent.ID, parent.Depth+1, parent.Path
+	}
+
+	if err := applyCategory(c, input); err != nil {
+		return nil, err
+	}
+
+	err := s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Create(ctx, c, parentPath); err != nil {
+			return uniqueError(err)
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionCategoryCreated, admin.AuditEntityCategory, c.ID.String(), nil, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Update changes the given fields of a category; its place in the tree is changed with Move
+func (s *CategoryService) Update(ctx context.Context, actor admin.Actor, id uuid.UUID, input CategoryInput) (*catalog.Category, error) {
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *c
+
+	if err := applyCategory(c, input); err != nil {
+		return nil, err
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Update(ctx, c); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrCategoryNotFound
+			}
+			return uniqueError(err)
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionCategoryUpdated, admin.AuditEntityCategory, id.String(), &before, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Move moves a category with its subtree under a new parent, or to the root without one, at the
+// given position among its new siblings. Slugs are not affected, so storefront URLs keep working.
+func (s *CategoryService) Move(ctx context.Context, actor admin.Actor, id uuid.UUID, parentID *uuid.UUID, position int) (*catalog.Category, error) {
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *c
+
+	var parent *catalog.Category
+	if parentID != nil {
+		parent, err = s.GetByID(ctx, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		if c.Contains(parent) {
+			return nil, domain.ErrInvalidCategoryParent
+		}
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Move(ctx, c, parent, position); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrCategoryNotFound
+			}
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionCategoryMoved, admin.AuditEntityCategory, id.String(), &before, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Delete deletes a category without subcategories; its products stay in the catalog
+func (s *CategoryService) Delete(ctx context.Context, actor admin.Actor, id uuid.UUID) error {
+	before, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Delete(ctx, id); err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			// Nothing was deleted, either because of subcategories or because it is already gone
+			hasChildren, err := s.categoryRepo.HasChildren(ctx, id)
+			if err != nil {
+				return err
+			}
+			if hasChildren {
+				return domain.ErrCategoryHasChildren
+			}
+			return domain.ErrCategoryNotFound
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionCategoryDeleted, admin.AuditEntityCategory, id.String(), before, nil)
+	})
+}
+
+// ListPublishedProducts retrieves the storefront products of a category and all its descendants
+func (s *CategoryService) ListPublishedProducts(ctx context.Context, slug string, page, limit int) (*catalog.Category, []*catalog.Product, int, error) {
+	c, err := s.categoryRepo.FindBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, 0, domain.ErrCategoryNotFound
+		}
+		return nil, nil, 0, err
+	}
+
+	products, total, err := listPublished(ctx, s.productRepo, page, limit, catalog.ProductFilter{CategoryPath: c.Path})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return c, products, total, nil
+}
+
+// applyCategory copies the given input fields onto a category and checks the result
+func applyCategory(c *catalog.Category, input CategoryInput) error {
+	if input.Name != nil {
+		c.Name = *input.Name
+	}
+	if input.Description != nil {
+		c.Description = *input.Description
+	}
+
+	c.Slug = resolveSlug(input.Slug, c.Slug, c.Name)
+	if !catalog.IsValidSlug(c.Slug) {
+		return domain.ErrInvalidSlug
+	}
+
+	return nil
+}
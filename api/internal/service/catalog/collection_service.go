@@ -0,0 +1,234 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	catalogRepo "github.com/yeftaz/susano.id/api/internal/repository/catalog"
+	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+)
+
+// CollectionInput holds the fields of a collection to create or update; nil fields are left
+// unchanged on update, and a nil Rules slice keeps the current rules
+type CollectionInput struct {
+	Name        *string
+	Slug        *string
+	Description *string
+	Type        catalog.CollectionType // Only used on create, a collection cannot change its type
+	Match       *catalog.CollectionMatch
+	Rules       []catalog.CollectionRule
+}
+
+type CollectionService struct {
+	collectionRepo *catalogRepo.CollectionRepository
+	productRepo    *catalogRepo.ProductRepository
+	audit          *adminService.AuditService
+}
+
+func NewCollectionService(collectionRepo *catalogRepo.CollectionRepository, productRepo *catalogRepo.ProductRepository, audit *adminService.AuditService) *CollectionService {
+	return &CollectionService{
+		collectionRepo: collectionRepo,
+		productRepo:    productRepo,
+		audit:          audit,
+	}
+}
+
+// GetAll retrieves all collections
+func (s *CollectionService) GetAll(ctx context.Context) ([]*catalog.Collection, error) {
+	return s.collectionRepo.GetAll(ctx)
+}
+
+// GetByID retrieves a collection, with the products of a manual collection
+func (s *CollectionService) GetByID(ctx context.Context, id uuid.UUID) (*catalog.Collection, error) {
+	c, err := s.collectionRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCollectionNotFound
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Create creates a collection; the slug is derived from the name when not given and products must match all rules by default
+func (s *CollectionService) Create(ctx context.Context, actor admin.Actor, input CollectionInput) (*catalog.Collection, error) {
+	if !input.Type.IsValid() {
+		return nil, domain.ErrInvalidCollectionRule
+	}
+
+	c := &catalog.Collection{
+		Type:  input.Type,
+		Match: catalog.CollectionMatchAll,
+		Rules: []catalog.CollectionRule{},
+	}
+	if err := applyCollection(c, input); err != nil {
+		return nil, err
+	}
+
+	err := s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.collectionRepo.Create(ctx, c); err != nil {
+			return uniqueError(err)
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionCollectionCreated, admin.AuditEntityCollection, c.ID.String(), nil, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Update changes the given fields of a collection
+func (s *CollectionService) Update(ctx context.Context, actor admin.Actor, id uuid.UUID, input CollectionInput) (*catalog.Collection, error) {
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *c
+
+	if err := applyCollection(c, input); err != nil {
+		return nil, err
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.collectionRepo.Update(ctx, c); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrCollectionNotFound
+			}
+			return uniqueError(err)
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionCollectionUpdated, admin.AuditEntityCollection, id.String(), &before, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// SetProducts replaces the products picked for a manual collection, in the given order
+func (s *CollectionService) SetProducts(ctx context.Context, actor admin.Actor, id uuid.UUID, productIDs []uuid.UUID) (*catalog.Collection, error) {
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.IsAutomated() {
+		return nil, domain.ErrCollectionNotManual
+	}
+
+	productIDs = uniqueIDs(productIDs)
+	found, err := s.productRepo.CountByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	if found != len(productIDs) {
+		return nil, domain.ErrProductNotFound
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.collectionRepo.SetProducts(ctx, id, productIDs); err != nil {
+			return err
+		}
+
+		before := *c
+		c.ProductIDs = productIDs
+
+		return s.audit.Record(ctx, actor, admin.AuditActionCollectionUpdated, admin.AuditEntityCollection, id.String(), &before, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Delete deletes a collection; its products stay in the catalog
+func (s *CollectionService) Delete(ctx context.Context, actor admin.Actor, id uuid.UUID) error {
+	before, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.collectionRepo.Delete(ctx, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrCollectionNotFound
+			}
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionCollectionDeleted, admin.AuditEntityCollection, id.String(), before, nil)
+	})
+}
+
+// ListProducts retrieves the products of a collection of any status, e.g. to preview its rules
+func (s *CollectionService) ListProducts(ctx context.Context, id uuid.UUID, page, limit int) ([]*catalog.Product, int, error) {
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.productRepo.GetAll(ctx, page, limit, catalog.ProductFilter{Collection: c})
+}
+
+// ListPublishedProducts retrieves the storefront products of a collection
+func (s *CollectionService) ListPublishedProducts(ctx context.Context, slug string, page, limit int) (*catalog.Collection, []*catalog.Product, int, error) {
+	c, err := s.collectionRepo.FindBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, 0, domain.ErrCollectionNotFound
+		}
+		return nil, nil, 0, err
+	}
+
+	products, total, err := listPublished(ctx, s.productRepo, page, limit, catalog.ProductFilter{Collection: c})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return c, products, total, nil
+}
+
+// applyCollection copies the given input fields onto a collection and checks the result
+func applyCollection(c *catalog.Collection, input CollectionInput) error {
+	if input.Name != nil {
+		c.Name = *input.Name
+	}
+	if input.Description != nil {
+		c.Description = *input.Description
+	}
+	if input.Match != nil {
+		c.Match = *input.Match
+	}
+	if input.Rules != nil {
+		c.Rules = input.Rules
+	}
+
+	c.Slug = resolveSlug(input.Slug, c.Slug, c.Name)
+	if !catalog.IsValidSlug(c.Slug) {
+		return domain.ErrInvalidSlug
+	}
+
+	if c.Match != catalog.CollectionMatchAll && c.Match != catalog.CollectionMatchAny {
+		return domain.ErrInvalidCollectionRule
+	}
+	if !c.IsAutomated() && len(c.Rules) > 0 {
+		return domain.ErrInvalidCollectionRule
+	}
+	for _, rule := range c.Rules {
+		if !rule.IsValid() {
+			return domain.ErrInvalidCollectionRule
+		}
+	}
+
+	return nil
+}
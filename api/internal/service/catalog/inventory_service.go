@@ -0,0 +1,308 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	catalogRepo "github.com/yeftaz/susano.id/api/internal/repository/catalog"
+	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+)
+
+// AdjustmentInput holds a stock movement recorded by hand
+type AdjustmentInput struct {
+	LocationID uuid.UUID
+	Type       catalog.MovementType // Receipt, return or adjustment
+	Quantity   int                  // Signed, see MovementType.AllowsQuantity
+	Reason     string
+	Reference  *string
+}
+
+type InventoryService struct {
+	inventoryRepo *catalogRepo.InventoryRepository
+	locationRepo  *catalogRepo.LocationRepository
+	audit         *adminService.AuditService
+	config        *config.Config
+}
+
+func NewInventoryService(inventoryRepo *catalogRepo.InventoryRepository, locationRepo *catalogRepo.LocationRepository, audit *adminService.AuditService, cfg *config.Config) *InventoryService {
+	return &InventoryService{
+		inventoryRepo: inventoryRepo,
+		locationRepo:  locationRepo,
+		audit:         audit,
+		config:        cfg,
+	}
+}
+
+// Level retrieves the on hand, reserved, available and incoming stock of a variant at a location
+func (s *InventoryService) Level(ctx context.Context, variantID, locationID uuid.UUID) (*catalog.StockLevel, error) {
+	if _, err := findLocation(ctx, s.locationRepo, locationID); err != nil {
+		return nil, err
+	}
+
+	level, err := s.inventoryRepo.Level(ctx, variantID, locationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrVariantNotFound
+		}
+		return nil, err
+	}
+
+	return level, nil
+}
+
+// Levels retrieves the stock of a variant at every location
+func (s *InventoryService) Levels(ctx context.Context, variantID uuid.UUID) ([]*catalog.StockLevel, error) {
+	levels, err := s.inventoryRepo.Levels(ctx, variantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrVariantNotFound
+		}
+		return nil, err
+	}
+
+	return levels, nil
+}
+
+// Movements retrieves the stock ledger of a variant with pagination, newest first, optionally
+// only the movements at one location
+func (s *InventoryService) Movements(ctx context.Context, variantID uuid.UUID, locationID *uuid.UUID, page, limit int) ([]*catalog.StockMovement, int, error) {
+	if err := s.checkVariant(ctx, variantID); err != nil {
+		return nil, 0, err
+	}
+	if locationID != nil {
+		if _, err := findLocation(ctx, s.locationRepo, *locationID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return s.inventoryRepo.GetMovements(ctx, variantID, locationID, page, limit)
+}
+
+// Adjust records a receipt, return or adjustment of a variant at a location by an admin.
+// Adjustments may take reserved stock, as a count reflects what is really on the shelf, but never
+// more than is on hand. Inactive locations can still be adjusted to clear out what is left there.
+func (s *InventoryService) Adjust(ctx context.Context, actor admin.Actor, variantID uuid.UUID, input AdjustmentInput) (*catalog.StockMovement, *catalog.StockLevel, error) {
+	if !input.Type.IsManual() || !input.Type.AllowsQuantity(input.Quantity) {
+		return nil, nil, domain.ErrInvalidMovement
+	}
+
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, nil, domain.ErrRequiredField
+	}
+
+	before, err := s.Level(ctx, variantID, input.LocationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := &catalog.StockMovement{
+		VariantID:  variantID,
+		LocationID: input.LocationID,
+		Type:       input.Type,
+		Quantity:   input.Quantity,
+		Reason:     reason,
+		Reference:  input.Reference,
+		ActorID:    &actor.Admin.ID,
+	}
+	var after *catalog.StockLevel
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.inventoryRepo.Record(ctx, m, true); err != nil {
+			return s.recordError(ctx, variantID, err)
+		}
+
+		var err error
+		after, err = s.Level(ctx, variantID, input.LocationID)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionStockAdjusted, admin.AuditEntityVariant, variantID.String(), before,
+			map[string]interface{}{"movement": m, "level": after})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return m, after, nil
+}
+
+// Sell records a sale of a variant at the till. The stock is taken from the home location of the
+// actor unless another location is given, which only admins above cashiers may do. The variant is
+// locked while the sale is checked against the available stock, so two tills cannot both sell
+// the last item.
+func (s *InventoryService) Sell(ctx context.Context, actor admin.Actor, variantID uuid.UUID, quantity int, locationID *uuid.UUID, reference *string) (*catalog.StockMovement, *catalog.StockLevel, error) {
+	if quantity <= 0 {
+		return nil, nil, domain.ErrInvalidMovement
+	}
+
+	location, err := s.saleLocation(ctx, actor, locationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := &catalog.StockMovement{
+		VariantID:  variantID,
+		LocationID: location.ID,
+		Type:       catalog.MovementTypeSale,
+		Quantity:   -quantity,
+		Reference:  reference,
+		ActorID:    &actor.Admin.ID,
+	}
+	if err := s.inventoryRepo.Record(ctx, m, false); err != nil {
+		return nil, nil, s.recordError(ctx, variantID, err)
+	}
+
+	level, err := s.Level(ctx, variantID, location.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return m, level, nil
+}
+
+// Reserve holds stock of enabled variants of active products at the location fulfilling online
+// orders during the checkout of a customer. A customer holds one reservation at a time, reserving
+// again releases the previous one. Either every item is reserved or none is.
+// There is no order placement yet, so a reservation only ends by being released or by expiring and
+// online sales are not written to the ledger.
+func (s *InventoryService) Reserve(ctx context.Context, customerID uuid.UUID, items []catalog.ReservationItem) (*catalog.Reservation, error) {
+	items = catalog.MergeReservationItems(items)
+
+	variantIDs := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		if item.Quantity <= 0 {
+			return nil, domain.ErrInvalidInput
+		}
+		variantIDs[i] = item.VariantID
+	}
+
+	count, err := s.inventoryRepo.CountSellable(ctx, variantIDs)
+	if err != nil {
+		return nil, err
+	}
+	if count != len(variantIDs) {
+		return nil, domain.ErrVariantNotFound
+	}
+
+	location, err := s.locationRepo.FindOnline(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoOnlineLocation
+		}
+		return nil, err
+	}
+
+	res := &catalog.Reservation{
+		CustomerID: &customerID,
+		LocationID: location.ID,
+		Status:     catalog.ReservationStatusActive,
+		Items:      items,
+		ExpiresAt:  time.Now().Add(s.config.StockReservationLifetime),
+	}
+	if err := s.inventoryRepo.Reserve(ctx, res); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInsufficientStock
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetReservation retrieves a reservation of a customer
+func (s *InventoryService) GetReservation(ctx context.Context, customerID, id uuid.UUID) (*catalog.Reservation, error) {
+	res, err := s.inventoryRepo.FindReservation(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrReservationNotFound
+		}
+		return nil, err
+	}
+
+	// Reservations of other customers are not revealed
+	if res.CustomerID == nil || *res.CustomerID != customerID {
+		return nil, domain.ErrReservationNotFound
+	}
+
+	return res, nil
+}
+
+// Release releases a reservation of a customer so its stock is available again
+func (s *InventoryService) Release(ctx context.Context, customerID, id uuid.UUID) error {
+	res, err := s.GetReservation(ctx, customerID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.inventoryRepo.ReleaseReservation(ctx, res); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrReservationInactive
+		}
+		return err
+	}
+
+	return nil
+}
+
+// saleLocation resolves the active location a sale by the actor takes stock from: the requested
+// one, or the home location of the actor when none is requested. Cashiers can only sell from
+// their home location.
+func (s *InventoryService) saleLocation(ctx context.Context, actor admin.Actor, requested *uuid.UUID) (*catalog.Location, error) {
+	home := actor.Admin.LocationID
+
+	var id uuid.UUID
+	switch {
+	case requested == nil && home == nil:
+		return nil, domain.ErrNoHomeLocation
+	case requested == nil:
+		id = *home
+	case actor.Admin.IsCashier() && (home == nil || *requested != *home):
+		return nil, domain.ErrLocationNotAllowed
+	default:
+		id = *requested
+	}
+
+	location, err := findLocation(ctx, s.locationRepo, id)
+	if err != nil {
+		return nil, err
+	}
+	if !location.IsActive {
+		return nil, domain.ErrLocationInactive
+	}
+
+	return location, nil
+}
+
+// checkVariant checks that a variant exists
+func (s *InventoryService) checkVariant(ctx context.Context, variantID uuid.UUID) error {
+	exists, err := s.inventoryRepo.HasVariant(ctx, variantID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrVariantNotFound
+	}
+	return nil
+}
+
+// recordError maps a failed ledger entry to the variant missing or its stock not sufficing
+func (s *InventoryService) recordError(ctx context.Context, variantID uuid.UUID, err error) error {
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err := s.checkVariant(ctx, variantID); err != nil {
+		return err
+	}
+	return domain.ErrInsufficientStock
+}
@@ -0,0 +1,197 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	catalogRepo "github.com/yeftaz/susano.id/api/internal/repository/catalog"
+	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+)
+
+// LocationInput holds the fields of a location to create or update; nil fields are left unchanged on update
+type LocationInput struct {
+	Name           *string
+	Code           *string
+	Type           *catalog.LocationType
+	Address        *string
+	FulfillsOnline *bool // Taking over online orders from the location fulfilling them before
+	IsActive       *bool
+}
+
+type LocationService struct {
+	locationRepo *catalogRepo.LocationRepository
+	adminRepo    *adminRepo.AdminRepository
+	audit        *adminService.AuditService
+}
+
+func NewLocationService(locationRepo *catalogRepo.LocationRepository, adminRepo *adminRepo.AdminRepository, audit *adminService.AuditService) *LocationService {
+	return &LocationService{
+		locationRepo: locationRepo,
+		adminRepo:    adminRepo,
+		audit:        audit,
+	}
+}
+
+// GetAll retrieves all locations
+func (s *LocationService) GetAll(ctx context.Context) ([]*catalog.Location, error) {
+	return s.locationRepo.GetAll(ctx)
+}
+
+// GetByID retrieves a location
+func (s *LocationService) GetByID(ctx context.Context, id uuid.UUID) (*catalog.Location, error) {
+	return findLocation(ctx, s.locationRepo, id)
+}
+
+// Create creates an active location
+func (s *LocationService) Create(ctx context.Context, actor admin.Actor, input LocationInput) (*catalog.Location, error) {
+	l := &catalog.Location{IsActive: true}
+	if err := applyLocation(l, input); err != nil {
+		return nil, err
+	}
+
+	err := s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.locationRepo.Create(ctx, l); err != nil {
+			return uniqueError(err)
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionLocationCreated, admin.AuditEntityLocation, l.ID.String(), nil, l)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Update changes the given fields of a location. Locations are deactivated instead of deleted;
+// their stock stays in the ledger and can still be adjusted or transferred away.
+func (s *LocationService) Update(ctx context.Context, actor admin.Actor, id uuid.UUID, input LocationInput) (*catalog.Location, error) {
+	l, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *l
+
+	if err := applyLocation(l, input); err != nil {
+		return nil, err
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.locationRepo.Update(ctx, l); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrLocationNotFound
+			}
+			return uniqueError(err)
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionLocationUpdated, admin.AuditEntityLocation, id.String(), &before, l)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// AssignAdmin sets the home location of an admin the actor does not rank below, or clears it
+// when locationID is nil. Sales the admin records at the till take stock from the home location.
+func (s *LocationService) AssignAdmin(ctx context.Context, actor admin.Actor, adminID uuid.UUID, locationID *uuid.UUID) (*admin.Admin, error) {
+	target, err := s.adminRepo.FindByID(ctx, adminID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !actor.Admin.CanManage(target) {
+		return nil, domain.ErrRoleNotGrantable
+	}
+	target.Password = ""
+	before := *target
+
+	if locationID != nil {
+		l, err := s.GetByID(ctx, *locationID)
+		if err != nil {
+			return nil, err
+		}
+		if !l.IsActive {
+			return nil, domain.ErrLocationInactive
+		}
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.adminRepo.UpdateLocation(ctx, adminID.String(), locationID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+		target.LocationID = locationID
+
+		return s.audit.Record(ctx, actor, admin.AuditActionAdminLocationAssigned, admin.AuditEntityAdmin, adminID.String(), &before, target)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// findLocation retrieves a location, mapping a missing one to ErrLocationNotFound
+func findLocation(ctx context.Context, locationRepo *catalogRepo.LocationRepository, id uuid.UUID) (*catalog.Location, error) {
+	l, err := locationRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrLocationNotFound
+		}
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// applyLocation copies the given input fields onto a location and checks the result
+func applyLocation(l *catalog.Location, input LocationInput) error {
+	if input.Name != nil {
+		l.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Code != nil {
+		l.Code = strings.ToUpper(strings.TrimSpace(*input.Code))
+	}
+	if input.Type != nil {
+		l.Type = *input.Type
+	}
+	if input.Address != nil {
+		l.Address = *input.Address
+	}
+	if input.FulfillsOnline != nil {
+		l.FulfillsOnline = *input.FulfillsOnline
+	}
+	if input.IsActive != nil {
+		l.IsActive = *input.IsActive
+	}
+
+	if l.Name == "" {
+		return domain.ErrRequiredField
+	}
+	if !catalog.IsValidLocationCode(l.Code) {
+		return domain.ErrInvalidLocationCode
+	}
+	if !l.Type.IsValid() {
+		return domain.ErrInvalidLocationType
+	}
+	// Online orders cannot be fulfilled from an inactive location
+	if l.FulfillsOnline && !l.IsActive {
+		return domain.ErrLocationInactive
+	}
+
+	return nil
+}
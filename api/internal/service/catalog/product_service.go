@@ -0,0 +1,416 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	catalogRepo "github.com/yeftaz/susano.id/api/internal/repository/catalog"
+	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+)
+
+// ProductInput holds the fields of a product to create or update; nil fields are left unchanged
+// on update, and nil Tags and Images slices keep the current tags and images
+type ProductInput struct {
+	Name        *string
+	Slug        *string
+	Description *string
+	Status      *catalog.ProductStatus
+	Tags        []string
+	Images      []catalog.ProductImage
+
+	// Options and Price build the variant matrix of a new product, Price being the price of each
+	// variant; they are ignored on update, where SetOptions changes the matrix
+	Options []catalog.ProductOption
+	Price   int64
+}
+
+// VariantInput holds the fields of a variant to update; nil fields are left unchanged and an empty barcode clears it
+type VariantInput struct {
+	SKU       *string
+	Price     *int64
+	Barcode   *string
+	Weight    *int
+	IsEnabled *bool
+}
+
+type ProductService struct {
+	productRepo  *catalogRepo.ProductRepository
+	categoryRepo *catalogRepo.CategoryRepository
+	audit        *adminService.AuditService
+}
+
+func NewProductService(productRepo *catalogRepo.ProductRepository, categoryRepo *catalogRepo.CategoryRepository, audit *adminService.AuditService) *ProductService {
+	return &ProductService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		audit:        audit,
+	}
+}
+
+// GetAll retrieves products of any status with pagination and filtering
+func (s *ProductService) GetAll(ctx context.Context, page, limit int, filter catalog.ProductFilter) ([]*catalog.Product, int, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, 0, domain.ErrInvalidProductStatus
+	}
+
+	return s.productRepo.GetAll(ctx, page, limit, filter)
+}
+
+// GetByID retrieves a product of any status
+func (s *ProductService) GetByID(ctx context.Context, id uuid.UUID) (*catalog.Product, error) {
+	p, err := s.productRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// ListPublished retrieves the products listed on the storefront with pagination, search and a tag filter.
+// Only the enabled variants of the products are included.
+func (s *ProductService) ListPublished(ctx context.Context, page, limit int, search, tag string) ([]*catalog.Product, int, error) {
+	return listPublished(ctx, s.productRepo, page, limit, catalog.ProductFilter{Search: search, Tag: tag})
+}
+
+// GetPublishedBySlug retrieves a product listed on the storefront; drafts and archived products are not found
+func (s *ProductService) GetPublishedBySlug(ctx context.Context, slug string) (*catalog.Product, error) {
+	p, err := s.productRepo.FindBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	if !p.IsPublished() {
+		return nil, domain.ErrProductNotFound
+	}
+
+	p.Variants = p.EnabledVariants()
+
+	return p, nil
+}
+
+// Create creates a product with the variant matrix of its options; the slug is derived from the name
+// when not given and new products are drafts by default
+func (s *ProductService) Create(ctx context.Context, actor admin.Actor, input ProductInput) (*catalog.Product, error) {
+	p := &catalog.Product{
+		Status:      catalog.ProductStatusDraft,
+		Tags:        []string{},
+		CategoryIDs: []uuid.UUID{},
+		Images:      []catalog.ProductImage{},
+		Options:     []catalog.ProductOption{},
+	}
+	if err := apply(p, input); err != nil {
+		return nil, err
+	}
+
+	if input.Options != nil {
+		p.Options = input.Options
+	}
+	if catalog.VariantCount(p.Options) > catalog.MaxVariants {
+		return nil, domain.ErrTooManyVariants
+	}
+	p.Variants = catalog.BuildVariants(p.Slug, p.Options, nil, input.Price)
+
+	err := s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Create(ctx, p); err != nil {
+			return uniqueError(err)
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionProductCreated, admin.AuditEntityProduct, p.ID.String(), nil, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Update changes the given fields of a product
+func (s *ProductService) Update(ctx context.Context, actor admin.Actor, id uuid.UUID, input ProductInput) (*catalog.Product, error) {
+	p, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshot the product before changing it for the audit log
+	before := *p
+	before.Tags = append([]string{}, p.Tags...)
+	before.Images = append([]catalog.ProductImage{}, p.Images...)
+
+	if err := apply(p, input); err != nil {
+		return nil, err
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Update(ctx, p); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return uniqueError(err)
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionProductUpdated, admin.AuditEntityProduct, id.String(), &before, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// SetOptions replaces the options of a product and rebuilds its variant matrix. Variants of
+// combinations that remain keep their SKU, price and stock; new combinations get the given price.
+// Combinations with stock on hand or reserved cannot be removed, their stock must be adjusted first.
+func (s *ProductService) SetOptions(ctx context.Context, actor admin.Actor, id uuid.UUID, options []catalog.ProductOption, price int64) (*catalog.Product, error) {
+	if catalog.VariantCount(options) > catalog.MaxVariants {
+		return nil, domain.ErrTooManyVariants
+	}
+
+	p, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := map[string]interface{}{"options": p.Options, "variants": p.Variants}
+
+	p.Options = options
+	p.Variants = catalog.BuildVariants(p.Slug, options, p.Variants, price)
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.SaveOptions(ctx, p); err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return uniqueError(err)
+			}
+			// Nothing was saved, either because the product is gone or because removed variants hold stock
+			if _, err := s.GetByID(ctx, id); err != nil {
+				return err
+			}
+			return domain.ErrVariantHasStock
+		}
+
+		after := map[string]interface{}{"options": p.Options, "variants": p.Variants}
+		return s.audit.Record(ctx, actor, admin.AuditActionProductOptionsUpdated, admin.AuditEntityProduct, id.String(), before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// UpdateVariant changes the given fields of a variant of a product
+func (s *ProductService) UpdateVariant(ctx context.Context, actor admin.Actor, productID, variantID uuid.UUID, input VariantInput) (*catalog.ProductVariant, error) {
+	p, err := s.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := p.Variant(variantID)
+	if !ok {
+		return nil, domain.ErrVariantNotFound
+	}
+	before := *v
+
+	if input.SKU != nil {
+		v.SKU = *input.SKU
+	}
+	if input.Price != nil {
+		v.Price = *input.Price
+	}
+	if input.Barcode != nil {
+		v.Barcode = input.Barcode
+		if *input.Barcode == "" {
+			v.Barcode = nil
+		}
+	}
+	if input.Weight != nil {
+		v.Weight = *input.Weight
+	}
+	if input.IsEnabled != nil {
+		v.IsEnabled = *input.IsEnabled
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.UpdateVariant(ctx, productID, v); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrVariantNotFound
+			}
+			return uniqueError(err)
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionVariantUpdated, admin.AuditEntityVariant, variantID.String(), &before, v)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// SetCategories replaces the categories a product is assigned to
+func (s *ProductService) SetCategories(ctx context.Context, actor admin.Actor, id uuid.UUID, categoryIDs []uuid.UUID) (*catalog.Product, error) {
+	p, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs = uniqueIDs(categoryIDs)
+	found, err := s.categoryRepo.CountByIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	if found != len(categoryIDs) {
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.SetCategories(ctx, id, categoryIDs); err != nil {
+			return err
+		}
+
+		before := p.CategoryIDs
+		p.CategoryIDs = categoryIDs
+
+		return s.audit.Record(ctx, actor, admin.AuditActionProductCategorized, admin.AuditEntityProduct, id.String(),
+			map[string]interface{}{"category_ids": before}, map[string]interface{}{"category_ids": categoryIDs})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Delete soft deletes a product, which frees its slug
+func (s *ProductService) Delete(ctx context.Context, actor admin.Actor, id uuid.UUID) error {
+	before, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Delete(ctx, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionProductDeleted, admin.AuditEntityProduct, id.String(), before, nil)
+	})
+}
+
+// uniqueError maps a violated unique index of the catalog to its domain error
+func uniqueError(err error) error {
+	switch {
+	case database.IsUniqueViolationOn(err, "idx_products_slug"):
+		return domain.ErrProductSlugTaken
+	case database.IsUniqueViolationOn(err, "idx_product_variants_sku"):
+		return domain.ErrVariantSKUTaken
+	case database.IsUniqueViolationOn(err, "categories_slug_key"):
+		return domain.ErrCategorySlugTaken
+	case database.IsUniqueViolationOn(err, "collections_slug_key"):
+		return domain.ErrCollectionSlugTaken
+	case database.IsUniqueViolationOn(err, "locations_code_key"):
+		return domain.ErrLocationCodeTaken
+	}
+	return err
+}
+
+// apply copies the given input fields onto a product and checks the result
+func apply(p *catalog.Product, input ProductInput) error {
+	if input.Name != nil {
+		p.Name = *input.Name
+	}
+	if input.Description != nil {
+		p.Description = *input.Description
+	}
+	if input.Status != nil {
+		p.Status = *input.Status
+	}
+	if input.Tags != nil {
+		p.Tags = normalizeTags(input.Tags)
+	}
+	if input.Images != nil {
+		p.Images = input.Images
+	}
+
+	p.Slug = resolveSlug(input.Slug, p.Slug, p.Name)
+
+	if !p.Status.IsValid() {
+		return domain.ErrInvalidProductStatus
+	}
+	if !catalog.IsValidSlug(p.Slug) {
+		return domain.ErrInvalidSlug
+	}
+
+	return nil
+}
+
+// resolveSlug picks the requested slug, or keeps the current one, or derives one from the name
+func resolveSlug(requested *string, current, name string) string {
+	if requested != nil && *requested != "" {
+		return *requested
+	}
+	if current != "" {
+		return current
+	}
+	return catalog.Slugify(name)
+}
+
+// listPublished retrieves active products matching the filter with only their enabled variants
+func listPublished(ctx context.Context, productRepo *catalogRepo.ProductRepository, page, limit int, filter catalog.ProductFilter) ([]*catalog.Product, int, error) {
+	filter.Status = catalog.ProductStatusActive
+
+	products, total, err := productRepo.GetAll(ctx, page, limit, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, p := range products {
+		p.Variants = p.EnabledVariants()
+	}
+
+	return products, total, nil
+}
+
+// normalizeTags trims tags and drops empty and repeated ones, keeping their order
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// uniqueIDs drops repeated IDs, keeping their order
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := []uuid.UUID{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
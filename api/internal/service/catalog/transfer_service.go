@@ -0,0 +1,183 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/admin"
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+	catalogRepo "github.com/yeftaz/susano.id/api/internal/repository/catalog"
+	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+)
+
+// TransferInput holds the fields of a transfer order to create
+type TransferInput struct {
+	FromLocationID uuid.UUID
+	ToLocationID   uuid.UUID
+	Note           string
+	Items          []catalog.TransferItem
+}
+
+type TransferService struct {
+	transferRepo *catalogRepo.TransferRepository
+	locationRepo *catalogRepo.LocationRepository
+	audit        *adminService.AuditService
+}
+
+func NewTransferService(transferRepo *catalogRepo.TransferRepository, locationRepo *catalogRepo.LocationRepository, audit *adminService.AuditService) *TransferService {
+	return &TransferService{
+		transferRepo: transferRepo,
+		locationRepo: locationRepo,
+		audit:        audit,
+	}
+}
+
+// GetAll retrieves transfer orders with pagination and filters
+func (s *TransferService) GetAll(ctx context.Context, page, limit int, filter catalog.TransferFilter) ([]*catalog.TransferOrder, int, error) {
+	return s.transferRepo.GetAll(ctx, page, limit, filter)
+}
+
+// GetByID retrieves a transfer order with its items
+func (s *TransferService) GetByID(ctx context.Context, id uuid.UUID) (*catalog.TransferOrder, error) {
+	t, err := s.transferRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrTransferNotFound
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Create creates a draft transfer order moving stock from one location to another, active one.
+// No stock moves until the transfer is shipped.
+func (s *TransferService) Create(ctx context.Context, actor admin.Actor, input TransferInput) (*catalog.TransferOrder, error) {
+	if input.FromLocationID == input.ToLocationID {
+		return nil, domain.ErrInvalidTransfer
+	}
+
+	if _, err := findLocation(ctx, s.locationRepo, input.FromLocationID); err != nil {
+		return nil, err
+	}
+	to, err := findLocation(ctx, s.locationRepo, input.ToLocationID)
+	if err != nil {
+		return nil, err
+	}
+	if !to.IsActive {
+		return nil, domain.ErrLocationInactive
+	}
+
+	items := catalog.MergeTransferItems(input.Items)
+	if len(items) == 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, domain.ErrInvalidInput
+		}
+	}
+
+	t := &catalog.TransferOrder{
+		FromLocationID: input.FromLocationID,
+		ToLocationID:   input.ToLocationID,
+		Status:         catalog.TransferStatusDraft,
+		Note:           input.Note,
+		Items:          items,
+		CreatedBy:      &actor.Admin.ID,
+	}
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.transferRepo.Create(ctx, t); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrVariantNotFound
+			}
+			return err
+		}
+
+		return s.audit.Record(ctx, actor, admin.AuditActionTransferCreated, admin.AuditEntityTransfer, t.ID.String(), nil, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Ship takes the stock of a draft transfer from its source, where it must be available, and puts
+// it in transit
+func (s *TransferService) Ship(ctx context.Context, actor admin.Actor, id uuid.UUID) (*catalog.TransferOrder, error) {
+	t, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !t.CanShip() {
+		return nil, domain.ErrTransferStatus
+	}
+
+	return s.transition(ctx, actor, t, catalog.TransferStatusInTransit, &t.FromLocationID, -1, "Transfer shipped", admin.AuditActionTransferShipped)
+}
+
+// Receive adds the stock of a transfer in transit at its destination
+func (s *TransferService) Receive(ctx context.Context, actor admin.Actor, id uuid.UUID) (*catalog.TransferOrder, error) {
+	t, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !t.CanReceive() {
+		return nil, domain.ErrTransferStatus
+	}
+
+	return s.transition(ctx, actor, t, catalog.TransferStatusReceived, &t.ToLocationID, 1, "Transfer received", admin.AuditActionTransferReceived)
+}
+
+// Cancel cancels a transfer that was not received; the stock of a transfer in transit goes back to its source
+func (s *TransferService) Cancel(ctx context.Context, actor admin.Actor, id uuid.UUID) (*catalog.TransferOrder, error) {
+	t, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !t.CanCancel() {
+		return nil, domain.ErrTransferStatus
+	}
+
+	var locationID *uuid.UUID
+	if t.Status == catalog.TransferStatusInTransit {
+		locationID = &t.FromLocationID
+	}
+
+	return s.transition(ctx, actor, t, catalog.TransferStatusCancelled, locationID, 1, "Transfer cancelled", admin.AuditActionTransferCancelled)
+}
+
+// transition moves a transfer on to the next status, recording its movements at the given location
+func (s *TransferService) transition(ctx context.Context, actor admin.Actor, t *catalog.TransferOrder, to catalog.TransferStatus, locationID *uuid.UUID, sign int, reason string, action admin.AuditAction) (*catalog.TransferOrder, error) {
+	before := *t
+	from := t.Status
+
+	err := s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.transferRepo.Transition(ctx, t, from, to, locationID, sign, reason, &actor.Admin.ID); err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			// Nothing moved, either because the transfer moved on meanwhile or because the stock did not suffice
+			current, err := s.GetByID(ctx, t.ID)
+			if err != nil {
+				return err
+			}
+			if current.Status != from {
+				return domain.ErrTransferStatus
+			}
+			return domain.ErrInsufficientStock
+		}
+
+		return s.audit.Record(ctx, actor, action, admin.AuditEntityTransfer, t.ID.String(), &before, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
@@ -0,0 +1,42 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/mailer"
+)
+
+// MailLockoutNotifier emails the account owner when their account gets locked
+type MailLockoutNotifier struct {
+	mailer mailer.Mailer
+	logger *logger.Logger
+}
+
+func NewMailLockoutNotifier(mailer mailer.Mailer, logger *logger.Logger) *MailLockoutNotifier {
+	return &MailLockoutNotifier{
+		mailer: mailer,
+		logger: logger,
+	}
+}
+
+// NotifyLockout sends the lockout email in the background
+func (n *MailLockoutNotifier) NotifyLockout(ctx context.Context, userType shared.UserType, email string, lockedUntil time.Time) {
+	msg := mailer.Message{
+		To:      email,
+		Subject: "Your Susano account has been temporarily locked",
+		Body: fmt.Sprintf(
+			"Hi,\n\nWe locked your account after several failed login attempts. You can try again after %s.\n\nIf this was not you, someone may be trying to guess your password. We recommend resetting it once the lock expires.\n",
+			lockedUntil.UTC().Format("2006-01-02 15:04 MST"),
+		),
+	}
+
+	go func() {
+		if err := n.mailer.Send(context.Background(), msg); err != nil {
+			n.logger.Error("Failed to send lockout email", "user_type", userType, "email", email, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mssola/useragent"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	sharedRepo "github.com/yeftaz/susano.id/api/internal/repository/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+)
+
+// NewDeviceNotifier is told when an account logs in from a device or IP address it never used before
+type NewDeviceNotifier interface {
+	NotifyNewDevice(ctx context.Context, userType shared.UserType, email string, login *shared.LoginHistory)
+}
+
+// LoginHistoryService keeps the login history of admins and customers and alerts on new devices
+type LoginHistoryService struct {
+	historyRepo *sharedRepo.LoginHistoryRepository
+	notifier    NewDeviceNotifier
+	logger      *logger.Logger
+}
+
+func NewLoginHistoryService(historyRepo *sharedRepo.LoginHistoryRepository, notifier NewDeviceNotifier, logger *logger.Logger) *LoginHistoryService {
+	return &LoginHistoryService{
+		historyRepo: historyRepo,
+		notifier:    notifier,
+		logger:      logger,
+	}
+}
+
+// RecordSuccess adds a successful login to the history and notifies the account owner
+// if neither the IP address nor the device was used for a successful login before.
+// The very first login of an account is not reported.
+func (s *LoginHistoryService) RecordSuccess(ctx context.Context, userType shared.UserType, userID uuid.UUID, email, ipAddress, userAgent string) {
+	entry := newLoginHistory(userType, userID, shared.LoginAttemptSuccess, ipAddress, userAgent)
+
+	total, fromIP, fromDevice, err := s.historyRepo.CountSuccesses(ctx, userType, userID, ipAddress, entry.Browser, entry.OS, entry.Device)
+	if err != nil {
+		s.logger.Error("Failed to look up login history", "user_type", userType, "user_id", userID, "error", err)
+		return
+	}
+	entry.IsNewDevice = total > 0 && (fromIP == 0 || fromDevice == 0)
+
+	if err := s.historyRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record login history", "user_type", userType, "user_id", userID, "error", err)
+		return
+	}
+
+	if entry.IsNewDevice && s.notifier != nil {
+		s.notifier.NotifyNewDevice(ctx, userType, email, entry)
+	}
+}
+
+// RecordFailure adds a failed login of a known account to the history
+func (s *LoginHistoryService) RecordFailure(ctx context.Context, userType shared.UserType, userID uuid.UUID, ipAddress, userAgent string) {
+	entry := newLoginHistory(userType, userID, shared.LoginAttemptFailure, ipAddress, userAgent)
+
+	if err := s.historyRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record login history", "user_type", userType, "user_id", userID, "error", err)
+	}
+}
+
+// List returns the login history of an account with pagination, newest first
+func (s *LoginHistoryService) List(ctx context.Context, userType shared.UserType, userID uuid.UUID, page, limit int) ([]*shared.LoginHistory, int, error) {
+	return s.historyRepo.FindByUser(ctx, userType, userID, page, limit)
+}
+
+// newLoginHistory builds a history entry with the browser, OS and device parsed from the user agent
+func newLoginHistory(userType shared.UserType, userID uuid.UUID, result shared.LoginAttemptResult, ipAddress, userAgent string) *shared.LoginHistory {
+	ua := useragent.New(userAgent)
+	browser, _ := ua.Browser()
+
+	device := shared.DeviceDesktop
+	switch {
+	case ua.Bot():
+		device = shared.DeviceBot
+	case ua.Mobile():
+		device = shared.DeviceMobile
+	}
+
+	return &shared.LoginHistory{
+		UserType:  userType,
+		UserID:    userID,
+		Result:    result,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Browser:   browser,
+		OS:        ua.OS(),
+		Device:    device,
+	}
+}
@@ -0,0 +1,131 @@
+package shared
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	sharedRepo "github.com/yeftaz/susano.id/api/internal/repository/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+)
+
+// LockoutNotifier is told when an account gets locked after too many failed logins
+type LockoutNotifier interface {
+	NotifyLockout(ctx context.Context, userType shared.UserType, email string, lockedUntil time.Time)
+}
+
+// LoginThrottleService slows down and locks out repeated failed logins per email and per IP
+type LoginThrottleService struct {
+	attemptRepo *sharedRepo.LoginAttemptRepository
+	notifier    LockoutNotifier
+	policy      shared.LoginThrottlePolicy
+	logger      *logger.Logger
+	config      *config.Config
+}
+
+func NewLoginThrottleService(attemptRepo *sharedRepo.LoginAttemptRepository, notifier LockoutNotifier, logger *logger.Logger, cfg *config.Config) *LoginThrottleService {
+	return &LoginThrottleService{
+		attemptRepo: attemptRepo,
+		notifier:    notifier,
+		policy: shared.LoginThrottlePolicy{
+			MaxAttempts:     cfg.LoginMaxAttempts,
+			LockoutDuration: cfg.LoginLockoutDuration,
+			BaseDelay:       cfg.LoginBaseDelay,
+			MaxDelay:        cfg.LoginMaxDelay,
+		},
+		logger: logger,
+		config: cfg,
+	}
+}
+
+// Check returns an error if a login for the email or from the IP address must not be attempted yet
+func (s *LoginThrottleService) Check(ctx context.Context, userType shared.UserType, email, ipAddress string) error {
+	// Per IP, so one address cannot spray many accounts
+	if s.config.LoginMaxAttemptsPerIP > 0 {
+		count, oldest, err := s.attemptRepo.CountFailuresByIP(ctx, ipAddress, s.config.LoginAttemptWindow)
+		if err != nil {
+			return err
+		}
+
+		if count >= s.config.LoginMaxAttemptsPerIP {
+			return &domain.ThrottledError{RetryAfter: retryAfter(oldest.Add(s.config.LoginAttemptWindow))}
+		}
+	}
+
+	// Per account, so a rotating IP pool cannot brute force one account
+	failures, err := s.attemptRepo.FindFailures(ctx, userType, normalizeEmail(email), s.lockoutWindow())
+	if err != nil {
+		return err
+	}
+
+	wait := s.policy.RetryAfter(*failures, time.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	if s.policy.IsLocked(*failures) {
+		return &domain.AccountLockedError{RetryAfter: wait}
+	}
+
+	return &domain.ThrottledError{RetryAfter: wait}
+}
+
+// RecordFailure adds a failed login to the ledger and notifies when it locks the account
+func (s *LoginThrottleService) RecordFailure(ctx context.Context, userType shared.UserType, email, ipAddress, userAgent string) error {
+	email = normalizeEmail(email)
+
+	if err := s.attemptRepo.Create(ctx, userType, email, ipAddress, userAgent, shared.LoginAttemptFailure); err != nil {
+		return err
+	}
+
+	failures, err := s.attemptRepo.FindFailures(ctx, userType, email, s.lockoutWindow())
+	if err != nil {
+		return err
+	}
+
+	// Only the failure that reaches the limit triggers a notification
+	if failures.Count == s.policy.MaxAttempts {
+		lockedUntil := failures.LastAt.Add(s.policy.LockoutDuration)
+		s.logger.Warn("Account locked after failed logins", "user_type", userType, "email", email, "ip_address", ipAddress, "locked_until", lockedUntil)
+
+		if s.notifier != nil {
+			s.notifier.NotifyLockout(ctx, userType, email, lockedUntil)
+		}
+	}
+
+	return nil
+}
+
+// RecordSuccess adds a successful login to the ledger, which resets the failure count
+func (s *LoginThrottleService) RecordSuccess(ctx context.Context, userType shared.UserType, email, ipAddress, userAgent string) error {
+	return s.attemptRepo.Create(ctx, userType, normalizeEmail(email), ipAddress, userAgent, shared.LoginAttemptSuccess)
+}
+
+// Unlock lifts a lockout and resets the failure count of an account
+func (s *LoginThrottleService) Unlock(ctx context.Context, userType shared.UserType, email, ipAddress, userAgent string) error {
+	return s.attemptRepo.Create(ctx, userType, normalizeEmail(email), ipAddress, userAgent, shared.LoginAttemptUnlock)
+}
+
+// lockoutWindow returns how far back failures are counted; a lockout must outlive its window
+func (s *LoginThrottleService) lockoutWindow() time.Duration {
+	if s.policy.LockoutDuration > s.config.LoginAttemptWindow {
+		return s.policy.LockoutDuration
+	}
+	return s.config.LoginAttemptWindow
+}
+
+// retryAfter returns the time left until t, at least one second
+func retryAfter(t time.Time) time.Duration {
+	if wait := time.Until(t); wait > time.Second {
+		return wait
+	}
+	return time.Second
+}
+
+// normalizeEmail makes ledger lookups case-insensitive
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,41 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/mailer"
+)
+
+// MailNewDeviceNotifier emails the account owner when they log in from a new device or IP address
+type MailNewDeviceNotifier struct {
+	mailer mailer.Mailer
+	logger *logger.Logger
+}
+
+func NewMailNewDeviceNotifier(mailer mailer.Mailer, logger *logger.Logger) *MailNewDeviceNotifier {
+	return &MailNewDeviceNotifier{
+		mailer: mailer,
+		logger: logger,
+	}
+}
+
+// NotifyNewDevice sends the new device email in the background
+func (n *MailNewDeviceNotifier) NotifyNewDevice(ctx context.Context, userType shared.UserType, email string, login *shared.LoginHistory) {
+	msg := mailer.Message{
+		To:      email,
+		Subject: "New login to your Susano account",
+		Body: fmt.Sprintf(
+			"Hi,\n\nYour account was just used to log in from a device or location we have not seen before:\n\nDevice: %s\nIP address: %s\nTime: %s\n\nIf this was you, you can ignore this email. If not, please change your password right away and sign out your other sessions.\n",
+			login.DeviceLabel(), login.IPAddress, login.CreatedAt.UTC().Format("2006-01-02 15:04 MST"),
+		),
+	}
+
+	go func() {
+		if err := n.mailer.Send(context.Background(), msg); err != nil {
+			n.logger.Error("Failed to send new device email", "user_type", userType, "email", email, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,131 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	sharedRepo "github.com/yeftaz/susano.id/api/internal/repository/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/password"
+)
+
+// PasswordPolicyService enforces the password policy whenever an admin or customer password is set:
+// composition rules, no personal details, not breached and not reused
+type PasswordPolicyService struct {
+	historyRepo *sharedRepo.PasswordHistoryRepository
+	policy      password.Policy
+	breaches    *password.BreachList
+	history     int
+	logger      *logger.Logger
+}
+
+// NewPasswordPolicyService returns an error when the breached password list is configured but
+// cannot be opened, so passwords are never silently accepted without the check
+func NewPasswordPolicyService(historyRepo *sharedRepo.PasswordHistoryRepository, logger *logger.Logger, cfg *config.Config) (*PasswordPolicyService, error) {
+	s := &PasswordPolicyService{
+		historyRepo: historyRepo,
+		policy: password.Policy{
+			MinLength:        cfg.PasswordMinLength,
+			RequireUppercase: cfg.PasswordRequireUppercase,
+			RequireLowercase: cfg.PasswordRequireLowercase,
+			RequireDigit:     cfg.PasswordRequireDigit,
+			RequireSymbol:    cfg.PasswordRequireSymbol,
+		},
+		history: cfg.PasswordHistory,
+		logger:  logger,
+	}
+
+	if cfg.PasswordBreachListPath != "" {
+		breaches, err := password.OpenBreachList(cfg.PasswordBreachListPath)
+		if err != nil {
+			return nil, fmt.Errorf("open breached password list %s: %w", cfg.PasswordBreachListPath, err)
+		}
+		s.breaches = breaches
+	}
+
+	return s, nil
+}
+
+// Hash checks a new password and returns its bcrypt hash. userID is nil for an account that is
+// being created; currentHash is the account's current password hash, which is never reusable.
+// Personal details such as the email address and name must not appear in the password.
+func (s *PasswordPolicyService) Hash(ctx context.Context, userType shared.UserType, userID *uuid.UUID, currentHash, newPassword string, personal ...string) (string, error) {
+	problems := s.policy.Check(newPassword, personal...)
+
+	if s.breaches != nil {
+		breached, err := s.breaches.Contains(newPassword)
+		if err != nil {
+			return "", err
+		}
+		if breached {
+			problems = append(problems, "has appeared in a data breach, choose another one")
+		}
+	}
+
+	if len(problems) > 0 {
+		return "", &domain.WeakPasswordError{Problems: problems}
+	}
+
+	if userID != nil {
+		reused, err := s.isReused(ctx, userType, *userID, currentHash, newPassword)
+		if err != nil {
+			return "", err
+		}
+		if reused {
+			return "", &domain.WeakPasswordError{Problems: []string{"must not be one of your last " + strconv.Itoa(max(s.history, 1)) + " passwords"}}
+		}
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashed), nil
+}
+
+// Remember adds a password hash that was just set to the history of the account
+func (s *PasswordPolicyService) Remember(ctx context.Context, userType shared.UserType, userID uuid.UUID, hashedPassword string) {
+	if s.history <= 0 {
+		return
+	}
+
+	if err := s.historyRepo.Create(ctx, userType, userID, hashedPassword, s.history); err != nil {
+		s.logger.Error("Failed to record password history", "user_type", userType, "user_id", userID, "error", err)
+	}
+}
+
+// isReused checks the password against the current and the remembered previous passwords of an account
+func (s *PasswordPolicyService) isReused(ctx context.Context, userType shared.UserType, userID uuid.UUID, currentHash, newPassword string) (bool, error) {
+	var hashes []string
+	if currentHash != "" {
+		hashes = append(hashes, currentHash)
+	}
+
+	if s.history > 0 {
+		previous, err := s.historyRepo.FindRecent(ctx, userType, userID, s.history)
+		if err != nil {
+			return false, err
+		}
+		for _, hash := range previous {
+			if hash != currentHash {
+				hashes = append(hashes, hash)
+			}
+		}
+	}
+
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPassword)) == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
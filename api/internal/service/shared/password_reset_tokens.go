@@ -0,0 +1,97 @@
+package shared
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	sharedRepo "github.com/yeftaz/susano.id/api/internal/repository/shared"
+)
+
+// PasswordResetTokens issues and checks the single use password reset tokens of one user type.
+// Only the hash of a token is stored.
+type PasswordResetTokens struct {
+	resetRepo *sharedRepo.PasswordResetRepository
+	userType  shared.UserType
+	appURL    string
+	lifetime  time.Duration
+}
+
+func NewPasswordResetTokens(resetRepo *sharedRepo.PasswordResetRepository, userType shared.UserType, appURL string, lifetime time.Duration) *PasswordResetTokens {
+	return &PasswordResetTokens{
+		resetRepo: resetRepo,
+		userType:  userType,
+		appURL:    appURL,
+		lifetime:  lifetime,
+	}
+}
+
+// Issue stores a new token for the email, replacing any earlier one, and returns the link to
+// the reset page of the app carrying it
+func (t *PasswordResetTokens) Issue(ctx context.Context, email string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.URLEncoding.EncodeToString(b)
+
+	if err := t.resetRepo.Upsert(ctx, email, t.userType, hashResetToken(token)); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/reset-password?%s", t.appURL, url.Values{
+		"token": {token},
+		"email": {email},
+	}.Encode()), nil
+}
+
+// Verify checks that token is the current, unexpired token of the email. An expired token is
+// deleted on the way.
+func (t *PasswordResetTokens) Verify(ctx context.Context, email, token string) error {
+	reset, err := t.resetRepo.FindByEmail(ctx, email, t.userType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(reset.Token), []byte(hashResetToken(token))) != 1 {
+		return domain.ErrInvalidResetToken
+	}
+
+	if reset.IsExpired(t.lifetime) {
+		_ = t.resetRepo.Delete(ctx, email, t.userType)
+		return domain.ErrInvalidResetToken
+	}
+
+	return nil
+}
+
+// Consume deletes token before it is acted on. Only one of concurrent requests with the same
+// token deletes it; the others get ErrInvalidResetToken.
+func (t *PasswordResetTokens) Consume(ctx context.Context, email, token string) error {
+	if err := t.resetRepo.DeleteToken(ctx, email, t.userType, hashResetToken(token)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidResetToken
+		}
+		return err
+	}
+	return nil
+}
+
+// hashResetToken returns the SHA-256 hex digest of a token for storage
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
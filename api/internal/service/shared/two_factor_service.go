@@ -0,0 +1,231 @@
+package shared
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	"github.com/yeftaz/susano.id/api/pkg/twofactor"
+)
+
+// TwoFactorAccounts loads and updates the two-factor data of admins or customers
+type TwoFactorAccounts interface {
+	FindTwoFactorAccount(ctx context.Context, id string) (*shared.TwoFactorAccount, error)
+	UpdatePendingTwoFactorSecret(ctx context.Context, id, secret string) error
+	// ConfirmTwoFactor swaps the pending secret in if it still matches pendingSecret and records
+	// step as used, returning sql.ErrNoRows otherwise
+	ConfirmTwoFactor(ctx context.Context, id, pendingSecret, recoveryCodes string, step int64) error
+	// UseTwoFactorStep records the time step of an accepted code, returning sql.ErrNoRows if
+	// the same or a later step was accepted before
+	UseTwoFactorStep(ctx context.Context, id string, step int64) error
+	DisableTwoFactor(ctx context.Context, id string) error
+	// UpdateRecoveryCodes replaces the recovery codes if they still match current,
+	// returning sql.ErrNoRows otherwise
+	UpdateRecoveryCodes(ctx context.Context, id string, current *string, recoveryCodes string) error
+	// DeleteTwoFactorChallenges drops the pending two-factor logins of the account
+	DeleteTwoFactorChallenges(ctx context.Context, id uuid.UUID) error
+}
+
+// TwoFactorService manages the two-factor setup of admins and customers
+type TwoFactorService struct {
+	accounts TwoFactorAccounts
+	config   *config.Config
+}
+
+func NewTwoFactorService(accounts TwoFactorAccounts, cfg *config.Config) *TwoFactorService {
+	return &TwoFactorService{
+		accounts: accounts,
+		config:   cfg,
+	}
+}
+
+// TwoFactorSetup holds the data an authenticator app needs to enroll
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Setup generates a new two-factor secret that must be confirmed before it is enforced
+func (s *TwoFactorService) Setup(ctx context.Context, userID string) (*TwoFactorSetup, error) {
+	account, err := s.accounts.FindTwoFactorAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.HasTwoFactor() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	return s.issueSecret(ctx, userID, account.Email)
+}
+
+// Confirm enables the pending two-factor secret once the user proves it works, replacing
+// the previous secret after a regeneration. Returns the plain recovery codes, which are shown
+// once and only stored hashed.
+func (s *TwoFactorService) Confirm(ctx context.Context, userID, code string) ([]string, error) {
+	account, err := s.accounts.FindTwoFactorAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !account.IsTwoFactorPending() {
+		if account.HasTwoFactor() {
+			return nil, domain.ErrTwoFactorAlreadyEnabled
+		}
+		return nil, domain.ErrTwoFactorNotInitiated
+	}
+
+	// Verify code against the pending secret
+	step, ok := twofactor.Match(code, *account.PendingSecret)
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	codes, encoded, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare-and-swap so a secret regenerated in the meantime is not enabled unverified
+	if err := s.accounts.ConfirmTwoFactor(ctx, userID, *account.PendingSecret, encoded, step); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidTwoFactorCode
+		}
+		return nil, err
+	}
+
+	// Drop pending logins, which were issued for the previous secret
+	if err := s.accounts.DeleteTwoFactorChallenges(ctx, account.ID); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable turns off two-factor authentication after re-verifying password and code
+func (s *TwoFactorService) Disable(ctx context.Context, userID, password, code string) error {
+	account, err := s.verify(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+
+	// Verify code
+	if err := s.useCode(ctx, account, code); err != nil {
+		return err
+	}
+
+	if err := s.accounts.DisableTwoFactor(ctx, userID); err != nil {
+		return err
+	}
+
+	// Drop any pending logins that would otherwise still ask for a code
+	return s.accounts.DeleteTwoFactorChallenges(ctx, account.ID)
+}
+
+// Regenerate issues a new two-factor secret, e.g. when moving to a new device.
+// The current secret stays enforced until the new one is confirmed the same way as during the initial setup.
+func (s *TwoFactorService) Regenerate(ctx context.Context, userID, password, code string) (*TwoFactorSetup, error) {
+	account, err := s.verify(ctx, userID, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify code against the current secret
+	if err := s.useCode(ctx, account, code); err != nil {
+		return nil, err
+	}
+
+	return s.issueSecret(ctx, userID, account.Email)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes with a new batch after re-verifying the password
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID, password string) ([]string, error) {
+	account, err := s.verify(ctx, userID, password)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, encoded, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.accounts.UpdateRecoveryCodes(ctx, userID, account.RecoveryCodes, encoded); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// verify loads an account with two-factor enabled and checks its password
+func (s *TwoFactorService) verify(ctx context.Context, userID, password string) (*shared.TwoFactorAccount, error) {
+	account, err := s.accounts.FindTwoFactorAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !account.HasTwoFactor() {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(account.Password), []byte(password)); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	return account, nil
+}
+
+// useCode checks a code against the confirmed secret and uses it up, so it cannot be replayed
+func (s *TwoFactorService) useCode(ctx context.Context, account *shared.TwoFactorAccount, code string) error {
+	step, ok := twofactor.Match(code, *account.Secret)
+	if !ok {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	if err := s.accounts.UseTwoFactorStep(ctx, account.ID.String(), step); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	return nil
+}
+
+// issueSecret generates and stores a new secret pending confirmation
+func (s *TwoFactorService) issueSecret(ctx context.Context, userID, email string) (*TwoFactorSetup, error) {
+	secret, err := twofactor.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.accounts.UpdatePendingTwoFactorSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret: secret,
+		URI:    twofactor.URI(s.config.TwoFactorIssuer, email, secret),
+	}, nil
+}
+
+// generateRecoveryCodes returns a new batch of plain recovery codes and their encoded hashes for storage
+func generateRecoveryCodes() ([]string, string, error) {
+	codes, err := twofactor.GenerateRecoveryCodes(twofactor.RecoveryCodeCount)
+	if err != nil {
+		return nil, "", err
+	}
+
+	encoded, err := twofactor.EncodeRecoveryCodes(twofactor.HashRecoveryCodes(codes))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return codes, encoded, nil
+}
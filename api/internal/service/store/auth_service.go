@@ -3,76 +3,272 @@ package store
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
 	"github.com/yeftaz/susano.id/api/internal/domain/store"
 	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/tokenhash"
+	"github.com/yeftaz/susano.id/api/pkg/twofactor"
 )
 
+// maxChallengeAttempts is the number of wrong codes allowed before a challenge is discarded
+const maxChallengeAttempts = 5
+
 type AuthService struct {
-	customerRepo *storeRepo.CustomerRepository
-	sessionRepo  *storeRepo.SessionRepository
+	customerRepo  *storeRepo.CustomerRepository
+	sessionRepo   *storeRepo.SessionRepository
+	challengeRepo *storeRepo.TwoFactorChallengeRepository
+	throttle      *sharedService.LoginThrottleService
+	history       *sharedService.LoginHistoryService
+	verification  *EmailVerificationService
+	passwords     *sharedService.PasswordPolicyService
+	tokens        *tokenhash.Hasher
+	logger        *logger.Logger
+	config        *config.Config
 }
 
-func NewAuthService(customerRepo *storeRepo.CustomerRepository, sessionRepo *storeRepo.SessionRepository) *AuthService {
+func NewAuthService(customerRepo *storeRepo.CustomerRepository, sessionRepo *storeRepo.SessionRepository, challengeRepo *storeRepo.TwoFactorChallengeRepository, throttle *sharedService.LoginThrottleService, history *sharedService.LoginHistoryService, verification *EmailVerificationService, passwords *sharedService.PasswordPolicyService, logger *logger.Logger, cfg *config.Config) *AuthService {
 	return &AuthService{
-		customerRepo: customerRepo,
-		sessionRepo:  sessionRepo,
+		customerRepo:  customerRepo,
+		sessionRepo:   sessionRepo,
+		challengeRepo: challengeRepo,
+		throttle:      throttle,
+		history:       history,
+		verification:  verification,
+		passwords:     passwords,
+		tokens:        tokenhash.New(cfg.SessionTokenKeys),
+		logger:        logger,
+		config:        cfg,
 	}
 }
 
-// Login authenticates a customer and creates a session
-func (s *AuthService) Login(ctx context.Context, email, password, ipAddress, userAgent string) (*store.Customer, *store.Session, error) {
+// LoginResult is the outcome of a password login.
+// When the customer has 2FA enabled, Session is nil and Challenge must be completed first.
+type LoginResult struct {
+	Customer  *store.Customer
+	Session   *store.Session
+	Challenge *store.TwoFactorChallenge
+}
+
+// RequiresTwoFactor checks if the login must be completed with a two-factor code
+func (r *LoginResult) RequiresTwoFactor() bool {
+	return r.Challenge != nil
+}
+
+// Login authenticates a customer and creates a session, or a two-factor challenge if 2FA is enabled
+func (s *AuthService) Login(ctx context.Context, email, password, ipAddress, userAgent string) (*LoginResult, error) {
+	// Refuse early while the email or IP address is throttled or locked out
+	if err := s.throttle.Check(ctx, shared.UserTypeCustomer, email, ipAddress); err != nil {
+		return nil, err
+	}
+
 	// Find customer by email
 	customer, err := s.customerRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, nil, err
+		if errors.Is(err, sql.ErrNoRows) {
+			// Unknown emails count too, so lockouts do not reveal which accounts exist
+			return nil, s.recordFailure(ctx, email, ipAddress, userAgent)
+		}
+		return nil, err
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(customer.Password), []byte(password)); err != nil {
-		return nil, nil, err
+		s.history.RecordFailure(ctx, shared.UserTypeCustomer, customer.ID, ipAddress, userAgent)
+		return nil, s.recordFailure(ctx, email, ipAddress, userAgent)
 	}
 
 	// Check if customer can make purchases
 	if !customer.CanPurchase() {
-		return nil, nil, err
+		return nil, domain.ErrUserInactive
 	}
 
-	// Generate session token
-	token, err := generateToken()
+	// Clear password before returning
+	customer.Password = ""
+
+	// Customers with 2FA enabled get a challenge instead of a session
+	if customer.HasTwoFactor() {
+		challenge, err := s.createChallenge(ctx, customer, ipAddress, userAgent)
+		if err != nil {
+			return nil, err
+		}
+
+		return &LoginResult{Customer: customer, Challenge: challenge}, nil
+	}
+
+	session, err := s.createSession(ctx, customer, ipAddress, userAgent)
 	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Customer: customer, Session: session}, nil
+}
+
+// LoginWithMagicLink logs in a customer who opened a magic link, with a two-factor challenge if 2FA is enabled.
+// Opening the link proves ownership of the email address, so it is marked as verified.
+func (s *AuthService) LoginWithMagicLink(ctx context.Context, customer *store.Customer, ipAddress, userAgent string) (*LoginResult, error) {
+	// A link does not get around a lockout of the email or IP address
+	if err := s.throttle.Check(ctx, shared.UserTypeCustomer, customer.Email, ipAddress); err != nil {
+		return nil, err
+	}
+
+	if !customer.CanPurchase() {
+		return nil, domain.ErrUserInactive
+	}
+
+	if !customer.IsEmailVerified() {
+		if err := s.customerRepo.MarkEmailAsVerified(ctx, customer.ID.String()); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		customer.EmailVerifiedAt = &now
+	}
+
+	return s.completeLogin(ctx, customer, ipAddress, userAgent)
+}
+
+// LoginWithIdentity logs in a customer who authenticated at an identity provider,
+// with a two-factor challenge if 2FA is enabled
+func (s *AuthService) LoginWithIdentity(ctx context.Context, customer *store.Customer, ipAddress, userAgent string) (*LoginResult, error) {
+	// An identity provider does not get around a lockout of the email or IP address
+	if err := s.throttle.Check(ctx, shared.UserTypeCustomer, customer.Email, ipAddress); err != nil {
+		return nil, err
+	}
+
+	if !customer.CanPurchase() {
+		return nil, domain.ErrUserInactive
+	}
+
+	return s.completeLogin(ctx, customer, ipAddress, userAgent)
+}
+
+// completeLogin finishes a login that replaced the password; customers with 2FA enabled still get a challenge
+func (s *AuthService) completeLogin(ctx context.Context, customer *store.Customer, ipAddress, userAgent string) (*LoginResult, error) {
+	// Clear password before returning
+	customer.Password = ""
+
+	if customer.HasTwoFactor() {
+		challenge, err := s.createChallenge(ctx, customer, ipAddress, userAgent)
+		if err != nil {
+			return nil, err
+		}
+
+		return &LoginResult{Customer: customer, Challenge: challenge}, nil
+	}
+
+	session, err := s.createSession(ctx, customer, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Customer: customer, Session: session}, nil
+}
+
+// VerifyTwoFactor completes a pending two-factor challenge with either a TOTP code or a recovery code and creates a session
+func (s *AuthService) VerifyTwoFactor(ctx context.Context, challengeToken, code, recoveryCode, ipAddress, userAgent string) (*store.Customer, *store.Session, error) {
+	// Find challenge by token hash
+	challenge, err := s.challengeRepo.FindByToken(ctx, hashToken(challengeToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrChallengeNotFound
+		}
 		return nil, nil, err
 	}
 
-	// Create session
-	session, err := s.sessionRepo.Create(ctx, customer.ID, token, ipAddress, userAgent)
+	// Discard challenges that expired or were guessed at too often
+	if challenge.IsExpired() || !challenge.HasAttemptsRemaining(maxChallengeAttempts) {
+		_ = s.challengeRepo.Delete(ctx, challenge.ID)
+		return nil, nil, domain.ErrChallengeExpired
+	}
+
+	// Get customer
+	customer, err := s.customerRepo.FindByID(ctx, challenge.CustomerID.String())
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if !customer.CanPurchase() {
+		return nil, nil, domain.ErrUserInactive
+	}
+
+	if !customer.HasTwoFactor() {
+		return nil, nil, domain.ErrTwoFactorNotEnabled
+	}
+
+	// A lockout also stops logins that are past the password
+	if err := s.throttle.Check(ctx, shared.UserTypeCustomer, customer.Email, ipAddress); err != nil {
+		return nil, nil, err
+	}
+
+	// Verify code, or burn a recovery code when the authenticator is unavailable
+	if err := s.verifySecondFactor(ctx, customer, code, recoveryCode); err != nil {
+		if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			// Count against the account too, so fresh challenges cannot be used to keep guessing
+			_ = s.challengeRepo.IncrementAttempts(ctx, challenge.ID)
+			s.history.RecordFailure(ctx, shared.UserTypeCustomer, customer.ID, ipAddress, userAgent)
+			if err := s.throttle.RecordFailure(ctx, shared.UserTypeCustomer, customer.Email, ipAddress, userAgent); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, err
+	}
+
+	// Consume challenge (fails if it was used concurrently)
+	if err := s.challengeRepo.Delete(ctx, challenge.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrChallengeNotFound
+		}
+		return nil, nil, err
+	}
+
 	// Clear password before returning
 	customer.Password = ""
 
+	session, err := s.createSession(ctx, customer, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	return customer, session, nil
 }
 
 // Register creates a new customer account
 func (s *AuthService) Register(ctx context.Context, email, password, name string) (*store.Customer, error) {
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	// Check password against the policy and hash it
+	hashedPassword, err := s.passwords.Hash(ctx, shared.UserTypeCustomer, nil, "", password, email, name)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create customer
-	customer, err := s.customerRepo.Create(ctx, email, string(hashedPassword), name)
+	customer, err := s.customerRepo.Create(ctx, email, hashedPassword, name)
 	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, domain.ErrEmailAlreadyExists
+		}
 		return nil, err
 	}
+	s.passwords.Remember(ctx, shared.UserTypeCustomer, customer.ID, hashedPassword)
+
+	// Send verification link for the new address; the account exists either way and the
+	// customer can ask for the link again
+	if err := s.verification.SendVerificationEmail(ctx, customer); err != nil {
+		s.logger.Error("Failed to send verification email", "customer_id", customer.ID, "error", err)
+	}
 
 	// Clear password before returning
 	customer.Password = ""
@@ -80,46 +276,214 @@ func (s *AuthService) Register(ctx context.Context, email, password, name string
 	return customer, nil
 }
 
-// Logout deletes a customer session
-func (s *AuthService) Logout(ctx context.Context, token string) error {
-	return s.sessionRepo.Delete(ctx, token)
+// ChangePassword sets a new password for a customer after checking the current one,
+// and signs out all sessions except the current one
+func (s *AuthService) ChangePassword(ctx context.Context, customerID, sessionID uuid.UUID, currentPassword, newPassword string) error {
+	customer, err := s.customerRepo.FindByID(ctx, customerID.String())
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(customer.Password), []byte(currentPassword)); err != nil {
+		return domain.ErrInvalidCredentials
+	}
+
+	// Check password against the policy and hash it
+	hashedPassword, err := s.passwords.Hash(ctx, shared.UserTypeCustomer, &customer.ID, customer.Password, newPassword, customer.Email, customer.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.customerRepo.UpdatePassword(ctx, customer.ID.String(), hashedPassword); err != nil {
+		return err
+	}
+	s.passwords.Remember(ctx, shared.UserTypeCustomer, customer.ID, hashedPassword)
+
+	_, err = s.sessionRepo.DeleteOthers(ctx, customer.ID, sessionID)
+	return err
 }
 
-// VerifySession verifies a session token and returns the customer
-func (s *AuthService) VerifySession(ctx context.Context, token string, sessionLifetime time.Duration) (*store.Customer, error) {
-	// Find session by token
-	session, err := s.sessionRepo.FindByToken(ctx, token)
+// Logout deletes a customer session
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	session, err := s.findSession(ctx, token)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Check if session is expired
-	if session.IsExpired(sessionLifetime) {
-		// Delete expired session
-		_ = s.sessionRepo.Delete(ctx, token)
-		return nil, err
+	return s.sessionRepo.Delete(ctx, session.ID)
+}
+
+// VerifySession verifies a session token against the session policy and returns the customer and the session
+func (s *AuthService) VerifySession(ctx context.Context, token string) (*store.Customer, *store.Session, error) {
+	// Find session by token hash
+	session, err := s.findSession(ctx, token)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Get customer
 	customer, err := s.customerRepo.FindByID(ctx, session.CustomerID.String())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Check if session is expired (idle too long or past its lifetime)
+	policy := s.SessionPolicy(customer, session)
+	if session.IsExpired(policy) {
+		// Delete expired session
+		_ = s.sessionRepo.Delete(ctx, session.ID)
+		return nil, nil, domain.ErrSessionExpired
 	}
 
 	// Update last activity if needed
-	if session.ShouldRefresh() {
-		_ = s.sessionRepo.UpdateLastActivity(ctx, token)
+	if session.ShouldRefresh(policy) {
+		if err := s.sessionRepo.UpdateLastActivity(ctx, session.ID); err == nil {
+			session.LastActivityAt = time.Now()
+		}
 	}
 
 	// Clear password before returning
 	customer.Password = ""
 
-	return customer, nil
+	return customer, session, nil
 }
 
 // RefreshSession updates the last activity timestamp
 func (s *AuthService) RefreshSession(ctx context.Context, token string) error {
-	return s.sessionRepo.UpdateLastActivity(ctx, token)
+	session, err := s.findSession(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return s.sessionRepo.UpdateLastActivity(ctx, session.ID)
+}
+
+// SessionPolicy returns the session policy for a customer session;
+// sessions of an impersonating admin end after the short impersonation lifetime
+func (s *AuthService) SessionPolicy(customer *store.Customer, session *store.Session) shared.SessionPolicy {
+	if session.IsImpersonated() {
+		return shared.SessionPolicy{
+			IdleTimeout: min(s.config.CustomerSessionIdleTimeout, s.config.ImpersonationLifetime),
+			Lifetime:    s.config.ImpersonationLifetime,
+		}
+	}
+
+	return shared.SessionPolicy{
+		IdleTimeout: s.config.CustomerSessionIdleTimeout,
+		Lifetime:    s.config.CustomerSessionLifetime,
+	}
+}
+
+// verifySecondFactor checks a TOTP code, or consumes a single-use recovery code if one is given
+func (s *AuthService) verifySecondFactor(ctx context.Context, customer *store.Customer, code, recoveryCode string) error {
+	if recoveryCode == "" {
+		step, ok := twofactor.Match(code, *customer.TwoFactorSecret)
+		if !ok {
+			return domain.ErrInvalidTwoFactorCode
+		}
+
+		// Each code works once, so an observed code cannot be replayed while it is still valid
+		if err := s.customerRepo.UseTwoFactorStep(ctx, customer.ID.String(), step); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrInvalidTwoFactorCode
+			}
+			return err
+		}
+		return nil
+	}
+
+	remaining, ok := twofactor.ConsumeRecoveryCode(twofactor.DecodeRecoveryCodes(customer.TwoFactorRecoveryCodes), recoveryCode)
+	if !ok {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	encoded, err := twofactor.EncodeRecoveryCodes(remaining)
+	if err != nil {
+		return err
+	}
+
+	// Compare-and-swap so the same code cannot be used by two concurrent logins
+	if err := s.customerRepo.UpdateRecoveryCodes(ctx, customer.ID.String(), customer.TwoFactorRecoveryCodes, encoded); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	customer.TwoFactorRecoveryCodes = &encoded
+
+	return nil
+}
+
+// recordFailure adds a failed login to the ledger and returns the error to report
+func (s *AuthService) recordFailure(ctx context.Context, email, ipAddress, userAgent string) error {
+	if err := s.throttle.RecordFailure(ctx, shared.UserTypeCustomer, email, ipAddress, userAgent); err != nil {
+		return err
+	}
+	return domain.ErrInvalidCredentials
+}
+
+// createSession generates a session token and stores a new session; every new session is a login kept in the history.
+// Only a completed login resets the failure count, a correct password alone does not.
+func (s *AuthService) createSession(ctx context.Context, customer *store.Customer, ipAddress, userAgent string) (*store.Session, error) {
+	if err := s.throttle.RecordSuccess(ctx, shared.UserTypeCustomer, customer.Email, ipAddress, userAgent); err != nil {
+		return nil, err
+	}
+
+	// Generate session token
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the hash is stored, the plain token is handed to the client once
+	session, err := s.sessionRepo.Create(ctx, customer.ID, s.tokens.Hash(token), ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Token = token
+
+	s.history.RecordSuccess(ctx, shared.UserTypeCustomer, customer.ID, customer.Email, ipAddress, userAgent)
+
+	return session, nil
+}
+
+// findSession looks up a session by the hash of its token under any accepted key
+// and rehashes it with the current key if it was made with an older one
+func (s *AuthService) findSession(ctx context.Context, token string) (*store.Session, error) {
+	session, err := s.sessionRepo.FindByToken(ctx, s.tokens.Candidates(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.tokens.IsCurrent(token, session.Token) {
+		if err := s.sessionRepo.UpdateToken(ctx, session.ID, s.tokens.Hash(token)); err != nil {
+			return nil, err
+		}
+	}
+
+	return session, nil
+}
+
+// createChallenge generates a challenge token and stores a new two-factor challenge
+func (s *AuthService) createChallenge(ctx context.Context, customer *store.Customer, ipAddress, userAgent string) (*store.TwoFactorChallenge, error) {
+	// Generate challenge token
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the hash is stored, the plain token is handed to the client once
+	expiresAt := time.Now().Add(s.config.TwoFactorChallengeLifetime)
+	challenge, err := s.challengeRepo.Create(ctx, customer.ID, hashToken(token), ipAddress, userAgent, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge.Token = token
+
+	return challenge, nil
 }
 
 // generateToken generates a secure random token
@@ -130,3 +494,9 @@ func generateToken() (string, error) {
 	}
 	return base64.URLEncoding.EncodeToString(b), nil
 }
+
+// hashToken returns the SHA-256 hex digest of a token for storage
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,17 +3,21 @@ package store
 import (
 	"context"
 
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain"
 	"github.com/yeftaz/susano.id/api/internal/domain/store"
 	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
 )
 
 type CustomerService struct {
 	customerRepo *storeRepo.CustomerRepository
+	verification *EmailVerificationService
 }
 
-func NewCustomerService(customerRepo *storeRepo.CustomerRepository) *CustomerService {
+func NewCustomerService(customerRepo *storeRepo.CustomerRepository, verification *EmailVerificationService) *CustomerService {
 	return &CustomerService{
 		customerRepo: customerRepo,
+		verification: verification,
 	}
 }
 
@@ -41,11 +45,27 @@ func (s *CustomerService) Update(ctx context.Context, id string, email, name str
 		namePtr = &name
 	}
 
+	// Get current email to detect a change
+	current, err := s.customerRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	customer, err := s.customerRepo.Update(ctx, id, emailPtr, namePtr)
 	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, domain.ErrEmailAlreadyExists
+		}
 		return nil, err
 	}
 
+	// A new email address is unverified until the link sent to it is opened
+	if customer.Email != current.Email && !customer.IsEmailVerified() {
+		if err := s.verification.SendVerificationEmail(ctx, customer); err != nil {
+			return nil, err
+		}
+	}
+
 	// Clear password before returning
 	customer.Password = ""
 
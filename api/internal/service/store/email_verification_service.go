@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/mailer"
+	"github.com/yeftaz/susano.id/api/pkg/signer"
+	"github.com/yeftaz/susano.id/api/pkg/throttle"
+)
+
+type EmailVerificationService struct {
+	customerRepo *storeRepo.CustomerRepository
+	mailer       mailer.Mailer
+	signer       *signer.Signer
+	throttle     *throttle.Throttle
+	logger       *logger.Logger
+	config       *config.Config
+}
+
+func NewEmailVerificationService(customerRepo *storeRepo.CustomerRepository, mailer mailer.Mailer, logger *logger.Logger, cfg *config.Config) *EmailVerificationService {
+	return &EmailVerificationService{
+		customerRepo: customerRepo,
+		mailer:       mailer,
+		signer:       signer.New(cfg.AppKey),
+		throttle:     throttle.New(cfg.EmailVerificationResendInterval),
+		logger:       logger,
+		config:       cfg,
+	}
+}
+
+// VerificationLink holds the signed parameters of a verification link
+type VerificationLink struct {
+	ID        string `json:"id" validate:"required,uuid"`
+	Hash      string `json:"hash" validate:"required"`
+	Expires   string `json:"expires" validate:"required,numeric"`
+	Signature string `json:"signature" validate:"required"`
+}
+
+// SendVerificationEmail emails a signed, expiring verification link to the customer
+func (s *EmailVerificationService) SendVerificationEmail(ctx context.Context, customer *store.Customer) error {
+	if customer.IsEmailVerified() {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	// Remember the send so an immediate resend is throttled
+	s.throttle.Allow(customer.ID.String())
+
+	s.send(customer)
+
+	return nil
+}
+
+// send builds the signed link and mails it in the background
+func (s *EmailVerificationService) send(customer *store.Customer) {
+	// The email hash is signed too, so the link stops working once the email changes
+	expiresAt := time.Now().Add(s.config.EmailVerificationLifetime)
+	params := s.signer.Sign(url.Values{
+		"id":   {customer.ID.String()},
+		"hash": {hashEmail(customer.Email)},
+	}, expiresAt)
+
+	link := fmt.Sprintf("%s/verify-email?%s", s.config.StoreAppURL, params.Encode())
+
+	msg := mailer.Message{
+		To:      customer.Email,
+		Subject: "Verify your Susano email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThis link expires in %s. If you did not create an account, you can ignore this email.\n",
+			customer.Name, link, s.config.EmailVerificationLifetime,
+		),
+	}
+
+	// Send in the background so requests do not wait on the mail server
+	go func() {
+		if err := s.mailer.Send(context.Background(), msg); err != nil {
+			s.logger.Error("Failed to send verification email", "customer_id", customer.ID, "error", err)
+		}
+	}()
+}
+
+// Verify checks a verification link and marks the customer email as verified
+func (s *EmailVerificationService) Verify(ctx context.Context, link VerificationLink) (*store.Customer, error) {
+	// Verify signature and expiry
+	if err := s.signer.Verify(url.Values{
+		"id":        {link.ID},
+		"hash":      {link.Hash},
+		"expires":   {link.Expires},
+		"signature": {link.Signature},
+	}); err != nil {
+		return nil, domain.ErrInvalidVerificationLink
+	}
+
+	// Find customer by ID
+	customer, err := s.customerRepo.FindByID(ctx, link.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidVerificationLink
+		}
+		return nil, err
+	}
+
+	// Link must belong to the current email address
+	if subtle.ConstantTimeCompare([]byte(link.Hash), []byte(hashEmail(customer.Email))) != 1 {
+		return nil, domain.ErrInvalidVerificationLink
+	}
+
+	if customer.IsEmailVerified() {
+		return nil, domain.ErrEmailAlreadyVerified
+	}
+
+	if err := s.customerRepo.MarkEmailAsVerified(ctx, link.ID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	customer.EmailVerifiedAt = &now
+
+	// Clear password before returning
+	customer.Password = ""
+
+	return customer, nil
+}
+
+// Resend sends a new verification link, at most once per resend interval per customer
+func (s *EmailVerificationService) Resend(ctx context.Context, customerID string) error {
+	customer, err := s.customerRepo.FindByID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if customer.IsEmailVerified() {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	if ok, wait := s.throttle.Allow(customerID); !ok {
+		return &domain.ThrottledError{RetryAfter: wait}
+	}
+
+	s.send(customer)
+
+	return nil
+}
+
+// hashEmail returns the SHA-256 hex digest of a normalized email address
+func hashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/mailer"
+	"github.com/yeftaz/susano.id/api/pkg/signer"
+	"github.com/yeftaz/susano.id/api/pkg/throttle"
+)
+
+type MagicLinkService struct {
+	customerRepo  *storeRepo.CustomerRepository
+	magicLinkRepo *storeRepo.MagicLinkRepository
+	auth          *AuthService
+	mailer        mailer.Mailer
+	signer        *signer.Signer
+	throttle      *throttle.Throttle
+	logger        *logger.Logger
+	config        *config.Config
+}
+
+func NewMagicLinkService(customerRepo *storeRepo.CustomerRepository, magicLinkRepo *storeRepo.MagicLinkRepository, auth *AuthService, mailer mailer.Mailer, logger *logger.Logger, cfg *config.Config) *MagicLinkService {
+	return &MagicLinkService{
+		customerRepo:  customerRepo,
+		magicLinkRepo: magicLinkRepo,
+		auth:          auth,
+		mailer:        mailer,
+		signer:        signer.New(cfg.AppKey),
+		throttle:      throttle.New(cfg.MagicLinkRequestInterval),
+		logger:        logger,
+		config:        cfg,
+	}
+}
+
+// MagicLinkParams holds the signed parameters of a magic link
+type MagicLinkParams struct {
+	Token     string `json:"token" validate:"required"`
+	Expires   string `json:"expires" validate:"required,numeric"`
+	Signature string `json:"signature" validate:"required"`
+}
+
+// Request emails a single-use login link if the customer exists, at most once per request interval per email.
+// It never reports whether the email is registered. When bindBrowser is set, the returned binding
+// must be kept by the requesting browser and presented again when the link is used.
+func (s *MagicLinkService) Request(ctx context.Context, email string, bindBrowser bool, ipAddress, userAgent string) (string, error) {
+	if ok, wait := s.throttle.Allow(strings.ToLower(strings.TrimSpace(email))); !ok {
+		return "", &domain.ThrottledError{RetryAfter: wait}
+	}
+
+	// The binding is handed out for unknown emails too, so responses look the same
+	var binding string
+	var bindingHash *string
+	if bindBrowser {
+		var err error
+		if binding, err = generateToken(); err != nil {
+			return "", err
+		}
+		hash := hashToken(binding)
+		bindingHash = &hash
+	}
+
+	customer, err := s.customerRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return binding, nil
+		}
+		return "", err
+	}
+
+	if !customer.CanPurchase() {
+		return binding, nil
+	}
+
+	// Generate link token, only its hash is stored
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	// A new link replaces any earlier one
+	if err := s.magicLinkRepo.DeleteByCustomerID(ctx, customer.ID); err != nil {
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(s.config.MagicLinkLifetime)
+	if _, err := s.magicLinkRepo.Create(ctx, customer.ID, hashToken(token), bindingHash, ipAddress, userAgent, expiresAt); err != nil {
+		return "", err
+	}
+
+	s.send(customer, token, expiresAt)
+
+	return binding, nil
+}
+
+// send builds the signed link and mails it in the background
+func (s *MagicLinkService) send(customer *store.Customer, token string, expiresAt time.Time) {
+	params := s.signer.Sign(url.Values{"token": {token}}, expiresAt)
+	link := fmt.Sprintf("%s/magic-link?%s", s.config.StoreAppURL, params.Encode())
+
+	msg := mailer.Message{
+		To:      customer.Email,
+		Subject: "Your Susano login link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nOpen the link below to log in to your account:\n\n%s\n\nThis link can be used once and expires in %s. If you did not request it, you can ignore this email.\n",
+			customer.Name, link, s.config.MagicLinkLifetime,
+		),
+	}
+
+	// Send in the background so requests do not wait on the mail server
+	go func() {
+		if err := s.mailer.Send(context.Background(), msg); err != nil {
+			s.logger.Error("Failed to send magic link email", "customer_id", customer.ID, "error", err)
+		}
+	}()
+}
+
+// Consume checks a magic link, uses it up and logs the customer in.
+// Links requested with a browser binding only work together with that binding.
+func (s *MagicLinkService) Consume(ctx context.Context, params MagicLinkParams, binding, ipAddress, userAgent string) (*LoginResult, error) {
+	// Verify signature and expiry
+	if err := s.signer.Verify(url.Values{
+		"token":     {params.Token},
+		"expires":   {params.Expires},
+		"signature": {params.Signature},
+	}); err != nil {
+		return nil, domain.ErrInvalidMagicLink
+	}
+
+	// Find link by token hash
+	link, err := s.magicLinkRepo.FindByToken(ctx, hashToken(params.Token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidMagicLink
+		}
+		return nil, err
+	}
+
+	if link.IsExpired() {
+		_ = s.magicLinkRepo.Delete(ctx, link.ID)
+		return nil, domain.ErrInvalidMagicLink
+	}
+
+	// A bound link stays valid when opened elsewhere, so the right browser can still use it
+	if link.IsBound() && subtle.ConstantTimeCompare([]byte(*link.BrowserBinding), []byte(hashToken(binding))) != 1 {
+		return nil, domain.ErrMagicLinkBrowserMismatch
+	}
+
+	// Consume link (fails if it was used concurrently)
+	if err := s.magicLinkRepo.Delete(ctx, link.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidMagicLink
+		}
+		return nil, err
+	}
+
+	customer, err := s.customerRepo.FindByID(ctx, link.CustomerID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidMagicLink
+		}
+		return nil, err
+	}
+
+	return s.auth.LoginWithMagicLink(ctx, customer, ipAddress, userAgent)
+}
@@ -0,0 +1,317 @@
+package store
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+	"github.com/yeftaz/susano.id/api/pkg/oidc"
+)
+
+type OIDCService struct {
+	customerRepo *storeRepo.CustomerRepository
+	sessionRepo  *storeRepo.SessionRepository
+	identityRepo *storeRepo.IdentityRepository
+	stateRepo    *storeRepo.OIDCStateRepository
+	auth         *AuthService
+	verification *EmailVerificationService
+	providers    map[string]*oidc.Provider
+	names        []string
+	config       *config.Config
+}
+
+func NewOIDCService(customerRepo *storeRepo.CustomerRepository, sessionRepo *storeRepo.SessionRepository, identityRepo *storeRepo.IdentityRepository, stateRepo *storeRepo.OIDCStateRepository, auth *AuthService, verification *EmailVerificationService, cfg *config.Config) *OIDCService {
+	providers := make(map[string]*oidc.Provider, len(cfg.OIDCProviders))
+	names := make([]string, 0, len(cfg.OIDCProviders))
+	for _, p := range cfg.OIDCProviders {
+		providers[p.Name] = oidc.New(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			Scopes:       p.Scopes,
+			RedirectURL:  p.RedirectURL,
+		})
+		names = append(names, p.Name)
+	}
+
+	return &OIDCService{
+		customerRepo: customerRepo,
+		sessionRepo:  sessionRepo,
+		identityRepo: identityRepo,
+		stateRepo:    stateRepo,
+		auth:         auth,
+		verification: verification,
+		providers:    providers,
+		names:        names,
+		config:       cfg,
+	}
+}
+
+// OIDCAuthorization is where to send the customer to log in at a provider.
+// State must be kept by the browser and presented again with the callback.
+type OIDCAuthorization struct {
+	URL       string    `json:"authorization_url"`
+	State     string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OIDCCallback holds the parameters the provider sent the customer back with
+type OIDCCallback struct {
+	State string `json:"state" validate:"required"`
+	Code  string `json:"code" validate:"required"`
+}
+
+// Providers returns the names of the configured identity providers
+func (s *OIDCService) Providers() []string {
+	return s.names
+}
+
+// Authorize starts a login at a provider
+func (s *OIDCService) Authorize(ctx context.Context, providerName, ipAddress, userAgent string) (*OIDCAuthorization, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, domain.ErrOIDCProviderNotFound
+	}
+
+	state, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oidc.GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the hash of the state is stored, the plain state is handed to the browser once
+	expiresAt := time.Now().Add(s.config.OIDCStateLifetime)
+	if _, err := s.stateRepo.Create(ctx, providerName, hashToken(state), nonce, verifier, ipAddress, userAgent, expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &OIDCAuthorization{URL: authURL, State: state, ExpiresAt: expiresAt}, nil
+}
+
+// Callback completes a login at a provider and logs the customer in.
+// browserState is the state kept by the browser that started the login,
+// so a callback cannot be replayed in another browser to log it into a different account.
+func (s *OIDCService) Callback(ctx context.Context, providerName string, params OIDCCallback, browserState, ipAddress, userAgent string) (*LoginResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, domain.ErrOIDCProviderNotFound
+	}
+
+	if subtle.ConstantTimeCompare([]byte(params.State), []byte(browserState)) != 1 {
+		return nil, domain.ErrInvalidOIDCLogin
+	}
+
+	// Find pending login by state hash
+	state, err := s.stateRepo.FindByState(ctx, providerName, hashToken(params.State))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidOIDCLogin
+		}
+		return nil, err
+	}
+
+	// Consume state (fails if it was used concurrently)
+	if err := s.stateRepo.Delete(ctx, state.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidOIDCLogin
+		}
+		return nil, err
+	}
+
+	if state.IsExpired() {
+		return nil, domain.ErrInvalidOIDCLogin
+	}
+
+	claims, err := provider.Exchange(ctx, params.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidToken) || errors.Is(err, oidc.ErrNonceMismatch) {
+			return nil, domain.ErrInvalidOIDCLogin
+		}
+		return nil, err
+	}
+
+	customer, err := s.resolveCustomer(ctx, providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.auth.LoginWithIdentity(ctx, customer, ipAddress, userAgent)
+}
+
+// Identities returns the identities linked to a customer
+func (s *OIDCService) Identities(ctx context.Context, customerID uuid.UUID) ([]*store.Identity, error) {
+	return s.identityRepo.FindByCustomerID(ctx, customerID)
+}
+
+// Unlink removes an identity of a customer; the customer can still log in with a password or a magic link
+func (s *OIDCService) Unlink(ctx context.Context, customerID, id uuid.UUID) error {
+	if err := s.identityRepo.Delete(ctx, id, customerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrIdentityNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// resolveCustomer finds the customer an identity is linked to. Unknown identities are linked
+// to the customer with the same email if the provider verified it, or get a new account.
+func (s *OIDCService) resolveCustomer(ctx context.Context, providerName string, claims *oidc.Claims) (*store.Customer, error) {
+	var email *string
+	if claims.Email != "" {
+		email = &claims.Email
+	}
+
+	identity, err := s.identityRepo.FindBySubject(ctx, providerName, claims.Subject)
+	if err == nil {
+		if err := s.identityRepo.UpdateLastUsed(ctx, identity.ID, email); err != nil {
+			return nil, err
+		}
+
+		customer, err := s.customerRepo.FindByID(ctx, identity.CustomerID.String())
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, domain.ErrUserInactive
+			}
+			return nil, err
+		}
+		return customer, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, domain.ErrOIDCEmailNotVerified
+	}
+
+	customer, err := s.customerRepo.FindByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		// Linking hands over an existing account, so the provider must vouch for the email
+		if !claims.EmailVerified {
+			return nil, domain.ErrOIDCEmailNotVerified
+		}
+
+		// Nobody proved owning the email of an unverified account, so whoever registered it
+		// may not be the owner the provider vouches for; shut them out before handing it over
+		if !customer.IsEmailVerified() {
+			if err := s.resetUnverified(ctx, customer); err != nil {
+				return nil, err
+			}
+		}
+	case errors.Is(err, sql.ErrNoRows):
+		if customer, err = s.register(ctx, claims); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if _, err := s.identityRepo.Create(ctx, customer.ID, providerName, claims.Subject, email); err != nil {
+		return nil, err
+	}
+
+	// The provider verified the address, which also proves ownership for this account
+	if claims.EmailVerified && !customer.IsEmailVerified() {
+		if err := s.customerRepo.MarkEmailAsVerified(ctx, customer.ID.String()); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		customer.EmailVerifiedAt = &now
+	}
+
+	return customer, nil
+}
+
+// resetUnverified replaces the password of an unverified account with a random one, turns off
+// 2FA and signs out all sessions, so a password set by someone else no longer gets in
+func (s *OIDCService) resetUnverified(ctx context.Context, customer *store.Customer) error {
+	hashedPassword, err := randomPasswordHash()
+	if err != nil {
+		return err
+	}
+
+	if err := s.customerRepo.UpdatePassword(ctx, customer.ID.String(), hashedPassword); err != nil {
+		return err
+	}
+
+	if err := s.customerRepo.DisableTwoFactor(ctx, customer.ID.String()); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.DeleteByCustomerID(ctx, customer.ID); err != nil {
+		return err
+	}
+
+	customer.TwoFactorSecret, customer.TwoFactorPendingSecret = nil, nil
+	customer.TwoFactorRecoveryCodes, customer.TwoFactorConfirmedAt = nil, nil
+
+	return nil
+}
+
+// register creates a customer for a new identity with a random password, which can be set with a password reset
+func (s *OIDCService) register(ctx context.Context, claims *oidc.Claims) (*store.Customer, error) {
+	hashedPassword, err := randomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	name := claims.Name
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	customer, err := s.customerRepo.Create(ctx, claims.Email, hashedPassword, name)
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, domain.ErrEmailAlreadyExists
+		}
+		return nil, err
+	}
+
+	// Addresses the provider did not verify are verified like after a normal registration
+	if !claims.EmailVerified {
+		if err := s.verification.SendVerificationEmail(ctx, customer); err != nil {
+			return nil, err
+		}
+	}
+
+	return customer, nil
+}
+
+// randomPasswordHash hashes a random password nobody knows, which can be replaced with a password reset
+func randomPasswordHash() (string, error) {
+	password, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashedPassword), nil
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	sharedRepo "github.com/yeftaz/susano.id/api/internal/repository/shared"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/mailer"
+)
+
+type PasswordResetService struct {
+	customerRepo *storeRepo.CustomerRepository
+	sessionRepo  *storeRepo.SessionRepository
+	tokens       *sharedService.PasswordResetTokens
+	passwords    *sharedService.PasswordPolicyService
+	mailer       mailer.Mailer
+	logger       *logger.Logger
+	config       *config.Config
+}
+
+func NewPasswordResetService(customerRepo *storeRepo.CustomerRepository, sessionRepo *storeRepo.SessionRepository, resetRepo *sharedRepo.PasswordResetRepository, passwords *sharedService.PasswordPolicyService, mailer mailer.Mailer, logger *logger.Logger, cfg *config.Config) *PasswordResetService {
+	return &PasswordResetService{
+		customerRepo: customerRepo,
+		sessionRepo:  sessionRepo,
+		tokens:       sharedService.NewPasswordResetTokens(resetRepo, shared.UserTypeCustomer, cfg.StoreAppURL, cfg.PasswordResetLifetime),
+		passwords:    passwords,
+		mailer:       mailer,
+		logger:       logger,
+		config:       cfg,
+	}
+}
+
+// ForgotPassword emails a reset link if the customer exists.
+// It never reports whether the email is registered.
+func (s *PasswordResetService) ForgotPassword(ctx context.Context, email string) error {
+	customer, err := s.customerRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if !customer.CanPurchase() {
+		return nil
+	}
+
+	link, err := s.tokens.Issue(ctx, customer.Email)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      customer.Email,
+		Subject: "Reset your Susano password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s\n\nThis link expires in %s. If you did not request a reset, you can ignore this email.\n",
+			customer.Name, link, s.config.PasswordResetLifetime,
+		),
+	}
+
+	// Send in the background so response timing does not reveal whether the email exists
+	go func() {
+		if err := s.mailer.Send(context.Background(), msg); err != nil {
+			s.logger.Error("Failed to send password reset email", "customer_id", customer.ID, "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and signs the customer out everywhere
+func (s *PasswordResetService) ResetPassword(ctx context.Context, email, token, password string) error {
+	if err := s.tokens.Verify(ctx, email, token); err != nil {
+		return err
+	}
+
+	customer, err := s.customerRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	// Check password against the policy and hash it
+	hashedPassword, err := s.passwords.Hash(ctx, shared.UserTypeCustomer, &customer.ID, customer.Password, password, customer.Email, customer.Name)
+	if err != nil {
+		return err
+	}
+
+	// Token is single use, so it is used up before the password changes
+	if err := s.tokens.Consume(ctx, email, token); err != nil {
+		return err
+	}
+
+	if err := s.customerRepo.UpdatePassword(ctx, customer.ID.String(), hashedPassword); err != nil {
+		return err
+	}
+	s.passwords.Remember(ctx, shared.UserTypeCustomer, customer.ID, hashedPassword)
+
+	// Invalidate all existing sessions
+	return s.sessionRepo.DeleteByCustomerID(ctx, customer.ID)
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+)
+
+type SessionService struct {
+	sessionRepo *storeRepo.SessionRepository
+	auth        *AuthService
+}
+
+func NewSessionService(sessionRepo *storeRepo.SessionRepository, auth *AuthService) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		auth:        auth,
+	}
+}
+
+// List returns all active sessions of a customer; sessions past the idle timeout or lifetime of
+// their session policy are left out until they are cleaned up
+func (s *SessionService) List(ctx context.Context, customer *store.Customer) ([]*store.Session, error) {
+	sessions, err := s.sessionRepo.FindByCustomerID(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]*store.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if !session.IsExpired(s.auth.SessionPolicy(customer, session)) {
+			active = append(active, session)
+		}
+	}
+
+	return active, nil
+}
+
+// Revoke deletes one session of a customer
+func (s *SessionService) Revoke(ctx context.Context, customerID, sessionID uuid.UUID) error {
+	if err := s.sessionRepo.DeleteByID(ctx, sessionID, customerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrSessionNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RevokeOthers deletes all sessions of a customer except the current one and returns how many were revoked
+func (s *SessionService) RevokeOthers(ctx context.Context, customerID, currentSessionID uuid.UUID) (int64, error) {
+	return s.sessionRepo.DeleteOthers(ctx, customerID, currentSessionID)
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+)
+
+// TwoFactorAccounts gives the shared two-factor service access to customer accounts
+type TwoFactorAccounts struct {
+	customerRepo  *storeRepo.CustomerRepository
+	challengeRepo *storeRepo.TwoFactorChallengeRepository
+}
+
+func NewTwoFactorAccounts(customerRepo *storeRepo.CustomerRepository, challengeRepo *storeRepo.TwoFactorChallengeRepository) *TwoFactorAccounts {
+	return &TwoFactorAccounts{
+		customerRepo:  customerRepo,
+		challengeRepo: challengeRepo,
+	}
+}
+
+// FindTwoFactorAccount retrieves the two-factor state of a customer
+func (a *TwoFactorAccounts) FindTwoFactorAccount(ctx context.Context, id string) (*shared.TwoFactorAccount, error) {
+	customer, err := a.customerRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return customer.TwoFactorAccount(), nil
+}
+
+// UpdatePendingTwoFactorSecret stores a new two-factor secret waiting for confirmation
+func (a *TwoFactorAccounts) UpdatePendingTwoFactorSecret(ctx context.Context, id, secret string) error {
+	return a.customerRepo.UpdatePendingTwoFactorSecret(ctx, id, secret)
+}
+
+// ConfirmTwoFactor swaps the pending secret in and stores a new batch of recovery codes
+func (a *TwoFactorAccounts) ConfirmTwoFactor(ctx context.Context, id, pendingSecret, recoveryCodes string, step int64) error {
+	return a.customerRepo.ConfirmTwoFactor(ctx, id, pendingSecret, recoveryCodes, step)
+}
+
+// UseTwoFactorStep records the time step of an accepted code if it is later than the last one
+func (a *TwoFactorAccounts) UseTwoFactorStep(ctx context.Context, id string, step int64) error {
+	return a.customerRepo.UseTwoFactorStep(ctx, id, step)
+}
+
+// DisableTwoFactor clears all two-factor data
+func (a *TwoFactorAccounts) DisableTwoFactor(ctx context.Context, id string) error {
+	return a.customerRepo.DisableTwoFactor(ctx, id)
+}
+
+// UpdateRecoveryCodes replaces the recovery codes if they still match current
+func (a *TwoFactorAccounts) UpdateRecoveryCodes(ctx context.Context, id string, current *string, recoveryCodes string) error {
+	return a.customerRepo.UpdateRecoveryCodes(ctx, id, current, recoveryCodes)
+}
+
+// DeleteTwoFactorChallenges drops the pending two-factor logins
+func (a *TwoFactorAccounts) DeleteTwoFactorChallenges(ctx context.Context, id uuid.UUID) error {
+	return a.challengeRepo.DeleteByCustomerID(ctx, id)
+}
@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+)
+
+// LogMailer writes messages to the application log instead of sending them (local development)
+type LogMailer struct {
+	from   string
+	logger *logger.Logger
+}
+
+func NewLogMailer(from string, logger *logger.Logger) *LogMailer {
+	return &LogMailer{
+		from:   from,
+		logger: logger,
+	}
+}
+
+// Send logs the message
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.Info("Mail sent",
+		"from", m.from,
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", msg.Body,
+	)
+	return nil
+}
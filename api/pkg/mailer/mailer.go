@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yeftaz/susano.id/api/pkg/logger"
+)
+
+// Message represents an outgoing plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email messages
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config selects and sets up a mailer
+type Config struct {
+	Driver      string // "file" writes messages to FilePath, anything else logs them
+	FromAddress string
+	FromName    string
+	FilePath    string
+}
+
+// New creates the mailer selected by the driver, falling back to the log mailer
+func New(cfg Config, logger *logger.Logger) Mailer {
+	from := formatAddress(cfg.FromName, cfg.FromAddress)
+
+	switch cfg.Driver {
+	case "file":
+		return NewFileMailer(from, cfg.FilePath)
+	default:
+		return NewLogMailer(from, logger)
+	}
+}
+
+// formatAddress formats an address with an optional display name
+func formatAddress(name, address string) string {
+	if name == "" {
+		return address
+	}
+	return fmt.Sprintf("%s <%s>", name, address)
+}
@@ -0,0 +1,295 @@
+package passkey
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+var (
+	ErrInvalidResponse = errors.New("invalid passkey response")
+	ErrCloned          = errors.New("passkey signature counter went backwards, the authenticator may be cloned")
+)
+
+// CreationOptions are handed to navigator.credentials.create() in the browser
+type CreationOptions = protocol.CredentialCreation
+
+// RequestOptions are handed to navigator.credentials.get() in the browser
+type RequestOptions = protocol.CredentialAssertion
+
+// Credential is the stored public part of a passkey
+type Credential struct {
+	ID              []byte
+	PublicKey       []byte
+	AttestationType string
+	Transports      []string
+	AAGUID          []byte
+	SignCount       uint32
+	BackupEligible  bool
+	BackupState     bool
+	UserVerified    bool
+	Attachment      string
+}
+
+// User is the account passkeys are registered for
+type User struct {
+	ID          []byte // Opaque user handle, at most 64 bytes
+	Name        string
+	DisplayName string
+	Credentials []Credential
+}
+
+// RelyingParty registers passkeys and verifies logins with them (WebAuthn Level 3)
+type RelyingParty struct {
+	webauthn *webauthn.WebAuthn
+	err      error // Configuration error, returned by every ceremony
+}
+
+// New creates a relying party for the given domain, accepting responses from the given origins.
+// Ceremonies must be finished within timeout.
+func New(rpID, rpName string, origins []string, timeout time.Duration) *RelyingParty {
+	timeouts := webauthn.TimeoutConfig{Enforce: true, Timeout: timeout, TimeoutUVD: timeout}
+
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: rpName,
+		RPOrigins:     origins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeouts,
+			Registration: timeouts,
+		},
+	})
+
+	return &RelyingParty{
+		webauthn: w,
+		err:      err,
+	}
+}
+
+// BeginRegistration starts registering a new passkey and returns the options for the browser
+// and the session to keep until the registration is finished. Passkeys the user already has are excluded.
+func (rp *RelyingParty) BeginRegistration(user *User) (*CreationOptions, []byte, error) {
+	if rp.err != nil {
+		return nil, nil, rp.err
+	}
+
+	options, session, err := rp.webauthn.BeginRegistration(webauthnUser{user},
+		webauthn.WithExclusions(webauthn.Credentials(user.webauthnCredentials()).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoded, err := json.Marshal(session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return options, encoded, nil
+}
+
+// FinishRegistration verifies the attestation the browser returned and returns the new credential
+func (rp *RelyingParty) FinishRegistration(user *User, session, response []byte) (*Credential, error) {
+	if rp.err != nil {
+		return nil, rp.err
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal(session, &data); err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	credential, err := rp.webauthn.CreateCredential(webauthnUser{user}, data, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	c := fromWebAuthn(credential)
+	return &c, nil
+}
+
+// BeginLogin starts a login with one of the passkeys of a known user, e.g. as a second factor
+func (rp *RelyingParty) BeginLogin(user *User) (*RequestOptions, []byte, error) {
+	if rp.err != nil {
+		return nil, nil, rp.err
+	}
+
+	options, session, err := rp.webauthn.BeginLogin(webauthnUser{user})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoded, err := json.Marshal(session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return options, encoded, nil
+}
+
+// FinishLogin verifies the assertion the browser returned for a known user
+// and returns the used credential with its updated signature counter
+func (rp *RelyingParty) FinishLogin(user *User, session, response []byte) (*Credential, error) {
+	if rp.err != nil {
+		return nil, rp.err
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal(session, &data); err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	credential, err := rp.webauthn.ValidateLogin(webauthnUser{user}, data, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	return verified(credential)
+}
+
+// BeginPasswordlessLogin starts a login where the browser offers any passkey it holds for this site.
+// User verification (PIN or biometrics) is required, so the passkey alone is enough to log in.
+func (rp *RelyingParty) BeginPasswordlessLogin() (*RequestOptions, []byte, error) {
+	if rp.err != nil {
+		return nil, nil, rp.err
+	}
+
+	options, session, err := rp.webauthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoded, err := json.Marshal(session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return options, encoded, nil
+}
+
+// FinishPasswordlessLogin verifies the assertion the browser returned, using lookup to find the user
+// by the user handle stored in the passkey, and returns the user and the used credential
+func (rp *RelyingParty) FinishPasswordlessLogin(session, response []byte, lookup func(userHandle []byte) (*User, error)) (*User, *Credential, error) {
+	if rp.err != nil {
+		return nil, nil, rp.err
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal(session, &data); err != nil {
+		return nil, nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	var user *User
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		u, err := lookup(userHandle)
+		if err != nil {
+			return nil, err
+		}
+		user = u
+		return webauthnUser{u}, nil
+	}
+
+	credential, err := rp.webauthn.ValidateDiscoverableLogin(handler, data, parsed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	c, err := verified(credential)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, c, nil
+}
+
+// verified converts a validated credential and refuses it if the authenticator looks cloned
+func verified(credential *webauthn.Credential) (*Credential, error) {
+	if credential.Authenticator.CloneWarning {
+		return nil, ErrCloned
+	}
+
+	c := fromWebAuthn(credential)
+	return &c, nil
+}
+
+func (u *User) webauthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(u.Credentials))
+	for i, c := range u.Credentials {
+		credentials[i] = c.toWebAuthn()
+	}
+	return credentials
+}
+
+// webauthnUser adapts User to the webauthn.User interface
+type webauthnUser struct {
+	*User
+}
+
+func (u webauthnUser) WebAuthnID() []byte                         { return u.ID }
+func (u webauthnUser) WebAuthnName() string                       { return u.Name }
+func (u webauthnUser) WebAuthnDisplayName() string                { return u.DisplayName }
+func (u webauthnUser) WebAuthnCredentials() []webauthn.Credential { return u.webauthnCredentials() }
+
+func (c Credential) toWebAuthn() webauthn.Credential {
+	transports := make([]protocol.AuthenticatorTransport, len(c.Transports))
+	for i, t := range c.Transports {
+		transports[i] = protocol.AuthenticatorTransport(t)
+	}
+
+	return webauthn.Credential{
+		ID:              c.ID,
+		PublicKey:       c.PublicKey,
+		AttestationType: c.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			UserVerified:   c.UserVerified,
+			BackupEligible: c.BackupEligible,
+			BackupState:    c.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:     c.AAGUID,
+			SignCount:  c.SignCount,
+			Attachment: protocol.AuthenticatorAttachment(c.Attachment),
+		},
+	}
+}
+
+func fromWebAuthn(credential *webauthn.Credential) Credential {
+	transports := make([]string, len(credential.Transport))
+	for i, t := range credential.Transport {
+		transports[i] = string(t)
+	}
+
+	return Credential{
+		ID:              credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		UserVerified:    credential.Flags.UserVerified,
+		Attachment:      string(credential.Authenticator.Attachment),
+	}
+}
@@ -0,0 +1,73 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// rangePrefixLength is how many hex characters of the SHA-1 hash name a range file
+const rangePrefixLength = 5
+
+// BreachList looks up passwords in a local copy of a breached password corpus split into
+// k-anonymity range files, as served by the Pwned Passwords range API: one file per 5 character
+// SHA-1 prefix (e.g. "21BD1.txt" or "21BD1") holding "SUFFIX:COUNT" lines.
+// Only the range file of the password's prefix is read, so the corpus never has to fit in memory.
+type BreachList struct {
+	dir string
+}
+
+// OpenBreachList opens the range files in dir
+func OpenBreachList(dir string) (*BreachList, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("breached password list %s is not a directory", dir)
+	}
+
+	return &BreachList{dir: dir}, nil
+}
+
+// Contains checks if the password appears in the list. A missing range file means no password
+// with that prefix was breached.
+func (l *BreachList) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:rangePrefixLength], hash[rangePrefixLength:]
+
+	file, err := l.openRange(prefix)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		candidate, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(candidate, suffix) && count != "0" {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// openRange opens the range file of a prefix, with or without a .txt extension
+func (l *BreachList) openRange(prefix string) (*os.File, error) {
+	file, err := os.Open(filepath.Join(l.dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.Open(filepath.Join(l.dir, prefix))
+	}
+	return file, err
+}
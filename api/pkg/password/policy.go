@@ -0,0 +1,92 @@
+package password
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLength is the longest password bcrypt can hash
+const MaxLength = 72
+
+// personalMinLength is the shortest personal detail (or email local part) a password may not contain
+const personalMinLength = 3
+
+// Policy describes the composition rules a new password must meet
+type Policy struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+}
+
+// Check returns the rules the password breaks, as messages for the user; none means it is acceptable.
+// Personal details such as the email address and name must not appear in the password.
+func (p Policy) Check(password string, personal ...string) []string {
+	var problems []string
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		problems = append(problems, "must be at least "+strconv.Itoa(p.MinLength)+" characters long")
+	}
+	if len(password) > MaxLength {
+		problems = append(problems, "must be at most "+strconv.Itoa(MaxLength)+" bytes long")
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if p.RequireUppercase && !upper {
+		problems = append(problems, "must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		problems = append(problems, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		problems = append(problems, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		problems = append(problems, "must contain a symbol")
+	}
+
+	if containsPersonal(password, personal) {
+		problems = append(problems, "must not contain your email address or name")
+	}
+
+	return problems
+}
+
+// containsPersonal checks if the password contains a personal detail, the local part of an email
+// address or a word of a name, ignoring case
+func containsPersonal(password string, personal []string) bool {
+	lowered := strings.ToLower(password)
+
+	for _, detail := range personal {
+		detail = strings.ToLower(strings.TrimSpace(detail))
+
+		parts := strings.Fields(detail)
+		if local, _, ok := strings.Cut(detail, "@"); ok {
+			parts = []string{detail, local}
+		}
+
+		for _, part := range parts {
+			if utf8.RuneCountInString(part) >= personalMinLength && strings.Contains(lowered, part) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -2,7 +2,10 @@ package response
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // Response represents a standard API response
@@ -97,3 +100,9 @@ func ErrorWithDetails(w http.ResponseWriter, statusCode int, message string, err
 
 	json.NewEncoder(w).Encode(response)
 }
+
+// ErrorWithRetryAfter sends an error JSON response with a Retry-After header (in whole seconds)
+func ErrorWithRetryAfter(w http.ResponseWriter, statusCode int, message string, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	Error(w, statusCode, message)
+}
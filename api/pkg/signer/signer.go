@@ -0,0 +1,78 @@
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpiredSignature = errors.New("signature has expired")
+)
+
+// Signer signs and verifies expiring URL parameters with HMAC-SHA256
+type Signer struct {
+	key []byte
+}
+
+func New(key string) *Signer {
+	return &Signer{
+		key: []byte(key),
+	}
+}
+
+// Sign returns a copy of values with "expires" and "signature" parameters added
+func (s *Signer) Sign(values url.Values, expiresAt time.Time) url.Values {
+	signed := url.Values{}
+	for k, v := range values {
+		signed[k] = append([]string(nil), v...)
+	}
+
+	signed.Del("signature")
+	signed.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	signed.Set("signature", s.signature(signed))
+
+	return signed
+}
+
+// Verify checks the signature and expiry of values produced by Sign
+func (s *Signer) Verify(values url.Values) error {
+	signature := values.Get("signature")
+	if signature == "" {
+		return ErrInvalidSignature
+	}
+
+	unsigned := url.Values{}
+	for k, v := range values {
+		if k != "signature" {
+			unsigned[k] = v
+		}
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.signature(unsigned))) {
+		return ErrInvalidSignature
+	}
+
+	expires, err := strconv.ParseInt(values.Get("expires"), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if time.Now().Unix() > expires {
+		return ErrExpiredSignature
+	}
+
+	return nil
+}
+
+// signature computes the HMAC of the canonical (sorted) encoding of values
+func (s *Signer) signature(values url.Values) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(values.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
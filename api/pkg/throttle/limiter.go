@@ -0,0 +1,58 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter allows a number of actions per key within a fixed window (in-memory, per instance)
+type Limiter struct {
+	window  time.Duration
+	windows map[string]*window
+	mu      sync.Mutex
+}
+
+// window counts the actions of one key since the window started
+type window struct {
+	start time.Time
+	count int
+}
+
+func NewLimiter(windowSize time.Duration) *Limiter {
+	return &Limiter{
+		window:  windowSize,
+		windows: make(map[string]*window),
+	}
+}
+
+// Allow reports whether another action within limit may run now, or how long to wait otherwise
+func (l *Limiter) Allow(key string, limit int) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &window{start: now}
+		l.windows[key] = w
+		if len(l.windows) > pruneThreshold {
+			l.prune(now)
+		}
+	}
+
+	if w.count >= limit {
+		return false, l.window - now.Sub(w.start)
+	}
+
+	w.count++
+	return true, 0
+}
+
+// prune drops keys whose window has passed so the map does not grow unbounded
+func (l *Limiter) prune(now time.Time) {
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.window {
+			delete(l.windows, key)
+		}
+	}
+}
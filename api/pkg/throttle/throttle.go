@@ -0,0 +1,52 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// pruneThreshold is the number of tracked keys above which stale keys are dropped
+const pruneThreshold = 1024
+
+// Throttle allows one action per key within an interval (in-memory, per instance)
+type Throttle struct {
+	interval time.Duration
+	last     map[string]time.Time
+	mu       sync.Mutex
+}
+
+func New(interval time.Duration) *Throttle {
+	return &Throttle{
+		interval: interval,
+		last:     make(map[string]time.Time),
+	}
+}
+
+// Allow reports whether the action may run now, or how long to wait otherwise
+func (t *Throttle) Allow(key string) (bool, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := t.last[key]; ok {
+		if wait := t.interval - now.Sub(last); wait > 0 {
+			return false, wait
+		}
+	}
+
+	t.last[key] = now
+	if len(t.last) > pruneThreshold {
+		t.prune(now)
+	}
+
+	return true, 0
+}
+
+// prune drops keys whose interval has passed so the map does not grow unbounded
+func (t *Throttle) prune(now time.Time) {
+	for key, last := range t.last {
+		if now.Sub(last) > t.interval {
+			delete(t.last, key)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package tokenhash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Hasher computes keyed hashes (HMAC-SHA256) of tokens so they can be stored and looked up
+// without keeping the plain token. The first key is used for new hashes, the others are
+// still accepted so keys can be rotated without signing everyone out.
+type Hasher struct {
+	keys [][]byte
+}
+
+func New(keys []string) *Hasher {
+	h := &Hasher{}
+	for _, key := range keys {
+		if key != "" {
+			h.keys = append(h.keys, []byte(key))
+		}
+	}
+	return h
+}
+
+// Hash returns the hash of token under the current key
+func (h *Hasher) Hash(token string) string {
+	if len(h.keys) == 0 {
+		return hash(nil, token)
+	}
+	return hash(h.keys[0], token)
+}
+
+// Candidates returns the hash of token under every accepted key, current key first
+func (h *Hasher) Candidates(token string) []string {
+	if len(h.keys) == 0 {
+		return []string{hash(nil, token)}
+	}
+
+	hashes := make([]string, 0, len(h.keys))
+	for _, key := range h.keys {
+		hashes = append(hashes, hash(key, token))
+	}
+	return hashes
+}
+
+// IsCurrent checks if a stored hash was made with the current key
+func (h *Hasher) IsCurrent(token, hashed string) bool {
+	return hmac.Equal([]byte(h.Hash(token)), []byte(hashed))
+}
+
+func hash(key []byte, token string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,98 @@
+package twofactor
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// RecoveryCodeCount is the number of recovery codes issued per batch
+const RecoveryCodeCount = 8
+
+// recoveryAlphabet omits characters that are easily confused (0/o, 1/l/i)
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// GenerateRecoveryCodes generates a batch of random recovery codes formatted as xxxxx-xxxxx
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	max := big.NewInt(int64(len(recoveryAlphabet)))
+
+	for i := 0; i < n; i++ {
+		b := make([]byte, 10)
+		for j := range b {
+			idx, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, err
+			}
+			b[j] = recoveryAlphabet[idx.Int64()]
+		}
+		codes = append(codes, string(b[:5])+"-"+string(b[5:]))
+	}
+
+	return codes, nil
+}
+
+// HashRecoveryCode returns the SHA-256 hex digest of a normalized recovery code.
+// Codes carry ~50 bits of entropy each, so a fast hash is sufficient.
+func HashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashRecoveryCodes hashes a batch of recovery codes
+func HashRecoveryCodes(codes []string) []string {
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return hashes
+}
+
+// EncodeRecoveryCodes serializes recovery code hashes for storage
+func EncodeRecoveryCodes(hashes []string) (string, error) {
+	b, err := json.Marshal(hashes)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// DecodeRecoveryCodes parses stored recovery code hashes, returning nil for empty or invalid data
+func DecodeRecoveryCodes(encoded *string) []string {
+	if encoded == nil || *encoded == "" {
+		return nil
+	}
+
+	var hashes []string
+	if err := json.Unmarshal([]byte(*encoded), &hashes); err != nil {
+		return nil
+	}
+	return hashes
+}
+
+// ConsumeRecoveryCode checks a code against the stored hashes and returns the hashes left after burning it
+func ConsumeRecoveryCode(hashes []string, code string) ([]string, bool) {
+	hash := HashRecoveryCode(code)
+
+	for i, stored := range hashes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			remaining := make([]string, 0, len(hashes)-1)
+			remaining = append(remaining, hashes[:i]...)
+			remaining = append(remaining, hashes[i+1:]...)
+			return remaining, true
+		}
+	}
+
+	return hashes, false
+}
+
+// normalizeRecoveryCode lowercases a code and strips separators users may type differently
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,134 @@
+package twofactor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// secretSize is the number of random bytes in a generated secret (160 bits, as recommended by RFC 4226)
+	secretSize = 20
+
+	// period is the TOTP time step
+	period = 30 * time.Second
+
+	// digits is the number of digits in a generated code
+	digits = 6
+
+	// skew is the number of time steps accepted before and after the current one
+	skew = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret generates a new random base32 encoded TOTP secret
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// URI builds an otpauth:// URI that authenticator apps can import (usually rendered as a QR code)
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", digits))
+	params.Set("period", fmt.Sprintf("%d", int(period.Seconds())))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+// Validate checks a code against the secret at the current time
+func Validate(code, secret string) bool {
+	return ValidateAt(code, secret, time.Now())
+}
+
+// ValidateAt checks a code against the secret at the given time, allowing for clock skew
+func ValidateAt(code, secret string, t time.Time) bool {
+	_, ok := MatchAt(code, secret, t)
+	return ok
+}
+
+// Match checks a code against the secret at the current time and returns the time step it belongs to
+func Match(code, secret string) (int64, bool) {
+	return MatchAt(code, secret, time.Now())
+}
+
+// MatchAt checks a code against the secret at the given time, allowing for clock skew, and returns
+// the time step it belongs to. A code must be accepted only once (RFC 6238 §5.2), so callers keep
+// the last accepted step and refuse codes of the same or an earlier one.
+func MatchAt(code, secret string, t time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != digits {
+		return 0, false
+	}
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	counter := t.Unix() / int64(period.Seconds())
+	for i := -skew; i <= skew; i++ {
+		step := counter + int64(i)
+		expected := generateCode(key, uint64(step))
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// GenerateCode generates the code for the given secret at the given time
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	counter := uint64(t.Unix()) / uint64(period.Seconds())
+	return generateCode(key, counter), nil
+}
+
+// generateCode computes an HOTP value (RFC 4226) for the given counter
+func generateCode(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+// decodeSecret decodes a base32 secret, tolerating lowercase letters, spaces and padding
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	return encoding.DecodeString(secret)
+}
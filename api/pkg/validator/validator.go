@@ -36,12 +36,18 @@ func formatValidationError(err validator.FieldError) string {
 	switch err.Tag() {
 	case "required":
 		return fmt.Sprintf("%s is required", field)
+	case "required_without":
+		return fmt.Sprintf("%s is required when %s is not present", field, strings.ToLower(err.Param()))
+	case "eqfield":
+		return fmt.Sprintf("%s must match %s", field, strings.ToLower(err.Param()))
 	case "email":
 		return fmt.Sprintf("%s must be a valid email address", field)
 	case "min":
 		return fmt.Sprintf("%s must be at least %s characters", field, err.Param())
 	case "max":
 		return fmt.Sprintf("%s must not exceed %s characters", field, err.Param())
+	case "len":
+		return fmt.Sprintf("%s must be exactly %s characters", field, err.Param())
 	case "oneof":
 		return fmt.Sprintf("%s must be one of: %s", field, err.Param())
 	case "url":
@@ -3,9 +3,11 @@ package admin_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestAdminCRUD(t *testing.T) {
@@ -13,6 +15,7 @@ func TestAdminCRUD(t *testing.T) {
 
 	// Get session token first
 	sessionCookie := loginAndGetSessionCookie(t, handler)
+	csrfToken := getCSRFToken(t, handler, sessionCookie)
 
 	// Test Get All Admins
 	t.Run("Get All Admins", func(t *testing.T) {
@@ -39,6 +42,7 @@ func TestAdminCRUD(t *testing.T) {
 		body, _ := json.Marshal(adminData)
 		req := httptest.NewRequest("POST", "/api/v1/admin/admins", bytes.NewBuffer(body))
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-CSRF-Token", csrfToken)
 		req.AddCookie(sessionCookie)
 
 		rr := httptest.NewRecorder()
@@ -73,3 +77,469 @@ func loginAndGetSessionCookie(t *testing.T, handler *http.Handler) *http.Cookie
 	t.Fatal("No session cookie found")
 	return nil
 }
+
+// getCSRFToken fetches the CSRF token state-changing requests of a cookie session must send
+func getCSRFToken(t *testing.T, handler *http.Handler, sessionCookie *http.Cookie) string {
+	req := httptest.NewRequest("GET", "/api/v1/admin/auth/csrf", nil)
+	req.AddCookie(sessionCookie)
+
+	rr := httptest.NewRecorder()
+	(*handler).ServeHTTP(rr, req)
+
+	var csrfResp struct {
+		Data struct {
+			Token string `json:"csrf_token"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&csrfResp); err != nil || csrfResp.Data.Token == "" {
+		t.Fatal("No CSRF token found")
+	}
+
+	return csrfResp.Data.Token
+}
+
+func TestAuditLogs(t *testing.T) {
+	handler := setupTestRouter(t)
+	sessionCookie := loginAndGetSessionCookie(t, handler)
+
+	t.Run("List Audit Logs", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/admin/audit-logs?action=auth.login&limit=5", nil)
+		req.AddCookie(sessionCookie)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+
+	t.Run("Invalid Filter", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/admin/audit-logs?from=yesterday", nil)
+		req.AddCookie(sessionCookie)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusUnprocessableEntity {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnprocessableEntity)
+		}
+	})
+
+	t.Run("Export CSV", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/admin/audit-logs/export?from=2020-01-01", nil)
+		req.AddCookie(sessionCookie)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		if ct := rr.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+			t.Errorf("expected CSV content type, got %q", ct)
+		}
+	})
+}
+
+func TestProductCatalog(t *testing.T) {
+	handler := setupTestRouter(t)
+	sessionCookie := loginAndGetSessionCookie(t, handler)
+	csrfToken := getCSRFToken(t, handler, sessionCookie)
+
+	send := func(method, path string, data interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if data != nil {
+			json.NewEncoder(&body).Encode(data)
+		}
+		req := httptest.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-CSRF-Token", csrfToken)
+		req.AddCookie(sessionCookie)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	var created struct {
+		Data struct {
+			ID   string `json:"id"`
+			Slug string `json:"slug"`
+		} `json:"data"`
+	}
+
+	// mainLocation finds the location seeded for the stock that existed before locations
+	mainLocation := func(t *testing.T) string {
+		rr := send("GET", "/api/v1/admin/locations", nil)
+
+		var locations struct {
+			Data []struct {
+				ID   string `json:"id"`
+				Code string `json:"code"`
+			} `json:"data"`
+		}
+		json.NewDecoder(rr.Body).Decode(&locations)
+		for _, l := range locations.Data {
+			if l.Code == "MAIN" {
+				return l.ID
+			}
+		}
+		t.Fatal("expected the MAIN location to be seeded")
+		return ""
+	}
+
+	// firstVariant finds the first variant of the created product
+	firstVariant := func(t *testing.T) string {
+		rr := send("GET", "/api/v1/admin/products/"+created.Data.ID, nil)
+
+		var product struct {
+			Data struct {
+				Variants []struct {
+					ID string `json:"id"`
+				} `json:"variants"`
+			} `json:"data"`
+		}
+		json.NewDecoder(rr.Body).Decode(&product)
+		if len(product.Data.Variants) == 0 {
+			t.Fatal("expected the product to have variants")
+		}
+		return product.Data.Variants[0].ID
+	}
+
+	t.Run("Create Product", func(t *testing.T) {
+		rr := send("POST", "/api/v1/admin/products", map[string]interface{}{
+			"name":        "Integration Test Kopi",
+			"description": "Single origin beans",
+			"images": []map[string]string{
+				{"url": "https://cdn.susano.id/products/kopi.jpg", "alt_text": "Kopi"},
+			},
+		})
+
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+		json.NewDecoder(rr.Body).Decode(&created)
+
+		if created.Data.Slug != "integration-test-kopi" {
+			t.Errorf("expected slug derived from the name, got %q", created.Data.Slug)
+		}
+	})
+
+	t.Run("Draft Hidden From Storefront", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/store/products/"+created.Data.Slug, nil)
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+		}
+	})
+
+	t.Run("Publish Product", func(t *testing.T) {
+		rr := send("PATCH", "/api/v1/admin/products/"+created.Data.ID, map[string]string{"status": "active"})
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+
+	t.Run("Active Product On Storefront", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/store/products/"+created.Data.Slug, nil)
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+
+	t.Run("Duplicate Slug", func(t *testing.T) {
+		rr := send("POST", "/api/v1/admin/products", map[string]string{"name": "Integration Test Kopi"})
+
+		if status := rr.Code; status != http.StatusUnprocessableEntity {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnprocessableEntity)
+		}
+	})
+
+	t.Run("Invalid Status Filter", func(t *testing.T) {
+		rr := send("GET", "/api/v1/admin/products?status=published", nil)
+
+		if status := rr.Code; status != http.StatusUnprocessableEntity {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnprocessableEntity)
+		}
+	})
+
+	t.Run("Set Options", func(t *testing.T) {
+		rr := send("PUT", "/api/v1/admin/products/"+created.Data.ID+"/options", map[string]interface{}{
+			"options": []map[string]interface{}{
+				{"name": "Size", "values": []string{"S", "M"}},
+				{"name": "Roast", "values": []string{"Light", "Dark"}},
+			},
+			"price": 85000,
+		})
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		var resp struct {
+			Data struct {
+				Variants []struct {
+					ID  string `json:"id"`
+					SKU string `json:"sku"`
+				} `json:"variants"`
+			} `json:"data"`
+		}
+		json.NewDecoder(rr.Body).Decode(&resp)
+
+		if len(resp.Data.Variants) != 4 {
+			t.Fatalf("expected 4 variants, got %d", len(resp.Data.Variants))
+		}
+
+		// Disable one combination, the storefront only lists the remaining ones
+		rr = send("PATCH", "/api/v1/admin/products/"+created.Data.ID+"/variants/"+resp.Data.Variants[0].ID, map[string]interface{}{"is_enabled": false})
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		req := httptest.NewRequest("GET", "/api/v1/store/products/"+created.Data.Slug, nil)
+		rr = httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+		json.NewDecoder(rr.Body).Decode(&resp)
+
+		if len(resp.Data.Variants) != 3 {
+			t.Errorf("expected 3 enabled variants on the storefront, got %d", len(resp.Data.Variants))
+		}
+	})
+
+	t.Run("Inventory", func(t *testing.T) {
+		inventory := "/api/v1/admin/inventory/variants/" + firstVariant(t)
+		main := mainLocation(t)
+
+		// Adjustments must give a reason
+		rr := send("POST", inventory+"/adjustments", map[string]interface{}{"location_id": main, "type": "receipt", "quantity": 2})
+		if status := rr.Code; status != http.StatusUnprocessableEntity {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnprocessableEntity)
+		}
+
+		rr = send("POST", inventory+"/adjustments", map[string]interface{}{"location_id": main, "type": "receipt", "quantity": 2, "reason": "Supplier delivery"})
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+
+		rr = send("POST", inventory+"/sales", map[string]interface{}{"location_id": main, "quantity": 2})
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+
+		// The last item is gone, another sale must not oversell
+		rr = send("POST", inventory+"/sales", map[string]interface{}{"location_id": main, "quantity": 1})
+		if status := rr.Code; status != http.StatusConflict {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+		}
+
+		rr = send("GET", inventory+"/movements", nil)
+
+		var movements struct {
+			Data []struct {
+				Type     string `json:"type"`
+				Quantity int    `json:"quantity"`
+			} `json:"data"`
+		}
+		json.NewDecoder(rr.Body).Decode(&movements)
+
+		if len(movements.Data) != 2 || movements.Data[0].Type != "sale" || movements.Data[0].Quantity != -2 {
+			t.Errorf("expected the receipt and the sale in the ledger, got %+v", movements.Data)
+		}
+	})
+
+	t.Run("Transfers", func(t *testing.T) {
+		variantID := firstVariant(t)
+		inventory := "/api/v1/admin/inventory/variants/" + variantID
+		main := mainLocation(t)
+
+		var shop struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		rr := send("POST", "/api/v1/admin/locations", map[string]string{
+			"name": "Integration Test Shop",
+			"code": fmt.Sprintf("IT-%d", time.Now().UnixNano()),
+			"type": "store",
+		})
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+		json.NewDecoder(rr.Body).Decode(&shop)
+		defer send("PATCH", "/api/v1/admin/locations/"+shop.Data.ID, map[string]bool{"is_active": false})
+
+		rr = send("POST", inventory+"/adjustments", map[string]interface{}{"location_id": main, "type": "receipt", "quantity": 3, "reason": "Supplier delivery"})
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+
+		var transfer struct {
+			Data struct {
+				ID     string `json:"id"`
+				Status string `json:"status"`
+			} `json:"data"`
+		}
+		rr = send("POST", "/api/v1/admin/transfers", map[string]interface{}{
+			"from_location_id": main,
+			"to_location_id":   shop.Data.ID,
+			"items":            []map[string]interface{}{{"variant_id": variantID, "quantity": 2}},
+		})
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+		json.NewDecoder(rr.Body).Decode(&transfer)
+		path := "/api/v1/admin/transfers/" + transfer.Data.ID
+
+		// Only shipped transfers can be received
+		rr = send("POST", path+"/receive", nil)
+		if status := rr.Code; status != http.StatusConflict {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+		}
+
+		rr = send("POST", path+"/ship", nil)
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		levelsAt := func() map[string][2]int {
+			rr := send("GET", inventory, nil)
+
+			var levels struct {
+				Data []struct {
+					LocationID string `json:"location_id"`
+					OnHand     int    `json:"on_hand"`
+					InTransit  int    `json:"in_transit"`
+				} `json:"data"`
+			}
+			json.NewDecoder(rr.Body).Decode(&levels)
+
+			result := map[string][2]int{}
+			for _, l := range levels.Data {
+				result[l.LocationID] = [2]int{l.OnHand, l.InTransit}
+			}
+			return result
+		}
+
+		// Shipped stock has left the source and is on its way to the shop
+		levels := levelsAt()
+		if levels[main] != [2]int{1, 0} || levels[shop.Data.ID] != [2]int{0, 2} {
+			t.Errorf("expected 1 on hand at the source and 2 in transit to the shop, got %v", levels)
+		}
+
+		rr = send("POST", path+"/receive", nil)
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		levels = levelsAt()
+		if levels[shop.Data.ID] != [2]int{2, 0} {
+			t.Errorf("expected 2 on hand at the shop, got %v", levels)
+		}
+
+		// A sale at the shop takes its stock, not the stock of the source
+		rr = send("POST", inventory+"/sales", map[string]interface{}{"location_id": shop.Data.ID, "quantity": 2})
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+		rr = send("POST", inventory+"/sales", map[string]interface{}{"location_id": shop.Data.ID, "quantity": 1})
+		if status := rr.Code; status != http.StatusConflict {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+		}
+
+		// More than is available at the source cannot be shipped
+		rr = send("POST", "/api/v1/admin/transfers", map[string]interface{}{
+			"from_location_id": main,
+			"to_location_id":   shop.Data.ID,
+			"items":            []map[string]interface{}{{"variant_id": variantID, "quantity": 5}},
+		})
+		json.NewDecoder(rr.Body).Decode(&transfer)
+		path = "/api/v1/admin/transfers/" + transfer.Data.ID
+
+		rr = send("POST", path+"/ship", nil)
+		if status := rr.Code; status != http.StatusConflict {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+		}
+
+		rr = send("POST", path+"/cancel", nil)
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		// Take the remaining item out so the variant can be removed later
+		rr = send("POST", inventory+"/adjustments", map[string]interface{}{"location_id": main, "type": "adjustment", "quantity": -1, "reason": "Integration test cleanup"})
+		if status := rr.Code; status != http.StatusCreated {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+	})
+
+	t.Run("Categories", func(t *testing.T) {
+		var parent, child struct {
+			Data struct {
+				ID   string `json:"id"`
+				Slug string `json:"slug"`
+			} `json:"data"`
+		}
+
+		rr := send("POST", "/api/v1/admin/categories", map[string]string{"name": "Integration Test Drinks"})
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+		json.NewDecoder(rr.Body).Decode(&parent)
+
+		rr = send("POST", "/api/v1/admin/categories", map[string]string{"name": "Integration Test Coffee", "parent_id": parent.Data.ID})
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+		json.NewDecoder(rr.Body).Decode(&child)
+		defer send("DELETE", "/api/v1/admin/categories/"+parent.Data.ID, nil)
+		defer send("DELETE", "/api/v1/admin/categories/"+child.Data.ID, nil)
+
+		// A category cannot move into its own subtree
+		rr = send("POST", "/api/v1/admin/categories/"+parent.Data.ID+"/move", map[string]interface{}{"parent_id": child.Data.ID, "position": 0})
+		if status := rr.Code; status != http.StatusUnprocessableEntity {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnprocessableEntity)
+		}
+
+		rr = send("PUT", "/api/v1/admin/products/"+created.Data.ID+"/categories", map[string]interface{}{"category_ids": []string{child.Data.ID}})
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		// Products of subcategories are listed under the parent
+		req := httptest.NewRequest("GET", "/api/v1/store/categories/"+parent.Data.Slug+"/products", nil)
+		rr = httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		var resp struct {
+			Data []struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		json.NewDecoder(rr.Body).Decode(&resp)
+
+		if len(resp.Data) != 1 || resp.Data[0].ID != created.Data.ID {
+			t.Errorf("expected the product in the parent category, got %d products", len(resp.Data))
+		}
+
+		rr = send("PUT", "/api/v1/admin/products/"+created.Data.ID+"/categories", map[string]interface{}{"category_ids": []string{}})
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+
+	t.Run("Delete Product", func(t *testing.T) {
+		rr := send("DELETE", "/api/v1/admin/products/"+created.Data.ID, nil)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/yeftaz/susano.id/api/internal/config"
 	"github.com/yeftaz/susano.id/api/internal/database"
@@ -13,9 +14,9 @@ import (
 	"github.com/yeftaz/susano.id/api/pkg/logger"
 )
 
-func setupTestRouter(t *testing.T) *http.Handler {
-	// Load test config
-	cfg := &config.Config{
+// testConfig returns the config the integration tests run with
+func testConfig() *config.Config {
+	return &config.Config{
 		AppEnv:          "test",
 		DBHost:          "localhost",
 		DBPort:          "5432",
@@ -23,9 +24,29 @@ func setupTestRouter(t *testing.T) *http.Handler {
 		DBUser:          "root",
 		DBPassword:      "",
 		DBSSLMode:       "disable",
-		SessionLifetime: 720 * 3600,
+		SessionLifetime: 720 * time.Hour,
+
+		AdminSessionIdleTimeout:    2 * time.Hour,
+		AdminSessionLifetime:       720 * time.Hour,
+		CustomerSessionIdleTimeout: 336 * time.Hour,
+		CustomerSessionLifetime:    720 * time.Hour,
+
+		AdminBearerTokens: true,
+
+		APIKeyRateLimit:   60,
+		APIKeyMaxLifetime: 8760 * time.Hour,
+
+		PasswordMinLength: 8,
+		PasswordHistory:   5,
 	}
+}
 
+func setupTestRouter(t *testing.T) *http.Handler {
+	return newTestRouter(t, testConfig())
+}
+
+// newTestRouter creates the router on the test database with the given config
+func newTestRouter(t *testing.T, cfg *config.Config) *http.Handler {
 	// Connect to test database
 	db, err := database.Connect(cfg)
 	if err != nil {
@@ -36,7 +57,10 @@ func setupTestRouter(t *testing.T) *http.Handler {
 	testLogger := logger.New(cfg)
 
 	// Create router
-	r := router.New(cfg, db, testLogger)
+	r, err := router.New(cfg, db, testLogger)
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
 	var handler http.Handler = r
 	return &handler
 }
@@ -139,9 +163,22 @@ func TestAdminLogout(t *testing.T) {
 		t.Fatal("No session cookie found after login")
 	}
 
+	t.Run("Logout Without CSRF Token", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/admin/auth/logout", nil)
+		req.AddCookie(sessionCookie)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusForbidden {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+		}
+	})
+
 	// Test logout
 	t.Run("Successful Logout", func(t *testing.T) {
 		req := httptest.NewRequest("POST", "/api/v1/admin/auth/logout", nil)
+		req.Header.Set("X-CSRF-Token", getCSRFToken(t, handler, sessionCookie))
 		req.AddCookie(sessionCookie)
 
 		rr := httptest.NewRecorder()
@@ -152,3 +189,218 @@ func TestAdminLogout(t *testing.T) {
 		}
 	})
 }
+
+func TestAdminBearerToken(t *testing.T) {
+	handler := setupTestRouter(t)
+
+	// First, login to get session token
+	loginData := map[string]string{
+		"email":    "admin@susano.id",
+		"password": "admin1234",
+	}
+
+	body, _ := json.Marshal(loginData)
+	req := httptest.NewRequest("POST", "/api/v1/admin/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	(*handler).ServeHTTP(rr, req)
+
+	var loginResp struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&loginResp); err != nil || loginResp.Data.Token == "" {
+		t.Fatal("No token found in login response")
+	}
+
+	t.Run("Authenticated With Bearer Token", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/admin/auth/me", nil)
+		req.Header.Set("Authorization", "Bearer "+loginResp.Data.Token)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+
+	t.Run("Logout With Bearer Token", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/admin/auth/logout", nil)
+		req.Header.Set("Authorization", "Bearer "+loginResp.Data.Token)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+}
+
+func TestAdminSessions(t *testing.T) {
+	handler := setupTestRouter(t)
+
+	// First, login to get session token
+	loginData := map[string]string{
+		"email":    "admin@susano.id",
+		"password": "admin1234",
+	}
+
+	body, _ := json.Marshal(loginData)
+	req := httptest.NewRequest("POST", "/api/v1/admin/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	(*handler).ServeHTTP(rr, req)
+
+	var sessionCookie *http.Cookie
+	for _, cookie := range rr.Result().Cookies() {
+		if cookie.Name == "session_token" {
+			sessionCookie = cookie
+			break
+		}
+	}
+
+	if sessionCookie == nil {
+		t.Fatal("No session cookie found after login")
+	}
+
+	t.Run("List Sessions", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/admin/auth/sessions", nil)
+		req.AddCookie(sessionCookie)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		var resp struct {
+			Data []struct {
+				Current bool `json:"current"`
+			} `json:"data"`
+		}
+		_ = json.NewDecoder(rr.Body).Decode(&resp)
+
+		current := 0
+		for _, session := range resp.Data {
+			if session.Current {
+				current++
+			}
+		}
+		if current != 1 {
+			t.Errorf("Expected exactly one current session, got %d", current)
+		}
+	})
+
+	t.Run("Revoke Other Sessions", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/api/v1/admin/auth/sessions", nil)
+		req.Header.Set("X-CSRF-Token", getCSRFToken(t, handler, sessionCookie))
+		req.AddCookie(sessionCookie)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+}
+
+func TestAdminAPIKeys(t *testing.T) {
+	handler := setupTestRouter(t)
+	sessionCookie := loginAndGetSessionCookie(t, handler)
+	csrfToken := getCSRFToken(t, handler, sessionCookie)
+
+	keyData := map[string]interface{}{
+		"name":       "ERP sync",
+		"scopes":     []string{"admins.view"},
+		"expires_at": time.Now().Add(24 * time.Hour),
+	}
+
+	body, _ := json.Marshal(keyData)
+	req := httptest.NewRequest("POST", "/api/v1/admin/auth/api-keys", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-CSRF-Token", csrfToken)
+	req.AddCookie(sessionCookie)
+
+	rr := httptest.NewRecorder()
+	(*handler).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	var createResp struct {
+		Data struct {
+			ID  string `json:"id"`
+			Key string `json:"key"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&createResp); err != nil || createResp.Data.Key == "" {
+		t.Fatal("No key found in create response")
+	}
+
+	t.Run("Scoped Request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/admin/admins", nil)
+		req.Header.Set("Authorization", "Bearer "+createResp.Data.Key)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+
+	t.Run("Out Of Scope Request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/admin/audit-logs", nil)
+		req.Header.Set("Authorization", "Bearer "+createResp.Data.Key)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusForbidden {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+		}
+	})
+
+	t.Run("Self-Service Route", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/admin/auth/sessions", nil)
+		req.Header.Set("Authorization", "Bearer "+createResp.Data.Key)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusForbidden {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusForbidden)
+		}
+	})
+
+	t.Run("Revoked Key", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/api/v1/admin/auth/api-keys/"+createResp.Data.ID, nil)
+		req.Header.Set("X-CSRF-Token", csrfToken)
+		req.AddCookie(sessionCookie)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		req = httptest.NewRequest("GET", "/api/v1/admin/admins", nil)
+		req.Header.Set("Authorization", "Bearer "+createResp.Data.Key)
+
+		rr = httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusUnauthorized {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+		}
+	})
+}
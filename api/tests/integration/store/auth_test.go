@@ -6,15 +6,20 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/yeftaz/susano.id/api/internal/config"
 	"github.com/yeftaz/susano.id/api/internal/database"
 	"github.com/yeftaz/susano.id/api/internal/router"
 	"github.com/yeftaz/susano.id/api/pkg/logger"
+	"github.com/yeftaz/susano.id/api/pkg/oidc/oidctest"
 )
 
-func setupTestRouter(t *testing.T) *http.Handler {
-	cfg := &config.Config{
+// testConfig returns the config the integration tests run with
+func testConfig(providers ...config.OIDCProvider) *config.Config {
+	return &config.Config{
 		AppEnv:          "test",
 		DBHost:          "localhost",
 		DBPort:          "5432",
@@ -22,16 +27,40 @@ func setupTestRouter(t *testing.T) *http.Handler {
 		DBUser:          "root",
 		DBPassword:      "",
 		DBSSLMode:       "disable",
-		SessionLifetime: 720 * 3600,
+		SessionLifetime: 720 * time.Hour,
+
+		AdminSessionIdleTimeout:    2 * time.Hour,
+		AdminSessionLifetime:       720 * time.Hour,
+		CustomerSessionIdleTimeout: 336 * time.Hour,
+		CustomerSessionLifetime:    720 * time.Hour,
+
+		MagicLinkLifetime:        15 * time.Minute,
+		MagicLinkRequestInterval: time.Minute,
+
+		OIDCProviders:     providers,
+		OIDCStateLifetime: 10 * time.Minute,
+
+		PasswordMinLength: 8,
+		PasswordHistory:   5,
 	}
+}
 
+func setupTestRouter(t *testing.T, providers ...config.OIDCProvider) *http.Handler {
+	return newTestRouter(t, testConfig(providers...))
+}
+
+// newTestRouter creates the router on the test database with the given config
+func newTestRouter(t *testing.T, cfg *config.Config) *http.Handler {
 	db, err := database.Connect(cfg)
 	if err != nil {
 		t.Fatalf("Failed to connect to test database: %v", err)
 	}
 
 	testLogger := logger.New(cfg)
-	r := router.New(cfg, db, testLogger)
+	r, err := router.New(cfg, db, testLogger)
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
 	var handler http.Handler = r
 	return &handler
 }
@@ -53,8 +82,47 @@ func TestCustomerRegister(t *testing.T) {
 		rr := httptest.NewRecorder()
 		(*handler).ServeHTTP(rr, req)
 
-		// TODO: Implement when customer registration is ready
-		t.Skip("Customer registration not yet implemented")
+		// Conflict means the customer exists from a previous run
+		if status := rr.Code; status != http.StatusCreated && status != http.StatusConflict {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+	})
+
+	t.Run("Duplicate Email", func(t *testing.T) {
+		registerData := map[string]string{
+			"email":    "customer@example.com",
+			"password": "password123",
+			"name":     "Test Customer",
+		}
+
+		body, _ := json.Marshal(registerData)
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/register", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusConflict {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+		}
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		registerData := map[string]string{
+			"email":    "invalid-email",
+			"password": "short",
+		}
+
+		body, _ := json.Marshal(registerData)
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/register", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusUnprocessableEntity {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnprocessableEntity)
+		}
 	})
 }
 
@@ -74,7 +142,270 @@ func TestCustomerLogin(t *testing.T) {
 		rr := httptest.NewRecorder()
 		(*handler).ServeHTTP(rr, req)
 
-		// TODO: Implement when customer login is ready
-		t.Skip("Customer login not yet implemented")
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		// Check session cookie
+		found := false
+		for _, cookie := range rr.Result().Cookies() {
+			if cookie.Name == "customer_session_token" && cookie.Value != "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Error("Expected customer_session_token cookie to be set")
+		}
+	})
+
+	t.Run("Invalid Credentials", func(t *testing.T) {
+		loginData := map[string]string{
+			"email":    "customer@example.com",
+			"password": "wrongpassword",
+		}
+
+		body, _ := json.Marshal(loginData)
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusUnauthorized {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestCustomerMagicLink(t *testing.T) {
+	handler := setupTestRouter(t)
+
+	t.Run("Request Binds Browser", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"email": "magic-link@example.com"})
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/magic-link", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", "http://localhost:3000")
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		found := false
+		for _, cookie := range rr.Result().Cookies() {
+			if cookie.Name == "magic_link_binding" && cookie.Value != "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Error("Expected magic_link_binding cookie to be set")
+		}
+	})
+
+	t.Run("Request Throttled Per Email", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"email": "Magic-Link@example.com"})
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/magic-link", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusTooManyRequests {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusTooManyRequests)
+		}
+	})
+
+	t.Run("Invalid Link", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{
+			"token":     "invalid",
+			"expires":   "9999999999",
+			"signature": "invalid",
+		})
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/magic-link/verify", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusUnauthorized {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestCustomerOIDCLogin(t *testing.T) {
+	provider, err := oidctest.NewServer("susano", "secret")
+	if err != nil {
+		t.Fatalf("Failed to start mock provider: %v", err)
+	}
+	defer provider.Close()
+
+	provider.SetUser(oidctest.User{Subject: "oidc-user-1", Email: "oidc-customer@example.com", EmailVerified: true, Name: "OIDC Customer"})
+
+	handler := setupTestRouter(t, config.OIDCProvider{
+		Name:         "mock",
+		Issuer:       provider.Issuer(),
+		ClientID:     "susano",
+		ClientSecret: "secret",
+		Scopes:       []string{"openid", "email", "profile"},
+		RedirectURL:  "http://localhost:3000/auth/oidc/mock/callback",
+	})
+
+	// authorize starts a login and returns the state cookie and the callback parameters from the provider
+	authorize := func(t *testing.T) (*http.Cookie, map[string]string) {
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/oidc/mock", nil)
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		var resp struct {
+			Data struct {
+				AuthorizationURL string `json:"authorization_url"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		var stateCookie *http.Cookie
+		for _, cookie := range rr.Result().Cookies() {
+			if cookie.Name == "oidc_state" {
+				stateCookie = cookie
+			}
+		}
+		if stateCookie == nil {
+			t.Fatal("Expected oidc_state cookie to be set")
+		}
+
+		code, state, err := provider.Authorize(resp.Data.AuthorizationURL)
+		if err != nil {
+			t.Fatalf("Failed to authorize at the provider: %v", err)
+		}
+
+		return stateCookie, map[string]string{"state": state, "code": code}
+	}
+
+	t.Run("Login Creates Session", func(t *testing.T) {
+		stateCookie, params := authorize(t)
+
+		body, _ := json.Marshal(params)
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/oidc/mock/callback", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(stateCookie)
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		found := false
+		for _, cookie := range rr.Result().Cookies() {
+			if cookie.Name == "customer_session_token" && cookie.Value != "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Error("Expected customer_session_token cookie to be set")
+		}
+	})
+
+	t.Run("Callback From Another Browser", func(t *testing.T) {
+		_, params := authorize(t)
+
+		body, _ := json.Marshal(params)
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/oidc/mock/callback", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusUnauthorized {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("Unknown Provider", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/oidc/unknown", nil)
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+		}
+	})
+
+	t.Run("Login Shuts Out Unverified Registration", func(t *testing.T) {
+		email := "oidc-" + uuid.NewString() + "@example.com"
+
+		// Someone else registers the address first with their own password and signs in
+		body, _ := json.Marshal(map[string]string{"email": email, "password": "password123", "name": "Squatter"})
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/register", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+		if status := rr.Code; status != http.StatusCreated {
+			t.Fatalf("register returned wrong status code: got %v want %v", status, http.StatusCreated)
+		}
+
+		passwordLogin := func() *httptest.ResponseRecorder {
+			body, _ := json.Marshal(map[string]string{"email": email, "password": "password123"})
+			req := httptest.NewRequest("POST", "/api/v1/store/auth/login", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			(*handler).ServeHTTP(rr, req)
+			return rr
+		}
+
+		rr = passwordLogin()
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("login returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+		var squatterSession *http.Cookie
+		for _, cookie := range rr.Result().Cookies() {
+			if cookie.Name == "customer_session_token" {
+				squatterSession = cookie
+			}
+		}
+		if squatterSession == nil {
+			t.Fatal("Expected customer_session_token cookie to be set")
+		}
+
+		// The owner of the address signs in at the provider
+		provider.SetUser(oidctest.User{Subject: "oidc-" + uuid.NewString(), Email: email, EmailVerified: true, Name: "Owner"})
+		stateCookie, params := authorize(t)
+
+		body, _ = json.Marshal(params)
+		req = httptest.NewRequest("POST", "/api/v1/store/auth/oidc/mock/callback", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(stateCookie)
+		rr = httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("callback returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+
+		// The earlier password no longer works
+		if status := passwordLogin().Code; status != http.StatusUnauthorized {
+			t.Errorf("login returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+		}
+
+		// And the earlier session was signed out
+		req = httptest.NewRequest("GET", "/api/v1/store/auth/sessions", nil)
+		req.AddCookie(squatterSession)
+		rr = httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+		if status := rr.Code; status != http.StatusUnauthorized {
+			t.Errorf("sessions returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+		}
 	})
 }
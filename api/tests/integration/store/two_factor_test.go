@@ -0,0 +1,101 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yeftaz/susano.id/api/internal/database"
+	storeRepo "github.com/yeftaz/susano.id/api/internal/repository/store"
+	"github.com/yeftaz/susano.id/api/pkg/twofactor"
+)
+
+func TestCustomerTwoFactorLockout(t *testing.T) {
+	cfg := testConfig()
+	cfg.LoginMaxAttempts = 3
+	cfg.LoginLockoutDuration = 15 * time.Minute
+	cfg.TwoFactorChallengeLifetime = 5 * time.Minute
+	handler := newTestRouter(t, cfg)
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	// Enroll a fresh customer in 2FA
+	ctx := context.Background()
+	email := "two-factor-" + uuid.NewString() + "@example.com"
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	customers := storeRepo.NewCustomerRepository(db)
+	customer, err := customers.Create(ctx, email, string(hash), "Two Factor")
+	if err != nil {
+		t.Fatalf("Failed to create customer: %v", err)
+	}
+	secret, _ := twofactor.GenerateSecret()
+	if err := customers.UpdatePendingTwoFactorSecret(ctx, customer.ID.String(), secret); err != nil {
+		t.Fatalf("Failed to set two-factor secret: %v", err)
+	}
+	if err := customers.ConfirmTwoFactor(ctx, customer.ID.String(), secret, "[]", 0); err != nil {
+		t.Fatalf("Failed to confirm two-factor: %v", err)
+	}
+
+	login := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"email": email, "password": "password123"})
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Every wrong code is made on a fresh challenge, so only the account lockout can stop it
+	for i := 0; i < cfg.LoginMaxAttempts; i++ {
+		rr := login()
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("login %d returned wrong status code: got %v want %v", i+1, status, http.StatusOK)
+		}
+
+		var response struct {
+			Data struct {
+				ChallengeToken string `json:"challenge_token"`
+			} `json:"data"`
+		}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+
+		body, _ := json.Marshal(map[string]string{
+			"challenge_token": response.Data.ChallengeToken,
+			"code":            wrongCode(t, secret),
+		})
+		req := httptest.NewRequest("POST", "/api/v1/store/auth/2fa/verify", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rr = httptest.NewRecorder()
+		(*handler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusUnauthorized {
+			t.Fatalf("verify %d returned wrong status code: got %v want %v", i+1, status, http.StatusUnauthorized)
+		}
+	}
+
+	// The correct password no longer gets a new challenge
+	if status := login().Code; status != http.StatusLocked {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusLocked)
+	}
+}
+
+// wrongCode returns a code that differs from the current code of the secret
+func wrongCode(t *testing.T, secret string) string {
+	code, err := twofactor.GenerateCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+	return string('0'+(code[0]-'0'+5)%10) + code[1:]
+}
@@ -2,12 +2,18 @@ package admin_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/yeftaz/susano.id/api/internal/config"
 	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
 	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	sharedRepo "github.com/yeftaz/susano.id/api/internal/repository/shared"
 	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
 )
 
 func setupAdminService(t *testing.T) *adminService.AdminService {
@@ -26,7 +32,12 @@ func setupAdminService(t *testing.T) *adminService.AdminService {
 	}
 
 	adminRepository := adminRepo.NewAdminRepository(db)
-	return adminService.NewAdminService(adminRepository)
+	auditService := adminService.NewAuditService(adminRepo.NewAuditLogRepository(db))
+	passwordPolicyService, err := sharedService.NewPasswordPolicyService(sharedRepo.NewPasswordHistoryRepository(db), logger.New(cfg), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create password policy service: %v", err)
+	}
+	return adminService.NewAdminService(adminRepository, passwordPolicyService, auditService)
 }
 
 func TestGetAllAdmins(t *testing.T) {
@@ -53,9 +64,10 @@ func TestGetAllAdmins(t *testing.T) {
 func TestCreateAdmin(t *testing.T) {
 	service := setupAdminService(t)
 	ctx := context.Background()
+	superAdmin := adminDomain.Actor{Admin: &adminDomain.Admin{Role: adminDomain.RoleSuperAdmin}, IPAddress: "127.0.0.1"}
 
 	t.Run("Create Admin", func(t *testing.T) {
-		admin, err := service.Create(ctx, "test@susano.id", "password123", "Test Admin", "admin")
+		admin, err := service.Create(ctx, superAdmin, "test@susano.id", "password123", "Test Admin", "admin")
 
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
@@ -70,6 +82,26 @@ func TestCreateAdmin(t *testing.T) {
 		}
 
 		// Cleanup: delete test admin
-		_ = service.Delete(ctx, admin.ID.String())
+		_ = service.Delete(ctx, superAdmin, admin.ID.String())
+	})
+
+	t.Run("Cannot Grant Role Above Own", func(t *testing.T) {
+		cashier := adminDomain.Actor{Admin: &adminDomain.Admin{Role: adminDomain.RoleCashier}, IPAddress: "127.0.0.1"}
+
+		_, err := service.Create(ctx, cashier, "escalate@susano.id", "password123", "Escalation", "super_admin")
+
+		if !errors.Is(err, domain.ErrRoleNotGrantable) {
+			t.Errorf("Expected ErrRoleNotGrantable, got %v", err)
+		}
+	})
+
+	t.Run("Unknown Role", func(t *testing.T) {
+		for _, role := range []string{"", "owner"} {
+			_, err := service.Create(ctx, superAdmin, "unknown-role@susano.id", "password123", "Unknown Role", role)
+
+			if !errors.Is(err, domain.ErrInvalidRole) {
+				t.Errorf("Expected ErrInvalidRole for role %q, got %v", role, err)
+			}
+		}
 	})
 }
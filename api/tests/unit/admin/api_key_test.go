@@ -0,0 +1,50 @@
+package admin_test
+
+import (
+	"testing"
+	"time"
+
+	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
+)
+
+func TestAPIKeyUsable(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name string
+		key  adminDomain.APIKey
+		want bool
+	}{
+		{"Active", adminDomain.APIKey{ExpiresAt: time.Now().Add(time.Hour)}, true},
+		{"Expired", adminDomain.APIKey{ExpiresAt: past}, false},
+		{"Revoked", adminDomain.APIKey{ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.key.IsUsable(); got != tt.want {
+			t.Errorf("%s: expected usable %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	key := &adminDomain.APIKey{Scopes: []adminDomain.Permission{adminDomain.PermissionAdminsView}}
+
+	if !key.HasScope(adminDomain.PermissionAdminsView) {
+		t.Error("Expected key to be scoped for admins.view")
+	}
+
+	if key.HasScope(adminDomain.PermissionAdminsDelete) {
+		t.Error("Expected key not to be scoped for admins.delete")
+	}
+}
+
+func TestIsAPIKey(t *testing.T) {
+	if !adminDomain.IsAPIKey(adminDomain.APIKeyPrefix + "abc") {
+		t.Error("Expected prefixed token to be an API key")
+	}
+
+	if adminDomain.IsAPIKey("c2Vzc2lvbi10b2tlbg") {
+		t.Error("Expected session token not to be an API key")
+	}
+}
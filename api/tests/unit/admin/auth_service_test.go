@@ -7,7 +7,10 @@ import (
 	"github.com/yeftaz/susano.id/api/internal/config"
 	"github.com/yeftaz/susano.id/api/internal/database"
 	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	sharedRepo "github.com/yeftaz/susano.id/api/internal/repository/shared"
 	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+	"github.com/yeftaz/susano.id/api/pkg/logger"
 )
 
 func setupAuthService(t *testing.T) *adminService.AuthService {
@@ -27,8 +30,18 @@ func setupAuthService(t *testing.T) *adminService.AuthService {
 
 	adminRepository := adminRepo.NewAdminRepository(db)
 	sessionRepository := adminRepo.NewSessionRepository(db)
+	challengeRepository := adminRepo.NewTwoFactorChallengeRepository(db)
+	loginThrottleService := sharedService.NewLoginThrottleService(sharedRepo.NewLoginAttemptRepository(db), nil, logger.New(cfg), cfg)
 
-	return adminService.NewAuthService(adminRepository, sessionRepository)
+	loginHistoryService := sharedService.NewLoginHistoryService(sharedRepo.NewLoginHistoryRepository(db), nil, logger.New(cfg))
+	passkeyService := adminService.NewPasskeyService(adminRepository, adminRepo.NewPasskeyRepository(db), adminRepo.NewWebAuthnChallengeRepository(db), cfg)
+	passwordPolicyService, err := sharedService.NewPasswordPolicyService(sharedRepo.NewPasswordHistoryRepository(db), logger.New(cfg), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create password policy service: %v", err)
+	}
+	auditService := adminService.NewAuditService(adminRepo.NewAuditLogRepository(db))
+
+	return adminService.NewAuthService(adminRepository, sessionRepository, challengeRepository, loginThrottleService, loginHistoryService, passkeyService, passwordPolicyService, auditService, cfg)
 }
 
 func TestLogin(t *testing.T) {
@@ -36,27 +49,27 @@ func TestLogin(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Successful Login", func(t *testing.T) {
-		admin, session, err := authService.Login(ctx, "admin@susano.id", "admin1234", "127.0.0.1", "test-agent")
+		result, err := authService.Login(ctx, "admin@susano.id", "admin1234", "127.0.0.1", "test-agent")
 
 		if err != nil {
-			t.Errorf("Expected no error, got %v", err)
+			t.Fatalf("Expected no error, got %v", err)
 		}
 
-		if admin == nil {
+		if result.Admin == nil {
 			t.Error("Expected admin to be returned")
 		}
 
-		if session == nil {
+		if result.Session == nil {
 			t.Error("Expected session to be returned")
 		}
 
-		if admin.Email != "admin@susano.id" {
-			t.Errorf("Expected email admin@susano.id, got %s", admin.Email)
+		if result.Admin.Email != "admin@susano.id" {
+			t.Errorf("Expected email admin@susano.id, got %s", result.Admin.Email)
 		}
 	})
 
 	t.Run("Invalid Password", func(t *testing.T) {
-		_, _, err := authService.Login(ctx, "admin@susano.id", "wrongpassword", "127.0.0.1", "test-agent")
+		_, err := authService.Login(ctx, "admin@susano.id", "wrongpassword", "127.0.0.1", "test-agent")
 
 		if err == nil {
 			t.Error("Expected error for invalid password")
@@ -64,7 +77,7 @@ func TestLogin(t *testing.T) {
 	})
 
 	t.Run("Invalid Email", func(t *testing.T) {
-		_, _, err := authService.Login(ctx, "nonexistent@susano.id", "admin1234", "127.0.0.1", "test-agent")
+		_, err := authService.Login(ctx, "nonexistent@susano.id", "admin1234", "127.0.0.1", "test-agent")
 
 		if err == nil {
 			t.Error("Expected error for invalid email")
@@ -0,0 +1,52 @@
+package admin_test
+
+import (
+	"testing"
+
+	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
+)
+
+func TestCanGrant(t *testing.T) {
+	tests := []struct {
+		actor adminDomain.AdminRole
+		role  adminDomain.AdminRole
+		want  bool
+	}{
+		{adminDomain.RoleSuperAdmin, adminDomain.RoleSuperAdmin, true},
+		{adminDomain.RoleSuperAdmin, adminDomain.RoleCashier, true},
+		{adminDomain.RoleAdmin, adminDomain.RoleAdmin, true},
+		{adminDomain.RoleAdmin, adminDomain.RoleSuperAdmin, false},
+		{adminDomain.RoleCashier, adminDomain.RoleAdmin, false},
+		{adminDomain.RoleCashier, adminDomain.RoleSuperAdmin, false},
+		{adminDomain.RoleSuperAdmin, adminDomain.AdminRole("owner"), false},
+	}
+
+	for _, tt := range tests {
+		actor := &adminDomain.Admin{Role: tt.actor}
+		if got := actor.CanGrant(tt.role); got != tt.want {
+			t.Errorf("%s granting %s: expected %v, got %v", tt.actor, tt.role, tt.want, got)
+		}
+	}
+}
+
+func TestCanManage(t *testing.T) {
+	admin := &adminDomain.Admin{Role: adminDomain.RoleAdmin}
+
+	if !admin.CanManage(&adminDomain.Admin{Role: adminDomain.RoleCashier}) {
+		t.Error("Expected admin to manage a cashier")
+	}
+
+	if admin.CanManage(&adminDomain.Admin{Role: adminDomain.RoleSuperAdmin}) {
+		t.Error("Expected admin not to manage a super admin")
+	}
+}
+
+func TestPermissionRegistry(t *testing.T) {
+	if !adminDomain.PermissionAdminsCreate.IsValid() {
+		t.Error("Expected admins.create to be registered")
+	}
+
+	if adminDomain.Permission("admins.destroy_everything").IsValid() {
+		t.Error("Expected unknown permission to be invalid")
+	}
+}
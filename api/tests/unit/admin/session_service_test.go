@@ -0,0 +1,62 @@
+package admin_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/database"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	adminDomain "github.com/yeftaz/susano.id/api/internal/domain/admin"
+	adminRepo "github.com/yeftaz/susano.id/api/internal/repository/admin"
+	adminService "github.com/yeftaz/susano.id/api/internal/service/admin"
+)
+
+func TestRevokeAllSessions(t *testing.T) {
+	cfg := &config.Config{
+		DBHost:     "localhost",
+		DBPort:     "5432",
+		DBName:     "susano_test",
+		DBUser:     "root",
+		DBPassword: "",
+		DBSSLMode:  "disable",
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	adminRepository := adminRepo.NewAdminRepository(db)
+	auditService := adminService.NewAuditService(adminRepo.NewAuditLogRepository(db))
+	service := adminService.NewSessionService(adminRepository, adminRepo.NewSessionRepository(db), nil, auditService)
+	ctx := context.Background()
+
+	target, err := adminRepository.Create(ctx, "revoke-"+uuid.NewString()+"@susano.id", "", "Revoke Target", string(adminDomain.RoleSuperAdmin))
+	if err != nil {
+		t.Fatalf("Failed to create admin: %v", err)
+	}
+	defer adminRepository.Delete(ctx, target.ID.String())
+
+	t.Run("Cannot Revoke Admin Above Own Role", func(t *testing.T) {
+		cashier := adminDomain.Actor{Admin: &adminDomain.Admin{Role: adminDomain.RoleCashier}, IPAddress: "127.0.0.1"}
+
+		err := service.RevokeAll(ctx, cashier, target.ID.String())
+
+		if !errors.Is(err, domain.ErrRoleNotGrantable) {
+			t.Errorf("Expected ErrRoleNotGrantable, got %v", err)
+		}
+	})
+
+	t.Run("Revoke Admin Of Same Role", func(t *testing.T) {
+		// The actor must exist, as the audit entry refers to it
+		superAdmin := adminDomain.Actor{Admin: target, IPAddress: "127.0.0.1"}
+
+		if err := service.RevokeAll(ctx, superAdmin, target.ID.String()); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+func TestCategoryPath(t *testing.T) {
+	root := uuid.New()
+	child := uuid.New()
+
+	rootPath := catalog.CategoryPath("", root)
+	if want := "/" + root.String() + "/"; rootPath != want {
+		t.Errorf("expected root path %q, got %q", want, rootPath)
+	}
+
+	childPath := catalog.CategoryPath(rootPath, child)
+	if want := "/" + root.String() + "/" + child.String() + "/"; childPath != want {
+		t.Errorf("expected child path %q, got %q", want, childPath)
+	}
+}
+
+func TestCategoryContains(t *testing.T) {
+	root := &catalog.Category{ID: uuid.New()}
+	root.Path = catalog.CategoryPath("", root.ID)
+	child := &catalog.Category{ID: uuid.New()}
+	child.Path = catalog.CategoryPath(root.Path, child.ID)
+	other := &catalog.Category{ID: uuid.New()}
+	other.Path = catalog.CategoryPath("", other.ID)
+
+	if !root.Contains(root) {
+		t.Error("expected a category to contain itself")
+	}
+	if !root.Contains(child) {
+		t.Error("expected a category to contain its child")
+	}
+	if child.Contains(root) {
+		t.Error("expected a child not to contain its parent")
+	}
+	if root.Contains(other) {
+		t.Error("expected a category not to contain another root")
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	food := &catalog.Category{ID: uuid.New(), Name: "Food"}
+	drinks := &catalog.Category{ID: uuid.New(), Name: "Drinks"}
+	coffee := &catalog.Category{ID: uuid.New(), ParentID: &drinks.ID, Name: "Coffee"}
+	tea := &catalog.Category{ID: uuid.New(), ParentID: &drinks.ID, Name: "Tea"}
+	espresso := &catalog.Category{ID: uuid.New(), ParentID: &coffee.ID, Name: "Espresso"}
+	missing := uuid.New()
+	orphan := &catalog.Category{ID: uuid.New(), ParentID: &missing, Name: "Orphan"}
+
+	roots := catalog.BuildCategoryTree([]*catalog.Category{food, drinks, coffee, tea, espresso, orphan})
+
+	if len(roots) != 3 || roots[0] != food || roots[1] != drinks || roots[2] != orphan {
+		t.Fatalf("expected roots food, drinks and orphan, got %d roots", len(roots))
+	}
+	if len(drinks.Children) != 2 || drinks.Children[0] != coffee || drinks.Children[1] != tea {
+		t.Errorf("expected drinks to have children coffee and tea in order, got %d children", len(drinks.Children))
+	}
+	if len(coffee.Children) != 1 || coffee.Children[0] != espresso {
+		t.Errorf("expected coffee to have child espresso, got %d children", len(coffee.Children))
+	}
+	if len(food.Children) != 0 {
+		t.Errorf("expected food to have no children, got %d", len(food.Children))
+	}
+}
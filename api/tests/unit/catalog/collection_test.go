@@ -0,0 +1,49 @@
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+func TestCollectionType(t *testing.T) {
+	for _, typ := range []catalog.CollectionType{catalog.CollectionTypeManual, catalog.CollectionTypeAutomated} {
+		if !typ.IsValid() {
+			t.Errorf("expected %q to be valid", typ)
+		}
+	}
+
+	for _, typ := range []catalog.CollectionType{"", "smart", "MANUAL"} {
+		if typ.IsValid() {
+			t.Errorf("expected %q to be invalid", typ)
+		}
+	}
+}
+
+func TestCollectionRuleIsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule catalog.CollectionRule
+		want bool
+	}{
+		{"price below", catalog.CollectionRule{Field: "price", Operator: "lt", Value: "100000"}, true},
+		{"price zero", catalog.CollectionRule{Field: "price", Operator: "gte", Value: "0"}, true},
+		{"price negative", catalog.CollectionRule{Field: "price", Operator: "gt", Value: "-1"}, false},
+		{"price not a number", catalog.CollectionRule{Field: "price", Operator: "eq", Value: "cheap"}, false},
+		{"price contains", catalog.CollectionRule{Field: "price", Operator: "contains", Value: "100"}, false},
+		{"tag equals", catalog.CollectionRule{Field: "tag", Operator: "eq", Value: "sale"}, true},
+		{"tag greater", catalog.CollectionRule{Field: "tag", Operator: "gt", Value: "sale"}, false},
+		{"name contains", catalog.CollectionRule{Field: "name", Operator: "contains", Value: "kopi"}, true},
+		{"empty value", catalog.CollectionRule{Field: "name", Operator: "eq", Value: ""}, false},
+		{"unknown field", catalog.CollectionRule{Field: "stock", Operator: "gt", Value: "0"}, false},
+		{"unknown operator", catalog.CollectionRule{Field: "name", Operator: "like", Value: "kopi"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.IsValid(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package catalog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+func TestMovementType(t *testing.T) {
+	for _, typ := range []catalog.MovementType{"receipt", "sale", "return", "adjustment", "transfer"} {
+		if !typ.IsValid() {
+			t.Errorf("expected %q to be valid", typ)
+		}
+	}
+	if catalog.MovementType("restock").IsValid() {
+		t.Error("expected an unknown type to be invalid")
+	}
+
+	for _, typ := range []catalog.MovementType{"receipt", "return", "adjustment"} {
+		if !typ.IsManual() {
+			t.Errorf("expected %q to be recordable by hand", typ)
+		}
+	}
+	for _, typ := range []catalog.MovementType{"sale", "transfer"} {
+		if typ.IsManual() {
+			t.Errorf("expected %q not to be recordable by hand", typ)
+		}
+	}
+}
+
+func TestMovementTypeAllowsQuantity(t *testing.T) {
+	tests := []struct {
+		typ      catalog.MovementType
+		quantity int
+		want     bool
+	}{
+		{catalog.MovementTypeReceipt, 5, true},
+		{catalog.MovementTypeReceipt, -5, false},
+		{catalog.MovementTypeReturn, 1, true},
+		{catalog.MovementTypeReturn, -1, false},
+		{catalog.MovementTypeSale, -1, true},
+		{catalog.MovementTypeSale, 1, false},
+		{catalog.MovementTypeAdjustment, 3, true},
+		{catalog.MovementTypeAdjustment, -3, true},
+		{catalog.MovementTypeAdjustment, 0, false},
+		{"unknown", 1, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.typ.AllowsQuantity(tt.quantity); got != tt.want {
+			t.Errorf("%s of %d: expected %v, got %v", tt.typ, tt.quantity, tt.want, got)
+		}
+	}
+}
+
+func TestReservationIsActive(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		reservation catalog.Reservation
+		want        bool
+	}{
+		{"active", catalog.Reservation{Status: catalog.ReservationStatusActive, ExpiresAt: now.Add(time.Minute)}, true},
+		{"expired", catalog.Reservation{Status: catalog.ReservationStatusActive, ExpiresAt: now.Add(-time.Minute)}, false},
+		{"released", catalog.Reservation{Status: catalog.ReservationStatusReleased, ExpiresAt: now.Add(time.Minute)}, false},
+		{"committed", catalog.Reservation{Status: catalog.ReservationStatusCommitted, ExpiresAt: now.Add(time.Minute)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reservation.IsActive(now); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMergeReservationItems(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	merged := catalog.MergeReservationItems([]catalog.ReservationItem{
+		{VariantID: a, Quantity: 1},
+		{VariantID: b, Quantity: 2},
+		{VariantID: a, Quantity: 3},
+	})
+
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(merged))
+	}
+	if merged[0].VariantID != a || merged[0].Quantity != 4 {
+		t.Errorf("expected 4 of the first variant, got %d of %s", merged[0].Quantity, merged[0].VariantID)
+	}
+	if merged[1].VariantID != b || merged[1].Quantity != 2 {
+		t.Errorf("expected 2 of the second variant, got %d of %s", merged[1].Quantity, merged[1].VariantID)
+	}
+}
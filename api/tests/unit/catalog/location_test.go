@@ -0,0 +1,94 @@
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+func TestLocationType(t *testing.T) {
+	for _, typ := range []catalog.LocationType{"warehouse", "store"} {
+		if !typ.IsValid() {
+			t.Errorf("expected %q to be valid", typ)
+		}
+	}
+	if catalog.LocationType("kiosk").IsValid() {
+		t.Error("expected an unknown type to be invalid")
+	}
+}
+
+func TestIsValidLocationCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"MAIN", true},
+		{"JKT-01", true},
+		{"BDG2", true},
+		{"", false},
+		{"jkt-01", false},
+		{"JKT--01", false},
+		{"-JKT", false},
+		{"JKT 01", false},
+	}
+
+	for _, tt := range tests {
+		if got := catalog.IsValidLocationCode(tt.code); got != tt.want {
+			t.Errorf("IsValidLocationCode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestTransferLifecycle(t *testing.T) {
+	tests := []struct {
+		status                         catalog.TransferStatus
+		canShip, canReceive, canCancel bool
+	}{
+		{catalog.TransferStatusDraft, true, false, true},
+		{catalog.TransferStatusInTransit, false, true, true},
+		{catalog.TransferStatusReceived, false, false, false},
+		{catalog.TransferStatusCancelled, false, false, false},
+	}
+
+	for _, tt := range tests {
+		transfer := &catalog.TransferOrder{Status: tt.status}
+		if !tt.status.IsValid() {
+			t.Errorf("expected %q to be valid", tt.status)
+		}
+		if got := transfer.CanShip(); got != tt.canShip {
+			t.Errorf("%s: CanShip() = %v, want %v", tt.status, got, tt.canShip)
+		}
+		if got := transfer.CanReceive(); got != tt.canReceive {
+			t.Errorf("%s: CanReceive() = %v, want %v", tt.status, got, tt.canReceive)
+		}
+		if got := transfer.CanCancel(); got != tt.canCancel {
+			t.Errorf("%s: CanCancel() = %v, want %v", tt.status, got, tt.canCancel)
+		}
+	}
+
+	if catalog.TransferStatus("lost").IsValid() {
+		t.Error("expected an unknown status to be invalid")
+	}
+}
+
+func TestMergeTransferItems(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	merged := catalog.MergeTransferItems([]catalog.TransferItem{
+		{VariantID: a, Quantity: 2},
+		{VariantID: b, Quantity: 1},
+		{VariantID: a, Quantity: 3},
+	})
+
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(merged))
+	}
+	if merged[0].VariantID != a || merged[0].Quantity != 5 {
+		t.Errorf("expected the first variant with quantity 5, got %+v", merged[0])
+	}
+	if merged[1].VariantID != b || merged[1].Quantity != 1 {
+		t.Errorf("expected the second variant with quantity 1, got %+v", merged[1])
+	}
+}
@@ -0,0 +1,76 @@
+package catalog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+func TestProductStatus(t *testing.T) {
+	for _, status := range []catalog.ProductStatus{catalog.ProductStatusDraft, catalog.ProductStatusActive, catalog.ProductStatusArchived} {
+		if !status.IsValid() {
+			t.Errorf("expected %q to be valid", status)
+		}
+	}
+
+	for _, status := range []catalog.ProductStatus{"", "published", "ACTIVE"} {
+		if status.IsValid() {
+			t.Errorf("expected %q to be invalid", status)
+		}
+	}
+}
+
+func TestProductIsPublished(t *testing.T) {
+	deletedAt := time.Now()
+
+	tests := []struct {
+		name    string
+		product catalog.Product
+		want    bool
+	}{
+		{"active", catalog.Product{Status: catalog.ProductStatusActive}, true},
+		{"draft", catalog.Product{Status: catalog.ProductStatusDraft}, false},
+		{"archived", catalog.Product{Status: catalog.ProductStatusArchived}, false},
+		{"deleted", catalog.Product{Status: catalog.ProductStatusActive, DeletedAt: &deletedAt}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.product.IsPublished(); got != tt.want {
+				t.Errorf("IsPublished() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Kopi Susu 250ml":    "kopi-susu-250ml",
+		"  Teh -- Tarik!  ":  "teh-tarik",
+		"Batik Tulis (Solo)": "batik-tulis-solo",
+		"already-a-slug":     "already-a-slug",
+		"Café au lait":       "caf-au-lait",
+		"!!!":                "",
+	}
+
+	for name, want := range tests {
+		if got := catalog.Slugify(name); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestIsValidSlug(t *testing.T) {
+	for _, slug := range []string{"kopi", "kopi-susu", "kopi-susu-250ml", "a1"} {
+		if !catalog.IsValidSlug(slug) {
+			t.Errorf("expected %q to be valid", slug)
+		}
+	}
+
+	for _, slug := range []string{"", "Kopi", "kopi--susu", "-kopi", "kopi-", "kopi susu", "kopi_susu"} {
+		if catalog.IsValidSlug(slug) {
+			t.Errorf("expected %q to be invalid", slug)
+		}
+	}
+}
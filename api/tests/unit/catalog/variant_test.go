@@ -0,0 +1,116 @@
+package catalog_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/catalog"
+)
+
+func TestBuildVariants(t *testing.T) {
+	options := []catalog.ProductOption{
+		{Name: "Size", Values: []string{"S", "M", "L"}},
+		{Name: "Color", Values: []string{"Red", "Navy Blue"}},
+	}
+
+	variants := catalog.BuildVariants("kaos-polos", options, nil, 75000)
+
+	if len(variants) != 6 || catalog.VariantCount(options) != 6 {
+		t.Fatalf("expected 6 variants, got %d", len(variants))
+	}
+
+	wantSKUs := []string{
+		"KAOS-POLOS-S-RED", "KAOS-POLOS-S-NAVY-BLUE",
+		"KAOS-POLOS-M-RED", "KAOS-POLOS-M-NAVY-BLUE",
+		"KAOS-POLOS-L-RED", "KAOS-POLOS-L-NAVY-BLUE",
+	}
+	for i, v := range variants {
+		if v.SKU != wantSKUs[i] {
+			t.Errorf("variant %d: expected SKU %s, got %s", i, wantSKUs[i], v.SKU)
+		}
+		if v.ID != uuid.Nil || v.Price != 75000 || !v.IsEnabled {
+			t.Errorf("variant %d: expected a new enabled variant priced 75000, got %+v", i, v)
+		}
+	}
+
+	if variants[3].Options["Size"] != "M" || variants[3].Options["Color"] != "Navy Blue" {
+		t.Errorf("unexpected options of variant 3: %v", variants[3].Options)
+	}
+}
+
+func TestBuildVariantsWithoutOptions(t *testing.T) {
+	variants := catalog.BuildVariants("tote-bag", nil, nil, 50000)
+
+	if len(variants) != 1 {
+		t.Fatalf("expected a single default variant, got %d", len(variants))
+	}
+	if variants[0].SKU != "TOTE-BAG" || len(variants[0].Options) != 0 {
+		t.Errorf("unexpected default variant: %+v", variants[0])
+	}
+}
+
+func TestBuildVariantsKeepsExisting(t *testing.T) {
+	barcode := "8991234567890"
+	existing := []catalog.ProductVariant{
+		{ID: uuid.New(), SKU: "CUSTOM-M-RED", Options: map[string]string{"Color": "Red", "Size": "M"}, Price: 80000, Barcode: &barcode, Stock: 12},
+		{ID: uuid.New(), SKU: "CUSTOM-XL-RED", Options: map[string]string{"Size": "XL", "Color": "Red"}, Price: 90000},
+	}
+
+	options := []catalog.ProductOption{
+		{Name: "Size", Values: []string{"M", "L"}},
+		{Name: "Color", Values: []string{"Red"}},
+	}
+
+	variants := catalog.BuildVariants("kaos-polos", options, existing, 75000)
+
+	if len(variants) != 2 {
+		t.Fatalf("expected 2 variants, got %d", len(variants))
+	}
+	if variants[0].ID != existing[0].ID || variants[0].SKU != "CUSTOM-M-RED" || variants[0].Stock != 12 || variants[0].IsEnabled {
+		t.Errorf("expected the existing M/Red variant to be kept unchanged, got %+v", variants[0])
+	}
+	if variants[1].ID != uuid.Nil || variants[1].SKU != "KAOS-POLOS-L-RED" || variants[1].Price != 75000 {
+		t.Errorf("expected a new L/Red variant, got %+v", variants[1])
+	}
+}
+
+func TestVariantKey(t *testing.T) {
+	a := catalog.VariantKey(map[string]string{"Size": "M", "Color": "Red"})
+	b := catalog.VariantKey(map[string]string{"Color": "Red", "Size": "M"})
+	c := catalog.VariantKey(map[string]string{"Size": "M", "Color": "Navy"})
+
+	if a != b {
+		t.Errorf("expected keys to ignore option order, got %q and %q", a, b)
+	}
+	if a == c {
+		t.Errorf("expected different combinations to have different keys")
+	}
+}
+
+func TestEnabledVariants(t *testing.T) {
+	p := catalog.Product{Variants: []catalog.ProductVariant{
+		{SKU: "A", IsEnabled: true},
+		{SKU: "B", IsEnabled: false},
+		{SKU: "C", IsEnabled: true},
+	}}
+
+	enabled := p.EnabledVariants()
+	if len(enabled) != 2 || enabled[0].SKU != "A" || enabled[1].SKU != "C" {
+		t.Errorf("expected variants A and C, got %+v", enabled)
+	}
+}
+
+func TestGenerateSKULength(t *testing.T) {
+	slug := strings.Repeat("kemeja-batik-", 20) + "lengan-panjang"
+
+	sku := catalog.GenerateSKU(slug, []string{"XL", "Merah"})
+
+	if len(sku) > catalog.MaxSKULength {
+		t.Errorf("expected at most %d characters, got %d", catalog.MaxSKULength, len(sku))
+	}
+	if !strings.HasSuffix(sku, "-XL-MERAH") || strings.HasPrefix(sku, "-") {
+		t.Errorf("expected the option values to be kept, got %q", sku)
+	}
+}
@@ -0,0 +1,42 @@
+package mailer_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yeftaz/susano.id/api/pkg/mailer"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	m := mailer.NewFileMailer("Susano <no-reply@susano.id>", dir)
+
+	err := m.Send(context.Background(), mailer.Message{
+		To:      "customer@example.com",
+		Subject: "Reset your password",
+		Body:    "https://susano.id/reset-password?token=abc",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 mail file, got %d", len(files))
+	}
+
+	content, _ := os.ReadFile(files[0])
+	for _, want := range []string{
+		"From: Susano <no-reply@susano.id>",
+		"To: customer@example.com",
+		"Subject: Reset your password",
+		"token=abc",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected mail to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxies    []string
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		want       string
+	}{
+		{"Remote Address", nil, "203.0.113.7:5000", "", "", "203.0.113.7"},
+		{"Untrusted Forwarded For", nil, "203.0.113.7:5000", "198.51.100.1", "", "203.0.113.7"},
+		{"Untrusted Real IP", nil, "203.0.113.7:5000", "", "198.51.100.1", "203.0.113.7"},
+		{"Trusted Forwarded For", []string{"10.0.0.0/8"}, "10.0.0.2:5000", "198.51.100.1", "", "198.51.100.1"},
+		{"Trusted Real IP", []string{"10.0.0.2"}, "10.0.0.2:5000", "", "198.51.100.1", "198.51.100.1"},
+		{"Spoofed Hop Before Proxy", []string{"10.0.0.0/8"}, "10.0.0.2:5000", "1.2.3.4, 198.51.100.1", "", "198.51.100.1"},
+		{"Chained Proxies", []string{"10.0.0.0/8"}, "10.0.0.2:5000", "198.51.100.1, 10.0.0.3", "", "198.51.100.1"},
+		{"Invalid Hop", []string{"10.0.0.0/8"}, "10.0.0.2:5000", "not-an-ip", "", "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			var got string
+			handler := middleware.RealIP(&config.Config{TrustedProxies: tt.proxies})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = middleware.ClientIP(r)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yeftaz/susano.id/api/internal/middleware"
+)
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+		wantOK bool
+	}{
+		{"Valid", "Bearer abc123", "abc123", true},
+		{"Lowercase Scheme", "bearer abc123", "abc123", true},
+		{"Missing", "", "", false},
+		{"Basic Scheme", "Basic dXNlcjpwYXNz", "", false},
+		{"Empty Token", "Bearer ", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			got, ok := middleware.BearerToken(req)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("BearerToken() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/yeftaz/susano.id/api/pkg/oidc"
+	"github.com/yeftaz/susano.id/api/pkg/oidc/oidctest"
+)
+
+const redirectURL = "http://localhost:3000/auth/oidc/mock/callback"
+
+func setupProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	server, err := oidctest.NewServer("susano", "secret")
+	if err != nil {
+		t.Fatalf("Failed to start mock provider: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	provider := oidc.New(oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "susano",
+		ClientSecret: "secret",
+		Scopes:       []string{"openid", "email", "profile"},
+		RedirectURL:  redirectURL,
+	})
+
+	return server, provider
+}
+
+// login runs the authorization code flow and returns the code the provider sent back
+func login(t *testing.T, server *oidctest.Server, provider *oidc.Provider, state, nonce, verifier string) string {
+	authURL, err := provider.AuthCodeURL(context.Background(), state, nonce, verifier)
+	if err != nil {
+		t.Fatalf("Failed to build authorization URL: %v", err)
+	}
+
+	code, returnedState, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Failed to authorize: %v", err)
+	}
+	if returnedState != state {
+		t.Fatalf("Expected state %q, got %q", state, returnedState)
+	}
+
+	return code
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	_, provider := setupProvider(t)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", oidc.GenerateVerifier())
+	if err != nil {
+		t.Fatalf("Failed to build authorization URL: %v", err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("Invalid authorization URL: %v", err)
+	}
+
+	q := parsed.Query()
+	for key, want := range map[string]string{
+		"client_id":             "susano",
+		"redirect_uri":          redirectURL,
+		"state":                 "state",
+		"nonce":                 "nonce",
+		"scope":                 "openid email profile",
+		"code_challenge_method": "S256",
+	} {
+		if got := q.Get(key); got != want {
+			t.Errorf("Expected %s %q, got %q", key, want, got)
+		}
+	}
+	if q.Get("code_challenge") == "" {
+		t.Error("Expected a PKCE code challenge")
+	}
+}
+
+func TestExchange(t *testing.T) {
+	server, provider := setupProvider(t)
+	server.SetUser(oidctest.User{Subject: "user-1", Email: "customer@example.com", EmailVerified: true, Name: "Test Customer"})
+
+	t.Run("Valid Code", func(t *testing.T) {
+		verifier := oidc.GenerateVerifier()
+		code := login(t, server, provider, "state", "nonce", verifier)
+
+		claims, err := provider.Exchange(context.Background(), code, verifier, "nonce")
+		if err != nil {
+			t.Fatalf("Expected exchange to succeed, got %v", err)
+		}
+
+		if claims.Subject != "user-1" || claims.Email != "customer@example.com" || !claims.EmailVerified || claims.Name != "Test Customer" {
+			t.Errorf("Unexpected claims: %+v", claims)
+		}
+	})
+
+	t.Run("Code Used Twice", func(t *testing.T) {
+		verifier := oidc.GenerateVerifier()
+		code := login(t, server, provider, "state", "nonce", verifier)
+
+		if _, err := provider.Exchange(context.Background(), code, verifier, "nonce"); err != nil {
+			t.Fatalf("Expected first exchange to succeed, got %v", err)
+		}
+		if _, err := provider.Exchange(context.Background(), code, verifier, "nonce"); !errors.Is(err, oidc.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("Wrong Verifier", func(t *testing.T) {
+		code := login(t, server, provider, "state", "nonce", oidc.GenerateVerifier())
+
+		if _, err := provider.Exchange(context.Background(), code, oidc.GenerateVerifier(), "nonce"); !errors.Is(err, oidc.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("Nonce Mismatch", func(t *testing.T) {
+		verifier := oidc.GenerateVerifier()
+		code := login(t, server, provider, "state", "nonce", verifier)
+
+		if _, err := provider.Exchange(context.Background(), code, verifier, "other-nonce"); !errors.Is(err, oidc.ErrNonceMismatch) {
+			t.Errorf("Expected ErrNonceMismatch, got %v", err)
+		}
+	})
+
+	t.Run("Other Client", func(t *testing.T) {
+		other := oidc.New(oidc.Config{
+			Issuer:       server.Issuer(),
+			ClientID:     "other",
+			ClientSecret: "secret",
+			RedirectURL:  redirectURL,
+		})
+
+		verifier := oidc.GenerateVerifier()
+		code := login(t, server, provider, "state", "nonce", verifier)
+
+		if _, err := other.Exchange(context.Background(), code, verifier, "nonce"); !errors.Is(err, oidc.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+}
+
+func TestUnreachableProvider(t *testing.T) {
+	server, provider := setupProvider(t)
+	server.Close()
+
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", oidc.GenerateVerifier()); err == nil {
+		t.Error("Expected an error when the provider cannot be discovered")
+	}
+}
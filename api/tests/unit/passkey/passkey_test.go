@@ -0,0 +1,281 @@
+package passkey_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+
+	"github.com/yeftaz/susano.id/api/pkg/passkey"
+)
+
+const (
+	rpID   = "admin.example.com"
+	origin = "https://admin.example.com"
+)
+
+// Authenticator data flags
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+// softAuthenticator is a software passkey authenticator with a single P-256 credential
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	counter      uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		t.Fatalf("Failed to generate credential ID: %v", err)
+	}
+
+	return &softAuthenticator{key: key, credentialID: credentialID}
+}
+
+// create answers navigator.credentials.create() with a "none" attestation
+func (a *softAuthenticator) create(t *testing.T, options *passkey.CreationOptions, origin string) []byte {
+	a.userHandle = options.Response.User.ID.(protocol.URLEncodedBase64)
+
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.PublicKey.X.FillBytes(x)
+	a.key.PublicKey.Y.FillBytes(y)
+
+	coseKey, err := webauthncbor.Marshal(map[int]interface{}{1: 2, 3: -7, -1: 1, -2: x, -3: y})
+	if err != nil {
+		t.Fatalf("Failed to encode public key: %v", err)
+	}
+
+	authData := a.authData(flagUserPresent | flagUserVerified | flagAttested)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, coseKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode attestation: %v", err)
+	}
+
+	clientData := a.clientData(t, "webauthn.create", options.Response.Challenge.String(), origin)
+
+	return a.marshal(t, map[string]string{
+		"clientDataJSON":    encode(clientData),
+		"attestationObject": encode(attestation),
+	})
+}
+
+// get answers navigator.credentials.get() with the given authenticator data flags
+func (a *softAuthenticator) get(t *testing.T, options *passkey.RequestOptions, origin string, flags byte) []byte {
+	a.counter++
+	authData := a.authData(flags)
+	clientData := a.clientData(t, "webauthn.get", options.Response.Challenge.String(), origin)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign assertion: %v", err)
+	}
+
+	return a.marshal(t, map[string]string{
+		"clientDataJSON":    encode(clientData),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(a.userHandle),
+	})
+}
+
+func (a *softAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.counter)
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, typ, challenge, origin string) []byte {
+	data, err := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": origin})
+	if err != nil {
+		t.Fatalf("Failed to encode client data: %v", err)
+	}
+	return data
+}
+
+func (a *softAuthenticator) marshal(t *testing.T, response map[string]string) []byte {
+	data, err := json.Marshal(map[string]interface{}{
+		"id":       encode(a.credentialID),
+		"rawId":    encode(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode credential: %v", err)
+	}
+	return data
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func register(t *testing.T, rp *passkey.RelyingParty, user *passkey.User, authenticator *softAuthenticator) *passkey.Credential {
+	options, session, err := rp.BeginRegistration(user)
+	if err != nil {
+		t.Fatalf("Failed to begin registration: %v", err)
+	}
+
+	credential, err := rp.FinishRegistration(user, session, authenticator.create(t, options, origin))
+	if err != nil {
+		t.Fatalf("Failed to finish registration: %v", err)
+	}
+
+	return credential
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	rp := passkey.New(rpID, "Susano Admin", []string{origin}, time.Minute)
+	user := &passkey.User{ID: []byte("admin-1"), Name: "admin@example.com", DisplayName: "Admin"}
+
+	credential := register(t, rp, user, newSoftAuthenticator(t))
+	if len(credential.ID) == 0 || len(credential.PublicKey) == 0 {
+		t.Fatal("Expected credential ID and public key to be returned")
+	}
+	if !credential.UserVerified {
+		t.Error("Expected credential to be user verified")
+	}
+
+	t.Run("Wrong Origin", func(t *testing.T) {
+		options, session, err := rp.BeginRegistration(user)
+		if err != nil {
+			t.Fatalf("Failed to begin registration: %v", err)
+		}
+
+		response := newSoftAuthenticator(t).create(t, options, "https://evil.example.com")
+		if _, err := rp.FinishRegistration(user, session, response); !errors.Is(err, passkey.ErrInvalidResponse) {
+			t.Errorf("Expected ErrInvalidResponse, got %v", err)
+		}
+	})
+}
+
+func TestPasskeySecondFactor(t *testing.T) {
+	rp := passkey.New(rpID, "Susano Admin", []string{origin}, time.Minute)
+	user := &passkey.User{ID: []byte("admin-1"), Name: "admin@example.com", DisplayName: "Admin"}
+	authenticator := newSoftAuthenticator(t)
+	user.Credentials = append(user.Credentials, *register(t, rp, user, authenticator))
+
+	options, session, err := rp.BeginLogin(user)
+	if err != nil {
+		t.Fatalf("Failed to begin login: %v", err)
+	}
+
+	credential, err := rp.FinishLogin(user, session, authenticator.get(t, options, origin, flagUserPresent))
+	if err != nil {
+		t.Fatalf("Expected login to succeed, got %v", err)
+	}
+	if credential.SignCount != authenticator.counter {
+		t.Errorf("Expected sign count %d, got %d", authenticator.counter, credential.SignCount)
+	}
+
+	t.Run("Cloned Authenticator", func(t *testing.T) {
+		// The stored counter is ahead of the one the authenticator reports
+		user.Credentials[0].SignCount = authenticator.counter + 10
+
+		options, session, err := rp.BeginLogin(user)
+		if err != nil {
+			t.Fatalf("Failed to begin login: %v", err)
+		}
+
+		if _, err := rp.FinishLogin(user, session, authenticator.get(t, options, origin, flagUserPresent)); !errors.Is(err, passkey.ErrCloned) {
+			t.Errorf("Expected ErrCloned, got %v", err)
+		}
+	})
+
+	t.Run("Unknown Passkey", func(t *testing.T) {
+		options, session, err := rp.BeginLogin(user)
+		if err != nil {
+			t.Fatalf("Failed to begin login: %v", err)
+		}
+
+		other := newSoftAuthenticator(t)
+		other.userHandle = user.ID
+		if _, err := rp.FinishLogin(user, session, other.get(t, options, origin, flagUserPresent)); !errors.Is(err, passkey.ErrInvalidResponse) {
+			t.Errorf("Expected ErrInvalidResponse, got %v", err)
+		}
+	})
+}
+
+func TestPasskeyPasswordlessLogin(t *testing.T) {
+	rp := passkey.New(rpID, "Susano Admin", []string{origin}, time.Minute)
+	user := &passkey.User{ID: []byte("admin-1"), Name: "admin@example.com", DisplayName: "Admin"}
+	authenticator := newSoftAuthenticator(t)
+	user.Credentials = append(user.Credentials, *register(t, rp, user, authenticator))
+
+	lookup := func(userHandle []byte) (*passkey.User, error) {
+		if string(userHandle) != string(user.ID) {
+			return nil, errors.New("unknown user")
+		}
+		return user, nil
+	}
+
+	options, session, err := rp.BeginPasswordlessLogin()
+	if err != nil {
+		t.Fatalf("Failed to begin login: %v", err)
+	}
+
+	found, _, err := rp.FinishPasswordlessLogin(session, authenticator.get(t, options, origin, flagUserPresent|flagUserVerified), lookup)
+	if err != nil {
+		t.Fatalf("Expected login to succeed, got %v", err)
+	}
+	if found != user {
+		t.Error("Expected the passkey owner to be returned")
+	}
+
+	t.Run("User Verification Required", func(t *testing.T) {
+		options, session, err := rp.BeginPasswordlessLogin()
+		if err != nil {
+			t.Fatalf("Failed to begin login: %v", err)
+		}
+
+		if _, _, err := rp.FinishPasswordlessLogin(session, authenticator.get(t, options, origin, flagUserPresent), lookup); !errors.Is(err, passkey.ErrInvalidResponse) {
+			t.Errorf("Expected ErrInvalidResponse without user verification, got %v", err)
+		}
+	})
+
+	t.Run("Challenge Mismatch", func(t *testing.T) {
+		options, _, err := rp.BeginPasswordlessLogin()
+		if err != nil {
+			t.Fatalf("Failed to begin login: %v", err)
+		}
+		_, otherSession, err := rp.BeginPasswordlessLogin()
+		if err != nil {
+			t.Fatalf("Failed to begin login: %v", err)
+		}
+
+		if _, _, err := rp.FinishPasswordlessLogin(otherSession, authenticator.get(t, options, origin, flagUserPresent|flagUserVerified), lookup); !errors.Is(err, passkey.ErrInvalidResponse) {
+			t.Errorf("Expected ErrInvalidResponse for another challenge, got %v", err)
+		}
+	})
+}
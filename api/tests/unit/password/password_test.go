@@ -0,0 +1,99 @@
+package password_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yeftaz/susano.id/api/pkg/password"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := password.Policy{
+		MinLength:        10,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		problems int
+	}{
+		{"Strong", "Correct-Horse-7", 0},
+		{"Too Short", "Sh0rt!pw", 1},
+		{"No Uppercase", "correct-horse-7", 1},
+		{"No Lowercase", "CORRECT-HORSE-7", 1},
+		{"No Digit", "Correct-Horse-X", 1},
+		{"No Symbol", "CorrectHorse77", 1},
+		{"Only Lowercase", "correcthorse", 3},
+		{"Too Long", "Aa1!" + strings.Repeat("x", password.MaxLength), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if problems := policy.Check(tt.password); len(problems) != tt.problems {
+				t.Errorf("Expected %d problems, got %v", tt.problems, problems)
+			}
+		})
+	}
+}
+
+func TestPolicyPersonalDetails(t *testing.T) {
+	policy := password.Policy{MinLength: 8}
+
+	tests := []struct {
+		name     string
+		password string
+		want     bool
+	}{
+		{"Email Local Part", "Jane.Doe-2024!", true},
+		{"Name Word", "i-am-SUSANO-99", true},
+		{"Unrelated", "Correct-Horse-7", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := policy.Check(tt.password, "jane.doe@example.com", "Budi Susano")
+			if got := len(problems) > 0; got != tt.want {
+				t.Errorf("Expected personal details match %v, got %v", tt.want, problems)
+			}
+		})
+	}
+}
+
+func TestBreachList(t *testing.T) {
+	dir := t.TempDir()
+
+	// Range file of "password123" next to an unrelated suffix, as served by the range API
+	sum := sha1.Sum([]byte("password123"))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	content := "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n" + hash[5:] + ":251682\r\n"
+	if err := os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write range file: %v", err)
+	}
+
+	list, err := password.OpenBreachList(dir)
+	if err != nil {
+		t.Fatalf("Failed to open breach list: %v", err)
+	}
+
+	breached, err := list.Contains("password123")
+	if err != nil || !breached {
+		t.Errorf("Expected password123 to be breached, got %v, %v", breached, err)
+	}
+
+	breached, err = list.Contains("Correct-Horse-7")
+	if err != nil || breached {
+		t.Errorf("Expected an unknown password not to be breached, got %v, %v", breached, err)
+	}
+
+	if _, err := password.OpenBreachList(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected opening a missing directory to fail")
+	}
+}
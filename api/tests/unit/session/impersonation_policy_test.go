@@ -0,0 +1,39 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain/store"
+	storeService "github.com/yeftaz/susano.id/api/internal/service/store"
+)
+
+func TestImpersonationSessionPolicy(t *testing.T) {
+	cfg := &config.Config{
+		SessionTokenKeys:           []string{"test-key"},
+		CustomerSessionIdleTimeout: 336 * time.Hour,
+		CustomerSessionLifetime:    720 * time.Hour,
+		ImpersonationLifetime:      30 * time.Minute,
+	}
+	authService := storeService.NewAuthService(nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+	customer := &store.Customer{}
+
+	policy := authService.SessionPolicy(customer, &store.Session{})
+	if policy.Lifetime != cfg.CustomerSessionLifetime {
+		t.Errorf("Expected customer lifetime %s, got %s", cfg.CustomerSessionLifetime, policy.Lifetime)
+	}
+
+	adminID := uuid.New()
+	policy = authService.SessionPolicy(customer, &store.Session{ImpersonatorID: &adminID})
+	if policy.Lifetime != 30*time.Minute || policy.IdleTimeout != 30*time.Minute {
+		t.Errorf("Expected impersonation to be capped at 30m, got lifetime %s and idle timeout %s", policy.Lifetime, policy.IdleTimeout)
+	}
+
+	now := time.Now()
+	if !policy.IsExpired(now.Add(-31*time.Minute), now) {
+		t.Error("Expected impersonation session past its lifetime to be expired")
+	}
+}
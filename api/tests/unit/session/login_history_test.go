@@ -0,0 +1,25 @@
+package session_test
+
+import (
+	"testing"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+)
+
+func TestLoginHistoryDeviceLabel(t *testing.T) {
+	tests := []struct {
+		entry shared.LoginHistory
+		want  string
+	}{
+		{shared.LoginHistory{Browser: "Chrome", OS: "Windows 10"}, "Chrome on Windows 10"},
+		{shared.LoginHistory{Browser: "curl"}, "curl"},
+		{shared.LoginHistory{OS: "Android 14"}, "Android 14"},
+		{shared.LoginHistory{}, "Unknown device"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.entry.DeviceLabel(); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+)
+
+func TestLoginThrottlePolicy(t *testing.T) {
+	policy := shared.LoginThrottlePolicy{
+		MaxAttempts:     5,
+		LockoutDuration: 15 * time.Minute,
+		BaseDelay:       time.Second,
+		MaxDelay:        30 * time.Second,
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		count  int
+		want   time.Duration
+		locked bool
+	}{
+		{"No Failures", 0, 0, false},
+		{"First Failure", 1, time.Second, false},
+		{"Third Failure Doubles", 3, 4 * time.Second, false},
+		{"Locked Out", 5, 15 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := shared.LoginFailures{Count: tt.count, LastAt: now}
+
+			if got := policy.RetryAfter(failures, now); got != tt.want {
+				t.Errorf("RetryAfter() = %v, want %v", got, tt.want)
+			}
+			if got := policy.IsLocked(failures); got != tt.locked {
+				t.Errorf("IsLocked() = %v, want %v", got, tt.locked)
+			}
+		})
+	}
+}
+
+func TestLoginThrottlePolicyMaxDelay(t *testing.T) {
+	policy := shared.LoginThrottlePolicy{BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+	now := time.Now()
+
+	if got := policy.RetryAfter(shared.LoginFailures{Count: 20, LastAt: now}, now); got != 30*time.Second {
+		t.Errorf("RetryAfter() = %v, want %v", got, 30*time.Second)
+	}
+}
+
+func TestLoginThrottlePolicyDelayElapsed(t *testing.T) {
+	policy := shared.LoginThrottlePolicy{MaxAttempts: 5, LockoutDuration: 15 * time.Minute, BaseDelay: time.Second}
+	now := time.Now()
+
+	if got := policy.RetryAfter(shared.LoginFailures{Count: 5, LastAt: now.Add(-16 * time.Minute)}, now); got != 0 {
+		t.Errorf("Expected lockout to be over, got RetryAfter() = %v", got)
+	}
+}
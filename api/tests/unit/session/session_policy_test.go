@@ -0,0 +1,85 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+)
+
+func TestSessionPolicyIdleTimeout(t *testing.T) {
+	policy := shared.SessionPolicy{IdleTimeout: 10 * time.Minute, Lifetime: 12 * time.Hour}
+	now := time.Now()
+
+	if policy.IsExpired(now.Add(-time.Hour), now.Add(-5*time.Minute)) {
+		t.Error("Expected recently active session to be valid")
+	}
+	if !policy.IsExpired(now.Add(-time.Hour), now.Add(-11*time.Minute)) {
+		t.Error("Expected session idle past the timeout to be expired")
+	}
+}
+
+func TestSessionPolicyLifetime(t *testing.T) {
+	policy := shared.SessionPolicy{IdleTimeout: 10 * time.Minute, Lifetime: 12 * time.Hour}
+	now := time.Now()
+
+	// Active a moment ago, but logged in longer than the lifetime
+	if !policy.IsExpired(now.Add(-13*time.Hour), now.Add(-time.Minute)) {
+		t.Error("Expected session past its absolute lifetime to be expired")
+	}
+}
+
+func TestSessionPolicyExpiresAt(t *testing.T) {
+	policy := shared.SessionPolicy{IdleTimeout: time.Hour, Lifetime: 24 * time.Hour}
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Idle timeout comes first
+	if got, want := policy.ExpiresAt(created, created.Add(2*time.Hour)), created.Add(3*time.Hour); !got.Equal(want) {
+		t.Errorf("ExpiresAt() = %v, want %v", got, want)
+	}
+
+	// Lifetime comes first
+	if got, want := policy.ExpiresAt(created, created.Add(23*time.Hour+30*time.Minute)), created.Add(24*time.Hour); !got.Equal(want) {
+		t.Errorf("ExpiresAt() = %v, want %v", got, want)
+	}
+}
+
+func TestSessionPolicyCookieMaxAge(t *testing.T) {
+	policy := shared.SessionPolicy{IdleTimeout: 10 * time.Minute, Lifetime: 12 * time.Hour}
+	now := time.Now()
+
+	// A new session keeps its cookie for the absolute lifetime, not the idle timeout
+	if got := policy.CookieMaxAge(now); got < int((12*time.Hour-time.Minute).Seconds()) || got > int((12*time.Hour).Seconds()) {
+		t.Errorf("CookieMaxAge() of a new session = %d, want about 12h", got)
+	}
+
+	// Only the rest of the lifetime is left for an older session
+	if got := policy.CookieMaxAge(now.Add(-11 * time.Hour)); got > int(time.Hour.Seconds()) {
+		t.Errorf("CookieMaxAge() of an 11h old session = %d, want at most 1h", got)
+	}
+
+	if got := policy.CookieMaxAge(now.Add(-13 * time.Hour)); got != -1 {
+		t.Errorf("CookieMaxAge() past the lifetime = %d, want -1", got)
+	}
+
+	if got := (shared.SessionPolicy{IdleTimeout: time.Hour}).CookieMaxAge(now); got != 0 {
+		t.Errorf("CookieMaxAge() without a lifetime = %d, want 0", got)
+	}
+}
+
+func TestSessionPolicyRefreshInterval(t *testing.T) {
+	tests := []struct {
+		idle time.Duration
+		want time.Duration
+	}{
+		{10 * time.Minute, time.Minute},
+		{336 * time.Hour, 15 * time.Minute},
+		{0, 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := (shared.SessionPolicy{IdleTimeout: tt.idle}).RefreshInterval(); got != tt.want {
+			t.Errorf("RefreshInterval() with idle %v = %v, want %v", tt.idle, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package signer_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/pkg/signer"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	s := signer.New("test-key")
+
+	signed := s.Sign(url.Values{"id": {"42"}}, time.Now().Add(time.Hour))
+	if err := s.Verify(signed); err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+
+	// Tampered value
+	signed.Set("id", "43")
+	if err := s.Verify(signed); !errors.Is(err, signer.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestVerifyWithOtherKey(t *testing.T) {
+	signed := signer.New("test-key").Sign(url.Values{"id": {"42"}}, time.Now().Add(time.Hour))
+
+	if err := signer.New("other-key").Verify(signed); !errors.Is(err, signer.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	s := signer.New("test-key")
+
+	signed := s.Sign(url.Values{"id": {"42"}}, time.Now().Add(-time.Minute))
+	if err := s.Verify(signed); !errors.Is(err, signer.ErrExpiredSignature) {
+		t.Errorf("Expected ErrExpiredSignature, got %v", err)
+	}
+}
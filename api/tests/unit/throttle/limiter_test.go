@@ -0,0 +1,56 @@
+package throttle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/pkg/throttle"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Run("Allows Up To Limit", func(t *testing.T) {
+		limiter := throttle.NewLimiter(time.Minute)
+
+		for i := 0; i < 3; i++ {
+			if ok, _ := limiter.Allow("key", 3); !ok {
+				t.Fatalf("Expected request %d to be allowed", i+1)
+			}
+		}
+
+		ok, wait := limiter.Allow("key", 3)
+		if ok {
+			t.Fatal("Expected request over the limit to be refused")
+		}
+		if wait <= 0 || wait > time.Minute {
+			t.Errorf("Expected wait within the window, got %v", wait)
+		}
+	})
+
+	t.Run("Keys Are Independent", func(t *testing.T) {
+		limiter := throttle.NewLimiter(time.Minute)
+
+		if ok, _ := limiter.Allow("a", 1); !ok {
+			t.Fatal("Expected first request of a to be allowed")
+		}
+		if ok, _ := limiter.Allow("b", 1); !ok {
+			t.Error("Expected first request of b to be allowed")
+		}
+	})
+
+	t.Run("Window Resets", func(t *testing.T) {
+		limiter := throttle.NewLimiter(20 * time.Millisecond)
+
+		if ok, _ := limiter.Allow("key", 1); !ok {
+			t.Fatal("Expected first request to be allowed")
+		}
+		if ok, _ := limiter.Allow("key", 1); ok {
+			t.Fatal("Expected second request to be refused")
+		}
+
+		time.Sleep(30 * time.Millisecond)
+
+		if ok, _ := limiter.Allow("key", 1); !ok {
+			t.Error("Expected request in a new window to be allowed")
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package tokenhash_test
+
+import (
+	"testing"
+
+	"github.com/yeftaz/susano.id/api/pkg/tokenhash"
+)
+
+func TestHashIsKeyed(t *testing.T) {
+	a := tokenhash.New([]string{"key-a"}).Hash("token")
+	b := tokenhash.New([]string{"key-b"}).Hash("token")
+
+	if a == b {
+		t.Error("Expected hashes under different keys to differ")
+	}
+	if a == "token" || len(a) != 64 {
+		t.Errorf("Expected a 64 character hex digest, got %q", a)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	old := tokenhash.New([]string{"key-a"})
+	rotated := tokenhash.New([]string{"key-b", "key-a"})
+
+	stored := old.Hash("token")
+
+	// Old hash is still accepted after rotation
+	found := false
+	for _, candidate := range rotated.Candidates("token") {
+		if candidate == stored {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected hash made with the old key to be a candidate")
+	}
+
+	if rotated.IsCurrent("token", stored) {
+		t.Error("Expected hash made with the old key not to be current")
+	}
+	if !rotated.IsCurrent("token", rotated.Hash("token")) {
+		t.Error("Expected hash made with the first key to be current")
+	}
+}
@@ -0,0 +1,83 @@
+package twofactor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yeftaz/susano.id/api/pkg/twofactor"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := twofactor.GenerateRecoveryCodes(twofactor.RecoveryCodeCount)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(codes) != twofactor.RecoveryCodeCount {
+		t.Fatalf("Expected %d codes, got %d", twofactor.RecoveryCodeCount, len(codes))
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("Unexpected code format: %s", code)
+		}
+		if seen[code] {
+			t.Errorf("Duplicate code generated: %s", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestConsumeRecoveryCode(t *testing.T) {
+	codes, _ := twofactor.GenerateRecoveryCodes(3)
+	encoded, err := twofactor.EncodeRecoveryCodes(twofactor.HashRecoveryCodes(codes))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if strings.Contains(encoded, codes[0]) {
+		t.Fatal("Expected plain codes not to be stored")
+	}
+
+	hashes := twofactor.DecodeRecoveryCodes(&encoded)
+
+	t.Run("Valid Code Is Burned", func(t *testing.T) {
+		remaining, ok := twofactor.ConsumeRecoveryCode(hashes, codes[1])
+		if !ok {
+			t.Fatal("Expected code to be accepted")
+		}
+
+		if len(remaining) != 2 {
+			t.Errorf("Expected 2 remaining codes, got %d", len(remaining))
+		}
+
+		if _, ok := twofactor.ConsumeRecoveryCode(remaining, codes[1]); ok {
+			t.Error("Expected burned code to be rejected")
+		}
+	})
+
+	t.Run("Normalized Input", func(t *testing.T) {
+		input := " " + strings.ToUpper(strings.ReplaceAll(codes[0], "-", "")) + " "
+		if _, ok := twofactor.ConsumeRecoveryCode(hashes, input); !ok {
+			t.Error("Expected code without dash and in uppercase to be accepted")
+		}
+	})
+
+	t.Run("Unknown Code", func(t *testing.T) {
+		remaining, ok := twofactor.ConsumeRecoveryCode(hashes, "aaaaa-aaaaa")
+		if ok {
+			t.Error("Expected unknown code to be rejected")
+		}
+
+		if len(remaining) != 3 {
+			t.Errorf("Expected codes to be untouched, got %d", len(remaining))
+		}
+	})
+
+	t.Run("Empty Storage", func(t *testing.T) {
+		if got := twofactor.DecodeRecoveryCodes(nil); got != nil {
+			t.Errorf("Expected nil, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package twofactor_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yeftaz/susano.id/api/pkg/twofactor"
+)
+
+// rfcSecret is the SHA1 test key from RFC 6238 Appendix B ("12345678901234567890")
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerateCode(t *testing.T) {
+	// RFC 6238 test vectors, truncated to 6 digits
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, v := range vectors {
+		code, err := twofactor.GenerateCode(rfcSecret, time.Unix(v.unix, 0))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if code != v.code {
+			t.Errorf("At %d expected code %s, got %s", v.unix, v.code, code)
+		}
+	}
+}
+
+func TestValidateAt(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	t.Run("Current Step", func(t *testing.T) {
+		if !twofactor.ValidateAt("005924", rfcSecret, now) {
+			t.Error("Expected code for current step to be valid")
+		}
+	})
+
+	t.Run("Adjacent Step", func(t *testing.T) {
+		code, _ := twofactor.GenerateCode(rfcSecret, now.Add(-30*time.Second))
+		if !twofactor.ValidateAt(code, rfcSecret, now) {
+			t.Error("Expected code for previous step to be valid")
+		}
+	})
+
+	t.Run("Outside Skew", func(t *testing.T) {
+		code, _ := twofactor.GenerateCode(rfcSecret, now.Add(-90*time.Second))
+		if twofactor.ValidateAt(code, rfcSecret, now) {
+			t.Error("Expected code three steps old to be invalid")
+		}
+	})
+
+	t.Run("Malformed Code", func(t *testing.T) {
+		if twofactor.ValidateAt("12345", rfcSecret, now) {
+			t.Error("Expected short code to be invalid")
+		}
+	})
+}
+
+func TestMatchAt(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / 30
+
+	t.Run("Current Step", func(t *testing.T) {
+		step, ok := twofactor.MatchAt("005924", rfcSecret, now)
+		if !ok || step != current {
+			t.Errorf("Expected step %d, got %d (valid %v)", current, step, ok)
+		}
+	})
+
+	t.Run("Adjacent Step", func(t *testing.T) {
+		code, _ := twofactor.GenerateCode(rfcSecret, now.Add(30*time.Second))
+		step, ok := twofactor.MatchAt(code, rfcSecret, now)
+		if !ok || step != current+1 {
+			t.Errorf("Expected step %d, got %d (valid %v)", current+1, step, ok)
+		}
+	})
+}
+
+func TestGenerateSecretAndURI(t *testing.T) {
+	secret, err := twofactor.GenerateSecret()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(secret) != 32 {
+		t.Errorf("Expected 32 character secret, got %d", len(secret))
+	}
+
+	uri := twofactor.URI("Susano", "admin@susano.id", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Susano:admin@susano.id?") {
+		t.Errorf("Unexpected URI: %s", uri)
+	}
+
+	if !strings.Contains(uri, "secret="+secret) {
+		t.Errorf("Expected URI to contain secret, got %s", uri)
+	}
+}
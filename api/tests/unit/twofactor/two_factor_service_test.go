@@ -0,0 +1,208 @@
+package twofactor_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yeftaz/susano.id/api/internal/config"
+	"github.com/yeftaz/susano.id/api/internal/domain"
+	"github.com/yeftaz/susano.id/api/internal/domain/shared"
+	sharedService "github.com/yeftaz/susano.id/api/internal/service/shared"
+	"github.com/yeftaz/susano.id/api/pkg/twofactor"
+)
+
+// memoryAccounts keeps a single account in memory the way the account repositories store it
+type memoryAccounts struct {
+	account           shared.TwoFactorAccount
+	lastStep          *int64
+	challengesDeleted int
+}
+
+func (m *memoryAccounts) FindTwoFactorAccount(ctx context.Context, id string) (*shared.TwoFactorAccount, error) {
+	account := m.account
+	return &account, nil
+}
+
+func (m *memoryAccounts) UpdatePendingTwoFactorSecret(ctx context.Context, id, secret string) error {
+	m.account.PendingSecret = &secret
+	return nil
+}
+
+func (m *memoryAccounts) ConfirmTwoFactor(ctx context.Context, id, pendingSecret, recoveryCodes string, step int64) error {
+	if m.account.PendingSecret == nil || *m.account.PendingSecret != pendingSecret {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	m.account.Secret, m.account.PendingSecret = m.account.PendingSecret, nil
+	m.account.RecoveryCodes = &recoveryCodes
+	m.account.ConfirmedAt = &now
+	m.lastStep = &step
+	return nil
+}
+
+func (m *memoryAccounts) UseTwoFactorStep(ctx context.Context, id string, step int64) error {
+	if m.lastStep != nil && step <= *m.lastStep {
+		return sql.ErrNoRows
+	}
+	m.lastStep = &step
+	return nil
+}
+
+func (m *memoryAccounts) DisableTwoFactor(ctx context.Context, id string) error {
+	m.account.Secret, m.account.PendingSecret, m.account.RecoveryCodes, m.account.ConfirmedAt = nil, nil, nil, nil
+	m.lastStep = nil
+	return nil
+}
+
+func (m *memoryAccounts) UpdateRecoveryCodes(ctx context.Context, id string, current *string, recoveryCodes string) error {
+	m.account.RecoveryCodes = &recoveryCodes
+	return nil
+}
+
+func (m *memoryAccounts) DeleteTwoFactorChallenges(ctx context.Context, id uuid.UUID) error {
+	m.challengesDeleted++
+	return nil
+}
+
+func currentCode(t *testing.T, secret string) string {
+	return codeAt(t, secret, time.Now())
+}
+
+// nextCode returns the code of the next time step, which is still accepted after the current
+// step was used to confirm the secret
+func nextCode(t *testing.T, secret string) string {
+	return codeAt(t, secret, time.Now().Add(30*time.Second))
+}
+
+func codeAt(t *testing.T, secret string, at time.Time) string {
+	code, err := twofactor.GenerateCode(secret, at)
+	if err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+	return code
+}
+
+func TestTwoFactorService(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{TwoFactorIssuer: "Susano"}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password1234"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+
+	enroll := func(t *testing.T) (*memoryAccounts, *sharedService.TwoFactorService) {
+		accounts := &memoryAccounts{account: shared.TwoFactorAccount{ID: uuid.New(), Email: "user@susano.id", Password: string(hash)}}
+		service := sharedService.NewTwoFactorService(accounts, cfg)
+
+		setup, err := service.Setup(ctx, accounts.account.ID.String())
+		if err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+		if accounts.account.ConfirmedAt != nil {
+			t.Fatal("Expected two-factor to stay off until confirmed")
+		}
+		if _, err := service.Confirm(ctx, accounts.account.ID.String(), currentCode(t, setup.Secret)); err != nil {
+			t.Fatalf("Confirm failed: %v", err)
+		}
+		return accounts, service
+	}
+
+	t.Run("Confirm Enables Pending Secret", func(t *testing.T) {
+		accounts, _ := enroll(t)
+
+		if accounts.account.ConfirmedAt == nil || accounts.account.Secret == nil {
+			t.Fatal("Expected two-factor to be enabled")
+		}
+		if accounts.account.PendingSecret != nil {
+			t.Error("Expected the pending secret to be cleared")
+		}
+	})
+
+	t.Run("Regenerate Keeps Current Secret Until Confirmed", func(t *testing.T) {
+		accounts, service := enroll(t)
+		current := *accounts.account.Secret
+		id := accounts.account.ID.String()
+
+		setup, err := service.Regenerate(ctx, id, "password1234", nextCode(t, current))
+		if err != nil {
+			t.Fatalf("Regenerate failed: %v", err)
+		}
+
+		if accounts.account.ConfirmedAt == nil || *accounts.account.Secret != current {
+			t.Fatal("Expected the current secret to stay enforced after regenerating")
+		}
+
+		deleted := accounts.challengesDeleted
+		if _, err := service.Confirm(ctx, id, currentCode(t, setup.Secret)); err != nil {
+			t.Fatalf("Confirm failed: %v", err)
+		}
+
+		if *accounts.account.Secret != setup.Secret {
+			t.Error("Expected the regenerated secret to be swapped in")
+		}
+		if accounts.challengesDeleted != deleted+1 {
+			t.Error("Expected pending challenges to be deleted on confirm")
+		}
+	})
+
+	t.Run("Confirm Without Pending Secret", func(t *testing.T) {
+		accounts, service := enroll(t)
+
+		_, err := service.Confirm(ctx, accounts.account.ID.String(), currentCode(t, *accounts.account.Secret))
+		if !errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+			t.Errorf("Expected ErrTwoFactorAlreadyEnabled, got %v", err)
+		}
+	})
+
+	t.Run("Confirm Rejects Wrong Code", func(t *testing.T) {
+		accounts, service := enroll(t)
+		current := *accounts.account.Secret
+		id := accounts.account.ID.String()
+
+		if _, err := service.Regenerate(ctx, id, "password1234", nextCode(t, current)); err != nil {
+			t.Fatalf("Regenerate failed: %v", err)
+		}
+
+		// A code of the current secret does not confirm the new one
+		_, err := service.Confirm(ctx, id, currentCode(t, current))
+		if !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			t.Errorf("Expected ErrInvalidTwoFactorCode, got %v", err)
+		}
+		if *accounts.account.Secret != current {
+			t.Error("Expected the current secret to stay enforced")
+		}
+	})
+
+	t.Run("Code Works Once", func(t *testing.T) {
+		accounts, service := enroll(t)
+		code := nextCode(t, *accounts.account.Secret)
+		id := accounts.account.ID.String()
+
+		if _, err := service.Regenerate(ctx, id, "password1234", code); err != nil {
+			t.Fatalf("Regenerate failed: %v", err)
+		}
+
+		// Replaying the code while it is still valid is refused
+		_, err := service.Regenerate(ctx, id, "password1234", code)
+		if !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			t.Errorf("Expected ErrInvalidTwoFactorCode, got %v", err)
+		}
+	})
+
+	t.Run("Confirming Code Is Used Up", func(t *testing.T) {
+		accounts, service := enroll(t)
+
+		// The code that confirmed the secret cannot be used again
+		err := service.Disable(ctx, accounts.account.ID.String(), "password1234", currentCode(t, *accounts.account.Secret))
+		if !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			t.Errorf("Expected ErrInvalidTwoFactorCode, got %v", err)
+		}
+	})
+}